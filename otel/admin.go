@@ -0,0 +1,268 @@
+package otel
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel/sdk/trace"
+)
+
+// samplingState is the sampler used by a dynamicSampler along with
+// the ratio it was built from, a negative ratio being the output default.
+type samplingState struct {
+	sampler trace.Sampler
+	ratio   float64
+}
+
+// dynamicSampler delegates to a sampler that can be replaced at runtime.
+type dynamicSampler struct {
+	mu      sync.Mutex
+	base    trace.Sampler
+	current atomic.Pointer[samplingState]
+}
+
+func newDynamicSampler() *dynamicSampler {
+	s := &dynamicSampler{base: trace.ParentBased(trace.AlwaysSample())}
+	s.current.Store(&samplingState{sampler: s.base, ratio: -1})
+
+	return s
+}
+
+// setBase replaces the output default sampler.
+func (s *dynamicSampler) setBase(base trace.Sampler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.base = base
+	if s.current.Load().ratio < 0 {
+		s.current.Store(&samplingState{sampler: base, ratio: -1})
+	}
+}
+
+// setRatio samples ratio of the traces not sampled by a parent span,
+// a negative ratio restores the output default sampler.
+func (s *dynamicSampler) setRatio(ratio float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if ratio < 0 {
+		s.current.Store(&samplingState{sampler: s.base, ratio: -1})
+		return
+	}
+
+	s.current.Store(&samplingState{
+		sampler: trace.ParentBased(ConsistentProbabilitySampler(ratio)),
+		ratio:   ratio,
+	})
+}
+
+func (s *dynamicSampler) ratio() float64 {
+	return s.current.Load().ratio
+}
+
+// ShouldSample implements the trace.Sampler interface.
+func (s *dynamicSampler) ShouldSample(p trace.SamplingParameters) trace.SamplingResult {
+	return s.current.Load().sampler.ShouldSample(p)
+}
+
+// Description implements the trace.Sampler interface.
+func (s *dynamicSampler) Description() string {
+	return fmt.Sprintf("DynamicSampler{%s}", s.current.Load().sampler.Description())
+}
+
+// debugProcessor writes a line per ended span while enabled.
+type debugProcessor struct {
+	enabled atomic.Bool
+	writer  io.Writer
+}
+
+// OnStart implements the trace.SpanProcessor interface.
+func (p *debugProcessor) OnStart(context.Context, trace.ReadWriteSpan) {}
+
+// OnEnd implements the trace.SpanProcessor interface.
+func (p *debugProcessor) OnEnd(s trace.ReadOnlySpan) {
+	if !p.enabled.Load() {
+		return
+	}
+
+	writer := p.writer
+	if writer == nil {
+		writer = os.Stderr
+	}
+	fmt.Fprintf(writer, "debug: span %q trace_id=%s span_id=%s duration=%s status=%s\n",
+		s.Name(), s.SpanContext().TraceID(), s.SpanContext().SpanID(),
+		s.EndTime().Sub(s.StartTime()), s.Status().Code)
+}
+
+// Shutdown implements the trace.SpanProcessor interface.
+func (p *debugProcessor) Shutdown(context.Context) error {
+	return nil
+}
+
+// ForceFlush implements the trace.SpanProcessor interface.
+func (p *debugProcessor) ForceFlush(context.Context) error {
+	return nil
+}
+
+// providerOptions returns the provider options backing the runtime toggles,
+// base is the sampler of the output used until a sampling ratio is set,
+// unless Config.Sampler replaces it.
+func (p *pipeline) providerOptions(base trace.Sampler) []trace.TracerProviderOption {
+	if p.config.Sampler != nil {
+		base = p.config.Sampler
+	}
+	p.sampler.setBase(base)
+	if p.config.Writer != nil {
+		p.debug.writer = &syncWriter{w: p.config.Writer}
+	}
+
+	var sampler trace.Sampler = p.sampler
+	if p.spanMetrics {
+		sampler = recordOnlySampler{next: sampler}
+	}
+	p.drops = newDropRules(sampler, p.config.DropSpans)
+	sampler = p.drops
+	if p.health.memory != nil {
+		sampler = memoryLimitSampler{next: sampler, limiter: p.health.memory}
+	}
+	sampler = deploymentSampler{next: enabledSampler{next: sampler}}
+
+	opts := []trace.TracerProviderOption{
+		trace.WithSampler(sampler),
+		p.register(p.debug),
+		p.register(p.counter),
+	}
+	if p.spanMetrics {
+		opts = append(opts, p.register(p.red))
+	}
+	if p.zpages {
+		opts = append(opts, p.register(p.tracez))
+	}
+	if p.traceViewer {
+		opts = append(opts, p.register(p.viewer))
+	}
+
+	return opts
+}
+
+// AdminHealth is the pipeline state reported by the admin handler.
+// Sampler describes the sampler in use, SamplingRatio is only set along a
+// ratio set through the handler or a reloaded configuration, and
+// TracingDisabled while SetEnabled(false) is in effect.
+type AdminHealth struct {
+	Status          string   `json:"status"`
+	Sampler         string   `json:"sampler"`
+	SamplingRatio   *float64 `json:"sampling_ratio,omitempty"`
+	DebugSpans      bool     `json:"debug_spans"`
+	TracingDisabled bool     `json:"tracing_disabled,omitempty"`
+}
+
+// AdminHandler returns a handler changing the pipeline built by e at runtime,
+// mount it on an existing mux with http.StripPrefix:
+//
+//	mux.Handle("/otel/", http.StripPrefix("/otel", otel.AdminHandler(exporter, authorize)))
+//
+// It serves the following endpoints, all replying with the AdminHealth of the pipeline:
+//
+//	GET  /health                  current state, "ok" once ExportPipeline succeeded
+//	POST /sampling?ratio=0.25     samples a ratio of the root spans, "default" restores the output sampler
+//	POST /debug?enabled=true      writes ended spans to Config.Writer or stderr
+//	POST /tracing?enabled=false   stops producing spans in every pipeline, see SetEnabled
+//	POST /flush                   flushes the spans pending export
+//
+// Every request must be accepted by authorize, a nil authorize rejects them all.
+func AdminHandler(e Exporter, authorize func(*http.Request) bool) http.Handler {
+	p, _ := pipelineOf(e)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /health", func(w http.ResponseWriter, r *http.Request) {
+		writeAdminHealth(w, p)
+	})
+	mux.HandleFunc("POST /sampling", func(w http.ResponseWriter, r *http.Request) {
+		value := r.FormValue("ratio")
+		if value == "default" {
+			p.sampler.setRatio(-1)
+			writeAdminHealth(w, p)
+			return
+		}
+
+		ratio, err := strconv.ParseFloat(value, 64)
+		if err != nil || ratio < 0 || ratio > 1 {
+			http.Error(w, "ratio must be between 0 and 1 or default", http.StatusBadRequest)
+			return
+		}
+		p.sampler.setRatio(ratio)
+		writeAdminHealth(w, p)
+	})
+	mux.HandleFunc("POST /debug", func(w http.ResponseWriter, r *http.Request) {
+		enabled, err := strconv.ParseBool(r.FormValue("enabled"))
+		if err != nil {
+			http.Error(w, "enabled must be a boolean", http.StatusBadRequest)
+			return
+		}
+		p.debug.enabled.Store(enabled)
+		writeAdminHealth(w, p)
+	})
+	mux.HandleFunc("POST /tracing", func(w http.ResponseWriter, r *http.Request) {
+		enabled, err := strconv.ParseBool(r.FormValue("enabled"))
+		if err != nil {
+			http.Error(w, "enabled must be a boolean", http.StatusBadRequest)
+			return
+		}
+		SetEnabled(enabled)
+		writeAdminHealth(w, p)
+	})
+	mux.HandleFunc("POST /flush", func(w http.ResponseWriter, r *http.Request) {
+		provider := p.current()
+		if provider == nil {
+			http.Error(w, "pipeline not started", http.StatusServiceUnavailable)
+			return
+		}
+		if err := provider.ForceFlush(r.Context()); err != nil {
+			http.Error(w, fmt.Sprintf("could not flush provider: %v", err), http.StatusInternalServerError)
+			return
+		}
+		writeAdminHealth(w, p)
+	})
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if authorize == nil || !authorize(r) {
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+		if p == nil {
+			http.Error(w, "unsupported exporter", http.StatusNotImplemented)
+			return
+		}
+
+		mux.ServeHTTP(w, r)
+	})
+}
+
+func writeAdminHealth(w http.ResponseWriter, p *pipeline) {
+	started := p.current() != nil
+	sampling := p.sampler.current.Load()
+
+	health := AdminHealth{
+		Status:          "not started",
+		Sampler:         sampling.sampler.Description(),
+		DebugSpans:      p.debug.enabled.Load(),
+		TracingDisabled: !Enabled(),
+	}
+	if started {
+		health.Status = "ok"
+	}
+	if sampling.ratio >= 0 {
+		health.SamplingRatio = &sampling.ratio
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(health)
+}
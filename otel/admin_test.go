@@ -0,0 +1,90 @@
+package otel
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/sdk/trace"
+)
+
+func adminRequest(t *testing.T, h http.Handler, method, target string) (int, AdminHealth) {
+	t.Helper()
+
+	r := httptest.NewRequest(method, target, nil)
+	r.Header.Set("Authorization", "secret")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+
+	var health AdminHealth
+	_ = json.Unmarshal(w.Body.Bytes(), &health)
+	return w.Code, health
+}
+
+func TestAdminHandler_TogglesPipeline(t *testing.T) {
+	setEnv()
+	defer unsetEnv()
+
+	var output bytes.Buffer
+	c := NewENVConfig()
+	c.Writer = &output
+	exporter := NewExporter(IO, c, WithGlobalRegistration(false))
+	h := AdminHandler(exporter, func(r *http.Request) bool { return r.Header.Get("Authorization") == "secret" })
+
+	code, health := adminRequest(t, h, http.MethodGet, "/health")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "not started", health.Status)
+	assert.Nil(t, health.SamplingRatio, "no ratio set")
+	assert.Equal(t, trace.ParentBased(trace.AlwaysSample()).Description(), health.Sampler)
+
+	provider, err := exporter.ExportPipeline(context.TODO())
+	assert.Nil(t, err)
+	defer provider.Shutdown(context.TODO())
+
+	code, health = adminRequest(t, h, http.MethodPost, "/sampling?ratio=0")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "ok", health.Status)
+	assert.Equal(t, 0.0, *health.SamplingRatio)
+	assert.Equal(t, trace.ParentBased(ConsistentProbabilitySampler(0)).Description(), health.Sampler)
+
+	_, span := provider.Tracer("sample").Start(context.TODO(), "dropped")
+	assert.False(t, span.SpanContext().IsSampled())
+	span.End()
+
+	adminRequest(t, h, http.MethodPost, "/sampling?ratio=default")
+	code, health = adminRequest(t, h, http.MethodPost, "/debug?enabled=true")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Nil(t, health.SamplingRatio, "default restored")
+	assert.True(t, health.DebugSpans)
+
+	_, span = provider.Tracer("sample").Start(context.TODO(), "sampled")
+	assert.True(t, span.SpanContext().IsSampled())
+	span.End()
+	assert.Contains(t, output.String(), `debug: span "sampled"`)
+	p, _ := pipelineOf(exporter)
+	assert.Equal(t, exporter.(*ioOutput).writer(), p.debug.writer, "serialized with the spans")
+
+	code, _ = adminRequest(t, h, http.MethodPost, "/flush")
+	assert.Equal(t, http.StatusOK, code)
+}
+
+func TestAdminHandler_RejectsInvalidRequests(t *testing.T) {
+	setEnv()
+	defer unsetEnv()
+
+	exporter := NewExporter(IO, NewENVConfig())
+
+	code, _ := adminRequest(t, AdminHandler(exporter, nil), http.MethodGet, "/health")
+	assert.Equal(t, http.StatusUnauthorized, code)
+
+	h := AdminHandler(exporter, func(*http.Request) bool { return true })
+	code, _ = adminRequest(t, h, http.MethodPost, "/sampling?ratio=2")
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	code, _ = adminRequest(t, h, http.MethodPost, "/flush")
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+}
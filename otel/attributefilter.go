@@ -0,0 +1,88 @@
+package otel
+
+import (
+	"context"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/trace"
+)
+
+// AttributeFilterProcessor is a span processor dropping the attributes of
+// ended spans, their events and links not in its allow list, when set, or in
+// its deny list before handing them to next, usually the batch processor of
+// the exporter. Set Config.AllowedAttributes and Config.DeniedAttributes, or
+// wrap a processor and register it on the provider returned by ExportPipeline
+// with RegisterSpanProcessor.
+//
+// Keys ending with ".*" match every key of that prefix, like "http.*".
+// The attributes of the resource are left as is.
+type AttributeFilterProcessor struct {
+	next  trace.SpanProcessor
+	allow []string
+	deny  []string
+}
+
+var _ trace.SpanProcessor = (*AttributeFilterProcessor)(nil)
+
+// NewAttributeFilterProcessor creates a processor filtering the attributes
+// of the spans with the allow and deny lists before handing them to next,
+// an empty allow list allows every key not denied.
+func NewAttributeFilterProcessor(next trace.SpanProcessor, allow, deny []string) *AttributeFilterProcessor {
+	return &AttributeFilterProcessor{next: next, allow: allow, deny: deny}
+}
+
+// matchKey reports whether key is one of keys or has the prefix of one ending with ".*".
+func matchKey(keys []string, key attribute.Key) bool {
+	for _, k := range keys {
+		if prefix, ok := strings.CutSuffix(k, "*"); ok && strings.HasSuffix(prefix, ".") {
+			if strings.HasPrefix(string(key), prefix) {
+				return true
+			}
+		} else if string(key) == k {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (p *AttributeFilterProcessor) filter(attrs []attribute.KeyValue) ([]attribute.KeyValue, bool) {
+	kept := attrs[:0:0]
+	for _, kv := range attrs {
+		if len(p.allow) > 0 && !matchKey(p.allow, kv.Key) || matchKey(p.deny, kv.Key) {
+			continue
+		}
+		kept = append(kept, kv)
+	}
+	if len(kept) == len(attrs) {
+		return attrs, false
+	}
+
+	return kept, true
+}
+
+// OnStart implements the trace.SpanProcessor interface.
+func (p *AttributeFilterProcessor) OnStart(ctx context.Context, s trace.ReadWriteSpan) {
+	p.next.OnStart(ctx, s)
+}
+
+// OnEnd implements the trace.SpanProcessor interface.
+func (p *AttributeFilterProcessor) OnEnd(s trace.ReadOnlySpan) {
+	span, filtered := mapAttributes(s, p.filter)
+	if !filtered {
+		p.next.OnEnd(s)
+		return
+	}
+	p.next.OnEnd(span)
+}
+
+// Shutdown implements the trace.SpanProcessor interface.
+func (p *AttributeFilterProcessor) Shutdown(ctx context.Context) error {
+	return p.next.Shutdown(ctx)
+}
+
+// ForceFlush implements the trace.SpanProcessor interface.
+func (p *AttributeFilterProcessor) ForceFlush(ctx context.Context) error {
+	return p.next.ForceFlush(ctx)
+}
@@ -0,0 +1,58 @@
+package otel
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+func TestAttributeFilterProcessor_AppliesAllowAndDenyLists(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := trace.NewTracerProvider(trace.WithSpanProcessor(NewAttributeFilterProcessor(recorder,
+		[]string{"http.*", "db.system", "exception.*"}, []string{"http.request.header.cookie"})))
+	defer tp.Shutdown(context.TODO())
+
+	_, span := tp.Tracer("sample").Start(context.TODO(), "GET /orders", oteltrace.WithAttributes(
+		attribute.String("http.method", "GET"),
+		attribute.String("http.request.header.cookie", "session=secret"),
+		attribute.String("db.system", "postgresql"),
+		attribute.String("db.statement", "SELECT * FROM users"),
+		attribute.String("httpx", "dropped, not in the http prefix"),
+	))
+	span.AddEvent("exception", oteltrace.WithAttributes(
+		attribute.String("exception.message", "timeout"),
+		attribute.String("user.email", "jane@example.com"),
+	))
+	span.End()
+
+	ended := recorder.Ended()[0]
+	assert.Equal(t, []attribute.KeyValue{
+		attribute.String("http.method", "GET"),
+		attribute.String("db.system", "postgresql"),
+	}, ended.Attributes())
+	assert.Equal(t, []attribute.KeyValue{attribute.String("exception.message", "timeout")}, ended.Events()[0].Attributes)
+}
+
+func TestAttributeFilterProcessor_ConfiguredOnPipeline(t *testing.T) {
+	setEnv()
+	defer unsetEnv()
+
+	c := NewENVConfig()
+	c.Writer = io.Discard
+	c.DeniedAttributes = []string{"enduser.id"}
+	c.Redactions = []string{"email"}
+	exporter := NewExporter(IO, c)
+
+	pipeline, err := exporter.ExportPipeline(context.TODO())
+	assert.Nil(t, err)
+	defer pipeline.Shutdown(context.TODO())
+
+	snapshot, _ := Snapshot(exporter)
+	assert.Equal(t, []string{"debug", "counter", "attribute_filter", "redaction", "batch"}, snapshot.Processors)
+}
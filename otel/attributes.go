@@ -0,0 +1,75 @@
+package otel
+
+import (
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// displaySuffix is appended to keys holding the human readable
+// value of a size or duration attribute.
+const displaySuffix = ".display"
+
+var byteUnits = []string{"B", "KiB", "MiB", "GiB", "TiB", "PiB"}
+
+// ByteSize returns the attributes of a size in bytes, the raw number of bytes
+// under key and its human readable value under key.display, e.g. "1.5 MiB".
+func ByteSize(key attribute.Key, bytes int64) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		key.Int64(bytes),
+		(key + displaySuffix).String(formatBytes(bytes)),
+	}
+}
+
+// Duration returns the attributes of a duration, in milliseconds under key
+// and its human readable value under key.display, e.g. "1.5s".
+//
+// Use it instead of ad hoc "duration_ms" string attributes.
+func Duration(key attribute.Key, d time.Duration) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		key.Float64(float64(d) / float64(time.Millisecond)),
+		(key + displaySuffix).String(d.String()),
+	}
+}
+
+// HTTPRequestSize returns the ByteSize attributes of an HTTP request body.
+func HTTPRequestSize(bytes int64) []attribute.KeyValue {
+	return ByteSize(semconv.HTTPRequestContentLengthKey, bytes)
+}
+
+// HTTPResponseSize returns the ByteSize attributes of an HTTP response body.
+func HTTPResponseSize(bytes int64) []attribute.KeyValue {
+	return ByteSize(semconv.HTTPResponseContentLengthKey, bytes)
+}
+
+// MessagingPayloadSize returns the ByteSize attributes of a message payload.
+func MessagingPayloadSize(bytes int64) []attribute.KeyValue {
+	return ByteSize(semconv.MessagingMessagePayloadSizeBytesKey, bytes)
+}
+
+// SetByteSize records the ByteSize attributes on span.
+func SetByteSize(span trace.Span, key attribute.Key, bytes int64) {
+	span.SetAttributes(ByteSize(key, bytes)...)
+}
+
+// SetDuration records the Duration attributes on span.
+func SetDuration(span trace.Span, key attribute.Key, d time.Duration) {
+	span.SetAttributes(Duration(key, d)...)
+}
+
+func formatBytes(bytes int64) string {
+	value, unit := float64(bytes), 0
+	for (value >= 1024 || value <= -1024) && unit < len(byteUnits)-1 {
+		value /= 1024
+		unit++
+	}
+
+	if unit == 0 {
+		return fmt.Sprintf("%d B", bytes)
+	}
+
+	return fmt.Sprintf("%.1f %s", value, byteUnits[unit])
+}
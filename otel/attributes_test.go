@@ -0,0 +1,30 @@
+package otel
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+func TestAttributes_ByteSize(t *testing.T) {
+	assert.Equal(t, []attribute.KeyValue{
+		attribute.Int64("payload.size", 1572864),
+		attribute.String("payload.size.display", "1.5 MiB"),
+	}, ByteSize("payload.size", 1572864))
+
+	assert.Equal(t, "512 B", formatBytes(512))
+	assert.Equal(t, "2.0 KiB", formatBytes(2048))
+}
+
+func TestAttributes_Duration(t *testing.T) {
+	assert.Equal(t, []attribute.KeyValue{
+		attribute.Float64("db.query.duration", 1500),
+		attribute.String("db.query.duration.display", "1.5s"),
+	}, Duration("db.query.duration", 1500*time.Millisecond))
+}
+
+func TestAttributes_SemconvKeys(t *testing.T) {
+	assert.Equal(t, attribute.Int64("http.response_content_length", 10), HTTPResponseSize(10)[0])
+}
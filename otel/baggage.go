@@ -0,0 +1,69 @@
+package otel
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/sdk/trace"
+)
+
+// SetBaggage returns a copy of ctx whose baggage holds key set to value,
+// propagated to downstream services along with the trace context.
+func SetBaggage(ctx context.Context, key, value string) (context.Context, error) {
+	member, err := baggage.NewMemberRaw(key, value)
+	if err != nil {
+		return ctx, fmt.Errorf("invalid baggage member %q: %w", key, err)
+	}
+
+	b, err := baggage.FromContext(ctx).SetMember(member)
+	if err != nil {
+		return ctx, fmt.Errorf("could not set baggage member %q: %w", key, err)
+	}
+
+	return baggage.ContextWithBaggage(ctx, b), nil
+}
+
+// GetBaggage returns the value of key in the baggage of ctx, empty when unset.
+func GetBaggage(ctx context.Context, key string) string {
+	return baggage.FromContext(ctx).Member(key).Value()
+}
+
+// BaggageProcessor is a span processor copying baggage entries of the
+// context spans are started with onto the spans as attributes of the same key.
+// Set Config.BaggageAttributes or register it on the provider returned by
+// ExportPipeline with RegisterSpanProcessor.
+type BaggageProcessor struct {
+	keys []string
+}
+
+var _ trace.SpanProcessor = (*BaggageProcessor)(nil)
+
+// NewBaggageProcessor creates a processor copying the baggage entries of keys.
+func NewBaggageProcessor(keys ...string) *BaggageProcessor {
+	return &BaggageProcessor{keys: keys}
+}
+
+// OnStart implements the trace.SpanProcessor interface.
+func (p *BaggageProcessor) OnStart(ctx context.Context, s trace.ReadWriteSpan) {
+	b := baggage.FromContext(ctx)
+	for _, key := range p.keys {
+		if member := b.Member(key); member.Key() != "" {
+			s.SetAttributes(attribute.String(key, member.Value()))
+		}
+	}
+}
+
+// OnEnd implements the trace.SpanProcessor interface.
+func (p *BaggageProcessor) OnEnd(trace.ReadOnlySpan) {}
+
+// Shutdown implements the trace.SpanProcessor interface.
+func (p *BaggageProcessor) Shutdown(context.Context) error {
+	return nil
+}
+
+// ForceFlush implements the trace.SpanProcessor interface.
+func (p *BaggageProcessor) ForceFlush(context.Context) error {
+	return nil
+}
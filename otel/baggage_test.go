@@ -0,0 +1,35 @@
+package otel
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestBaggage_SetAndGet(t *testing.T) {
+	ctx, err := SetBaggage(context.Background(), "tenant.id", "acme corp")
+	assert.Nil(t, err)
+	assert.Equal(t, "acme corp", GetBaggage(ctx, "tenant.id"))
+	assert.Empty(t, GetBaggage(ctx, "user.tier"))
+
+	_, err = SetBaggage(ctx, "", "value")
+	assert.NotNil(t, err)
+}
+
+func TestBaggageProcessor_CopiesSelectedEntries(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := trace.NewTracerProvider(trace.WithSpanProcessor(NewBaggageProcessor("tenant.id")), trace.WithSpanProcessor(recorder))
+	defer tp.Shutdown(context.TODO())
+
+	ctx, _ := SetBaggage(context.TODO(), "tenant.id", "acme")
+	ctx, _ = SetBaggage(ctx, "session.token", "secret")
+	_, span := tp.Tracer("sample").Start(ctx, "sample span")
+	span.End()
+
+	attrs := recorder.Ended()[0].Attributes()
+	assert.Equal(t, []attribute.KeyValue{attribute.String("tenant.id", "acme")}, attrs)
+}
@@ -0,0 +1,161 @@
+package otel
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/resolver"
+	"google.golang.org/grpc/resolver/manual"
+)
+
+// roundRobinServiceConfig spreads the RPCs of a connection over the
+// addresses of its target instead of the first one.
+const roundRobinServiceConfig = `{"loadBalancingConfig": [{"round_robin": {}}]}`
+
+// endpointsScheme is the scheme of the target resolved to Config.Endpoints.
+const endpointsScheme = "otel-endpoints"
+
+// dnsScheme is the scheme of the target resolved with Config.LoadBalance.
+const dnsScheme = "otel-dns"
+
+// dnsRefreshInterval is how often the host of Config.URL is resolved again
+// with LoadBalance. The dns resolver of gRPC only does when a connection
+// fails, so the replicas added by a scale up would never be used.
+const dnsRefreshInterval = 30 * time.Second
+
+// dnsTimeout bounds every lookup of the host.
+const dnsTimeout = 10 * time.Second
+
+// target returns the target of the GRPC connection along with the options
+// balancing the exports over Config.Endpoints, or over the addresses URL
+// resolves to with Config.LoadBalance. URL is left for Config.ProxyURL to
+// resolve otherwise, the proxy being the one able to reach its host.
+func (c *Config) target() (string, []grpc.DialOption, error) {
+	switch {
+	case len(c.Endpoints) > 0:
+		endpoints := make([]resolver.Endpoint, 0, len(c.Endpoints))
+		for _, endpoint := range c.Endpoints {
+			host, _, err := net.SplitHostPort(endpoint)
+			if err != nil {
+				return "", nil, fmt.Errorf("invalid endpoint %q: %w", endpoint, err)
+			}
+			// The certificates of the replicas are verified against their own host.
+			endpoints = append(endpoints, resolver.Endpoint{Addresses: []resolver.Address{{Addr: endpoint, ServerName: host}}})
+		}
+		r := manual.NewBuilderWithScheme(endpointsScheme)
+		r.InitialState(resolver.State{Endpoints: endpoints})
+
+		return endpointsScheme + ":///", []grpc.DialOption{
+			grpc.WithResolvers(r),
+			grpc.WithDefaultServiceConfig(roundRobinServiceConfig),
+		}, nil
+	case c.LoadBalance:
+		r := &dnsBuilder{lookup: net.DefaultResolver.LookupHost, interval: dnsRefreshInterval}
+
+		return dnsScheme + ":///" + c.URL, []grpc.DialOption{
+			grpc.WithResolvers(r),
+			grpc.WithDefaultServiceConfig(roundRobinServiceConfig),
+		}, nil
+	case c.ProxyURL != "":
+		return "passthrough:///" + c.URL, nil, nil
+	}
+
+	return c.URL, nil, nil
+}
+
+// dnsBuilder builds the resolvers of the dnsScheme targets, resolving their
+// host with lookup every interval and whenever gRPC asks to.
+type dnsBuilder struct {
+	lookup   func(ctx context.Context, host string) ([]string, error)
+	interval time.Duration
+}
+
+// Build implements the resolver.Builder interface.
+func (b *dnsBuilder) Build(target resolver.Target, cc resolver.ClientConn, _ resolver.BuildOptions) (resolver.Resolver, error) {
+	host, port, err := net.SplitHostPort(target.Endpoint())
+	if err != nil {
+		return nil, fmt.Errorf("invalid endpoint %q: %w", target.Endpoint(), err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r := &dnsResolver{
+		builder: b,
+		host:    host,
+		port:    port,
+		cc:      cc,
+		now:     make(chan struct{}, 1),
+		cancel:  cancel,
+		done:    make(chan struct{}),
+	}
+	go r.run(ctx)
+
+	return r, nil
+}
+
+// Scheme implements the resolver.Builder interface.
+func (b *dnsBuilder) Scheme() string {
+	return dnsScheme
+}
+
+type dnsResolver struct {
+	builder    *dnsBuilder
+	host, port string
+	cc         resolver.ClientConn
+	now        chan struct{}
+	cancel     context.CancelFunc
+	done       chan struct{}
+}
+
+func (r *dnsResolver) run(ctx context.Context) {
+	defer close(r.done)
+
+	ticker := time.NewTicker(r.builder.interval)
+	defer ticker.Stop()
+	for {
+		r.resolve(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-r.now:
+		}
+	}
+}
+
+// resolve updates the addresses of the connection, keeping the previous ones
+// when the lookup fails.
+func (r *dnsResolver) resolve(ctx context.Context) {
+	lookupCtx, cancel := context.WithTimeout(ctx, dnsTimeout)
+	defer cancel()
+
+	hosts, err := r.builder.lookup(lookupCtx, r.host)
+	if err != nil {
+		// the lookups interrupted by Close aren't failures
+		if ctx.Err() == nil {
+			r.cc.ReportError(fmt.Errorf("could not resolve %s: %w", r.host, err))
+		}
+		return
+	}
+	addresses := make([]resolver.Address, 0, len(hosts))
+	for _, host := range hosts {
+		addresses = append(addresses, resolver.Address{Addr: net.JoinHostPort(host, r.port)})
+	}
+	r.cc.UpdateState(resolver.State{Addresses: addresses})
+}
+
+// ResolveNow implements the resolver.Resolver interface.
+func (r *dnsResolver) ResolveNow(resolver.ResolveNowOptions) {
+	select {
+	case r.now <- struct{}{}:
+	default:
+	}
+}
+
+// Close implements the resolver.Resolver interface.
+func (r *dnsResolver) Close() {
+	r.cancel()
+	<-r.done
+}
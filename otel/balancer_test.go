@@ -0,0 +1,87 @@
+package otel
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/resolver"
+)
+
+func TestGRPCOutput_BalancesOverEndpoints(t *testing.T) {
+	first, firstCollector := startTraceCollector(t)
+	second, secondCollector := startTraceCollector(t)
+	exporter := NewExporter(GRPC, &Config{
+		Endpoints:  []string{first, second},
+		Insecure:   true,
+		SyncExport: true,
+	}, WithGlobalRegistration(false))
+	pipeline, err := exporter.ExportPipeline(context.TODO())
+	assert.Nil(t, err)
+	defer pipeline.Shutdown(context.TODO())
+
+	for range 10 {
+		_, span := pipeline.Tracer("sample").Start(context.TODO(), "sample span")
+		span.End()
+	}
+
+	assert.Equal(t, 10, len(firstCollector.requests)+len(secondCollector.requests))
+	assert.NotEmpty(t, firstCollector.requests)
+	assert.NotEmpty(t, secondCollector.requests)
+}
+
+func TestConfig_Target(t *testing.T) {
+	target, opts, err := (&Config{URL: "collector:4317"}).target()
+	assert.Nil(t, err)
+	assert.Equal(t, "collector:4317", target)
+	assert.Empty(t, opts)
+
+	target, opts, err = (&Config{URL: "collector:4317", LoadBalance: true}).target()
+	assert.Nil(t, err)
+	assert.Equal(t, "otel-dns:///collector:4317", target)
+	assert.Len(t, opts, 2)
+
+	_, _, err = (&Config{Endpoints: []string{"collector"}}).target()
+	assert.ErrorContains(t, err, `invalid endpoint "collector"`)
+}
+
+// stateConn records the addresses and errors a resolver reports.
+type stateConn struct {
+	resolver.ClientConn
+	states chan []resolver.Address
+	errs   chan error
+}
+
+func (c *stateConn) UpdateState(s resolver.State) error {
+	c.states <- s.Addresses
+	return nil
+}
+
+func (c *stateConn) ReportError(err error) {
+	c.errs <- err
+}
+
+func TestDNSBuilder_ResolvesPeriodically(t *testing.T) {
+	var lookups atomic.Int32
+	b := &dnsBuilder{interval: 10 * time.Millisecond, lookup: func(context.Context, string) ([]string, error) {
+		switch lookups.Add(1) {
+		case 1:
+			return []string{"10.0.0.1"}, nil
+		case 2:
+			return nil, errors.New("no such host")
+		}
+		return []string{"10.0.0.1", "10.0.0.2"}, nil
+	}}
+	conn := &stateConn{states: make(chan []resolver.Address, 10), errs: make(chan error, 10)}
+	r, err := b.Build(resolver.Target{URL: url.URL{Scheme: dnsScheme, Path: "/collector:4317"}}, conn, resolver.BuildOptions{})
+	assert.Nil(t, err)
+	defer r.Close()
+
+	assert.Equal(t, []resolver.Address{{Addr: "10.0.0.1:4317"}}, <-conn.states)
+	assert.EqualError(t, <-conn.errs, "could not resolve collector: no such host")
+	assert.Equal(t, []resolver.Address{{Addr: "10.0.0.1:4317"}, {Addr: "10.0.0.2:4317"}}, <-conn.states)
+}
@@ -0,0 +1,67 @@
+package otel
+
+import (
+	"runtime/debug"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// Version control attributes read from the build info of the binary.
+const (
+	VCSRevisionKey = attribute.Key("vcs.revision")
+	VCSModifiedKey = attribute.Key("vcs.modified")
+)
+
+// develVersion is the version of main modules built from a checkout.
+const develVersion = "(devel)"
+
+// readBuildInfo is replaced in tests, binaries built by go test have no VCS info.
+var readBuildInfo = debug.ReadBuildInfo
+
+// serviceVersion returns Config.ServiceVersion, defaulting to the version
+// of the main module, e.g. v1.2.3 for binaries installed with go install,
+// or the VCS revision for binaries built from a checkout.
+func (c *Config) serviceVersion() string {
+	if c.ServiceVersion != "" {
+		return c.ServiceVersion
+	}
+
+	info, ok := readBuildInfo()
+	if !ok {
+		return ""
+	}
+	if info.Main.Version != "" && info.Main.Version != develVersion {
+		return info.Main.Version
+	}
+
+	return buildSetting(info, "vcs.revision")
+}
+
+// vcsAttributes returns the VCS revision the binary was built from
+// and whether the checkout had local changes.
+func vcsAttributes() []attribute.KeyValue {
+	info, ok := readBuildInfo()
+	if !ok {
+		return nil
+	}
+
+	var attrs []attribute.KeyValue
+	if revision := buildSetting(info, "vcs.revision"); revision != "" {
+		attrs = append(attrs, VCSRevisionKey.String(revision))
+	}
+	if modified := buildSetting(info, "vcs.modified"); modified != "" {
+		attrs = append(attrs, VCSModifiedKey.Bool(modified == "true"))
+	}
+
+	return attrs
+}
+
+func buildSetting(info *debug.BuildInfo, key string) string {
+	for _, s := range info.Settings {
+		if s.Key == key {
+			return s.Value
+		}
+	}
+
+	return ""
+}
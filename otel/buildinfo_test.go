@@ -0,0 +1,39 @@
+package otel
+
+import (
+	"context"
+	"runtime/debug"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResource_VersionFromBuildInfo(t *testing.T) {
+	defer func(read func() (*debug.BuildInfo, bool)) { readBuildInfo = read }(readBuildInfo)
+
+	info := &debug.BuildInfo{
+		Main: debug.Module{Path: "example.com/orders", Version: develVersion},
+		Settings: []debug.BuildSetting{
+			{Key: "vcs.revision", Value: "4f2a9c1e"},
+			{Key: "vcs.modified", Value: "true"},
+		},
+	}
+	readBuildInfo = func() (*debug.BuildInfo, bool) { return info, true }
+
+	c := &Config{ServiceName: "orders"}
+	res, err := c.resource(context.TODO())
+	assert.Nil(t, err)
+	attrs := res.Set()
+	value, _ := attrs.Value("service.version")
+	assert.Equal(t, "4f2a9c1e", value.AsString())
+	value, _ = attrs.Value(VCSRevisionKey)
+	assert.Equal(t, "4f2a9c1e", value.AsString())
+	value, _ = attrs.Value(VCSModifiedKey)
+	assert.True(t, value.AsBool())
+
+	info.Main.Version = "v1.2.3"
+	assert.Equal(t, "v1.2.3", c.serviceVersion())
+
+	c.ServiceVersion = "v2.0.0"
+	assert.Equal(t, "v2.0.0", c.serviceVersion())
+}
@@ -0,0 +1,303 @@
+package otel
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.43.0"
+)
+
+// metadataTimeout bounds the requests to instance metadata services,
+// which aren't reachable outside of their cloud.
+const metadataTimeout = time.Second
+
+// metadataClient queries the link local metadata services, never through a proxy.
+var metadataClient = &http.Client{
+	Timeout:   metadataTimeout,
+	Transport: &http.Transport{Proxy: nil},
+}
+
+// AzureResourceGroupKey is the resource group of the Azure VM the process runs on.
+const AzureResourceGroupKey = attribute.Key("azure.resource_group.name")
+
+// fetchMetadata sends a metadata request and decodes its JSON response into v,
+// or returns it as is when v is a *string.
+func fetchMetadata(ctx context.Context, client *http.Client, method, url string, header http.Header, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, method, url, nil)
+	if err != nil {
+		return err
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("metadata request failed with status %d", resp.StatusCode)
+	}
+	if s, ok := v.(*string); ok {
+		body, err := io.ReadAll(resp.Body)
+		*s = strings.TrimSpace(string(body))
+		return err
+	}
+
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// nonEmpty drops the attributes with an empty string value.
+func nonEmpty(attrs ...attribute.KeyValue) []attribute.KeyValue {
+	var kept []attribute.KeyValue
+	for _, kv := range attrs {
+		if kv.Value.Type() != attribute.STRING || kv.Value.AsString() != "" {
+			kept = append(kept, kv)
+		}
+	}
+
+	return kept
+}
+
+// lastSegment returns what follows the last slash of s, like the zone
+// of "projects/123/zones/us-central1-a".
+func lastSegment(s string) string {
+	return s[strings.LastIndex(s, "/")+1:]
+}
+
+// awsDetector sets the cloud attributes of Lambda functions, ECS tasks
+// and EC2 instances, including EKS nodes.
+type awsDetector struct {
+	getenv  func(string) string
+	imdsURL string
+	client  *http.Client
+}
+
+// Detect implements the resource.Detector interface.
+func (d awsDetector) Detect(ctx context.Context) (*resource.Resource, error) {
+	if name := d.getenv("AWS_LAMBDA_FUNCTION_NAME"); name != "" {
+		return resource.NewSchemaless(nonEmpty(
+			semconv.CloudProviderAWS,
+			semconv.CloudPlatformAWSLambda,
+			semconv.CloudRegionKey.String(d.getenv("AWS_REGION")),
+			semconv.FaaSNameKey.String(name),
+			semconv.FaaSVersionKey.String(d.getenv("AWS_LAMBDA_FUNCTION_VERSION")),
+		)...), nil
+	}
+	if uri := d.getenv("ECS_CONTAINER_METADATA_URI_V4"); uri != "" {
+		return d.detectECS(ctx, uri), nil
+	}
+
+	return d.detectEC2(ctx), nil
+}
+
+func (d awsDetector) detectECS(ctx context.Context, uri string) *resource.Resource {
+	var task struct {
+		Cluster          string
+		TaskARN          string
+		Family           string
+		Revision         string
+		AvailabilityZone string
+		LaunchType       string
+	}
+	if err := fetchMetadata(ctx, d.client, http.MethodGet, uri+"/task", nil, &task); err != nil {
+		return resource.Empty()
+	}
+	var container struct {
+		ContainerARN string
+	}
+	_ = fetchMetadata(ctx, d.client, http.MethodGet, uri, nil, &container)
+
+	// arn:aws:ecs:<region>:<account>:task/<cluster>/<id>
+	arn := strings.SplitN(task.TaskARN, ":", 6)
+	var region, account string
+	if len(arn) == 6 {
+		region, account = arn[3], arn[4]
+	}
+	cluster := task.Cluster
+	if !strings.HasPrefix(cluster, "arn:") && region != "" {
+		cluster = fmt.Sprintf("arn:aws:ecs:%s:%s:cluster/%s", region, account, cluster)
+	}
+
+	return resource.NewSchemaless(nonEmpty(
+		semconv.CloudProviderAWS,
+		semconv.CloudPlatformAWSECS,
+		semconv.CloudRegionKey.String(region),
+		semconv.CloudAccountIDKey.String(account),
+		semconv.CloudAvailabilityZoneKey.String(task.AvailabilityZone),
+		semconv.AWSECSClusterARNKey.String(cluster),
+		semconv.AWSECSTaskARNKey.String(task.TaskARN),
+		semconv.AWSECSTaskFamilyKey.String(task.Family),
+		semconv.AWSECSTaskRevisionKey.String(task.Revision),
+		semconv.AWSECSLaunchtypeKey.String(strings.ToLower(task.LaunchType)),
+		semconv.AWSECSContainerARNKey.String(container.ContainerARN),
+	)...)
+}
+
+func (d awsDetector) detectEC2(ctx context.Context) *resource.Resource {
+	var token string
+	err := fetchMetadata(ctx, d.client, http.MethodPut, d.imdsURL+"/latest/api/token",
+		http.Header{"X-Aws-Ec2-Metadata-Token-Ttl-Seconds": {"60"}}, &token)
+	if err != nil {
+		return resource.Empty()
+	}
+
+	var identity struct {
+		Region           string `json:"region"`
+		AccountID        string `json:"accountId"`
+		AvailabilityZone string `json:"availabilityZone"`
+		InstanceID       string `json:"instanceId"`
+		InstanceType     string `json:"instanceType"`
+		ImageID          string `json:"imageId"`
+	}
+	err = fetchMetadata(ctx, d.client, http.MethodGet, d.imdsURL+"/latest/dynamic/instance-identity/document",
+		http.Header{"X-Aws-Ec2-Metadata-Token": {token}}, &identity)
+	if err != nil {
+		return resource.Empty()
+	}
+
+	platform := semconv.CloudPlatformAWSEC2
+	if d.getenv("KUBERNETES_SERVICE_HOST") != "" {
+		platform = semconv.CloudPlatformAWSEKS
+	}
+
+	return resource.NewSchemaless(nonEmpty(
+		semconv.CloudProviderAWS,
+		platform,
+		semconv.CloudRegionKey.String(identity.Region),
+		semconv.CloudAccountIDKey.String(identity.AccountID),
+		semconv.CloudAvailabilityZoneKey.String(identity.AvailabilityZone),
+		semconv.HostIDKey.String(identity.InstanceID),
+		semconv.HostTypeKey.String(identity.InstanceType),
+		semconv.HostImageIDKey.String(identity.ImageID),
+	)...)
+}
+
+// gcpDetector sets the cloud attributes of Cloud Run services,
+// GKE nodes and Compute Engine instances.
+type gcpDetector struct {
+	getenv      func(string) string
+	metadataURL string
+	client      *http.Client
+}
+
+// Detect implements the resource.Detector interface.
+func (d gcpDetector) Detect(ctx context.Context) (*resource.Resource, error) {
+	var project string
+	if err := d.fetch(ctx, "project/project-id", &project); err != nil {
+		return resource.Empty(), nil
+	}
+
+	var zone, region, id, machineType string
+	_ = d.fetch(ctx, "instance/zone", &zone)
+	_ = d.fetch(ctx, "instance/region", &region)
+	_ = d.fetch(ctx, "instance/id", &id)
+	_ = d.fetch(ctx, "instance/machine-type", &machineType)
+
+	zone = lastSegment(zone)
+	region = lastSegment(region)
+	if i := strings.LastIndex(zone, "-"); region == "" && i > 0 {
+		region = zone[:i]
+	}
+
+	attrs := []attribute.KeyValue{
+		semconv.CloudProviderGCP,
+		semconv.CloudAccountIDKey.String(project),
+		semconv.CloudRegionKey.String(region),
+	}
+	switch {
+	case d.getenv("K_SERVICE") != "":
+		attrs = append(attrs,
+			semconv.CloudPlatformGCPCloudRun,
+			semconv.FaaSNameKey.String(d.getenv("K_SERVICE")),
+			semconv.FaaSVersionKey.String(d.getenv("K_REVISION")),
+			semconv.FaaSInstanceKey.String(id),
+		)
+	case d.getenv("KUBERNETES_SERVICE_HOST") != "":
+		attrs = append(attrs,
+			semconv.CloudPlatformGCPKubernetesEngine,
+			semconv.CloudAvailabilityZoneKey.String(zone),
+			semconv.HostIDKey.String(id),
+		)
+	default:
+		attrs = append(attrs,
+			semconv.CloudPlatformGCPComputeEngine,
+			semconv.CloudAvailabilityZoneKey.String(zone),
+			semconv.HostIDKey.String(id),
+			semconv.HostTypeKey.String(lastSegment(machineType)),
+		)
+	}
+
+	return resource.NewSchemaless(nonEmpty(attrs...)...), nil
+}
+
+func (d gcpDetector) fetch(ctx context.Context, path string, value *string) error {
+	return fetchMetadata(ctx, d.client, http.MethodGet, d.metadataURL+"/computeMetadata/v1/"+path,
+		http.Header{"Metadata-Flavor": {"Google"}}, value)
+}
+
+// azureDetector sets the cloud attributes of Azure VMs, including AKS nodes.
+type azureDetector struct {
+	getenv  func(string) string
+	imdsURL string
+	client  *http.Client
+}
+
+// Detect implements the resource.Detector interface.
+func (d azureDetector) Detect(ctx context.Context) (*resource.Resource, error) {
+	var compute struct {
+		Location          string `json:"location"`
+		Name              string `json:"name"`
+		ResourceGroupName string `json:"resourceGroupName"`
+		SubscriptionID    string `json:"subscriptionId"`
+		VMID              string `json:"vmId"`
+		VMSize            string `json:"vmSize"`
+		Zone              string `json:"zone"`
+	}
+	err := fetchMetadata(ctx, d.client, http.MethodGet, d.imdsURL+"/metadata/instance/compute?api-version=2021-02-01&format=json",
+		http.Header{"Metadata": {"true"}}, &compute)
+	if err != nil {
+		return resource.Empty(), nil
+	}
+
+	platform := semconv.CloudPlatformAzureVM
+	if d.getenv("KUBERNETES_SERVICE_HOST") != "" {
+		platform = semconv.CloudPlatformAzureAKS
+	}
+
+	return resource.NewSchemaless(nonEmpty(
+		semconv.CloudProviderAzure,
+		platform,
+		semconv.CloudRegionKey.String(compute.Location),
+		semconv.CloudAccountIDKey.String(compute.SubscriptionID),
+		semconv.CloudAvailabilityZoneKey.String(compute.Zone),
+		semconv.HostIDKey.String(compute.VMID),
+		semconv.HostTypeKey.String(compute.VMSize),
+		semconv.HostNameKey.String(compute.Name),
+		AzureResourceGroupKey.String(compute.ResourceGroupName),
+	)...), nil
+}
+
+// firstDetector returns the resource of the first detector finding one.
+type firstDetector []resource.Detector
+
+// Detect implements the resource.Detector interface.
+func (d firstDetector) Detect(ctx context.Context) (*resource.Resource, error) {
+	for _, detector := range d {
+		res, err := detector.Detect(ctx)
+		if err == nil && res.Len() > 0 {
+			return res, nil
+		}
+	}
+
+	return resource.Empty(), nil
+}
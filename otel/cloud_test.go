@@ -0,0 +1,170 @@
+package otel
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+func environment(env map[string]string) func(string) string {
+	return func(key string) string { return env[key] }
+}
+
+func TestAWSDetector_EC2(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("PUT /latest/api/token", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "60", r.Header.Get("X-Aws-Ec2-Metadata-Token-Ttl-Seconds"))
+		w.Write([]byte("token"))
+	})
+	mux.HandleFunc("GET /latest/dynamic/instance-identity/document", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "token", r.Header.Get("X-Aws-Ec2-Metadata-Token"))
+		w.Write([]byte(`{"region":"eu-west-1","accountId":"123456789012","availabilityZone":"eu-west-1a",` +
+			`"instanceId":"i-0abc","instanceType":"m5.large","imageId":"ami-0def"}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	d := awsDetector{getenv: environment(nil), imdsURL: server.URL, client: server.Client()}
+	res, err := d.Detect(context.TODO())
+	assert.Nil(t, err)
+	assert.ElementsMatch(t, []attribute.KeyValue{
+		attribute.String("cloud.provider", "aws"),
+		attribute.String("cloud.platform", "aws_ec2"),
+		attribute.String("cloud.region", "eu-west-1"),
+		attribute.String("cloud.account.id", "123456789012"),
+		attribute.String("cloud.availability_zone", "eu-west-1a"),
+		attribute.String("host.id", "i-0abc"),
+		attribute.String("host.type", "m5.large"),
+		attribute.String("host.image.id", "ami-0def"),
+	}, res.Attributes())
+
+	d.getenv = environment(map[string]string{"KUBERNETES_SERVICE_HOST": "10.0.0.1"})
+	res, _ = d.Detect(context.TODO())
+	value, _ := res.Set().Value("cloud.platform")
+	assert.Equal(t, "aws_eks", value.AsString())
+}
+
+func TestAWSDetector_ECS(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v4", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"ContainerARN":"arn:aws:ecs:us-east-1:123456789012:container/shop/1/2"}`))
+	})
+	mux.HandleFunc("GET /v4/task", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"Cluster":"shop","TaskARN":"arn:aws:ecs:us-east-1:123456789012:task/shop/1",` +
+			`"Family":"orders","Revision":"7","AvailabilityZone":"us-east-1b","LaunchType":"FARGATE"}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	d := awsDetector{getenv: environment(map[string]string{"ECS_CONTAINER_METADATA_URI_V4": server.URL + "/v4"}), client: server.Client()}
+	res, err := d.Detect(context.TODO())
+	assert.Nil(t, err)
+	attrs := res.Set()
+	for key, expected := range map[attribute.Key]string{
+		"cloud.platform":        "aws_ecs",
+		"cloud.region":          "us-east-1",
+		"cloud.account.id":      "123456789012",
+		"aws.ecs.cluster.arn":   "arn:aws:ecs:us-east-1:123456789012:cluster/shop",
+		"aws.ecs.task.family":   "orders",
+		"aws.ecs.launchtype":    "fargate",
+		"aws.ecs.container.arn": "arn:aws:ecs:us-east-1:123456789012:container/shop/1/2",
+	} {
+		value, _ := attrs.Value(key)
+		assert.Equal(t, expected, value.AsString(), key)
+	}
+}
+
+func TestGCPDetector_CloudRun(t *testing.T) {
+	metadata := map[string]string{
+		"/computeMetadata/v1/project/project-id": "shop-prod",
+		"/computeMetadata/v1/instance/region":    "projects/42/regions/europe-west1",
+		"/computeMetadata/v1/instance/id":        "00bf4b",
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		value, ok := metadata[r.URL.Path]
+		if !ok || r.Header.Get("Metadata-Flavor") != "Google" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(value))
+	}))
+	defer server.Close()
+
+	d := gcpDetector{getenv: environment(map[string]string{"K_SERVICE": "orders", "K_REVISION": "orders-00007"}), metadataURL: server.URL, client: server.Client()}
+	res, err := d.Detect(context.TODO())
+	assert.Nil(t, err)
+	assert.ElementsMatch(t, []attribute.KeyValue{
+		attribute.String("cloud.provider", "gcp"),
+		attribute.String("cloud.platform", "gcp_cloud_run"),
+		attribute.String("cloud.account.id", "shop-prod"),
+		attribute.String("cloud.region", "europe-west1"),
+		attribute.String("faas.name", "orders"),
+		attribute.String("faas.version", "orders-00007"),
+		attribute.String("faas.instance", "00bf4b"),
+	}, res.Attributes())
+
+	delete(metadata, "/computeMetadata/v1/instance/region")
+	metadata["/computeMetadata/v1/instance/zone"] = "projects/42/zones/europe-west1-b"
+	metadata["/computeMetadata/v1/instance/machine-type"] = "projects/42/machineTypes/e2-medium"
+	d.getenv = environment(nil)
+	res, _ = d.Detect(context.TODO())
+	attrs := res.Set()
+	for key, expected := range map[attribute.Key]string{
+		"cloud.platform":          "gcp_compute_engine",
+		"cloud.region":            "europe-west1",
+		"cloud.availability_zone": "europe-west1-b",
+		"host.type":               "e2-medium",
+	} {
+		value, _ := attrs.Value(key)
+		assert.Equal(t, expected, value.AsString(), key)
+	}
+}
+
+func TestAzureDetector_VM(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "true", r.Header.Get("Metadata"))
+		assert.Equal(t, "/metadata/instance/compute", r.URL.Path)
+		w.Write([]byte(`{"location":"westeurope","name":"orders-vm","resourceGroupName":"shop",` +
+			`"subscriptionId":"8d10da13","vmId":"02aab8a4","vmSize":"Standard_D2s_v3","zone":""}`))
+	}))
+	defer server.Close()
+
+	d := azureDetector{getenv: environment(nil), imdsURL: server.URL, client: server.Client()}
+	res, err := d.Detect(context.TODO())
+	assert.Nil(t, err)
+	assert.ElementsMatch(t, []attribute.KeyValue{
+		attribute.String("cloud.provider", "azure"),
+		attribute.String("cloud.platform", "azure.vm"),
+		attribute.String("cloud.region", "westeurope"),
+		attribute.String("cloud.account.id", "8d10da13"),
+		attribute.String("host.id", "02aab8a4"),
+		attribute.String("host.type", "Standard_D2s_v3"),
+		attribute.String("host.name", "orders-vm"),
+		attribute.String("azure.resource_group.name", "shop"),
+	}, res.Attributes())
+}
+
+func TestFirstDetector_SkipsOtherClouds(t *testing.T) {
+	unreachable := httptest.NewServer(http.NotFoundHandler())
+	defer unreachable.Close()
+	azure := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"location":"westeurope"}`))
+	}))
+	defer azure.Close()
+
+	res, err := firstDetector{
+		awsDetector{getenv: environment(nil), imdsURL: unreachable.URL, client: unreachable.Client()},
+		gcpDetector{getenv: environment(nil), metadataURL: unreachable.URL, client: unreachable.Client()},
+		azureDetector{getenv: environment(nil), imdsURL: azure.URL, client: azure.Client()},
+	}.Detect(context.TODO())
+	assert.Nil(t, err)
+	value, _ := res.Set().Value("cloud.provider")
+	assert.Equal(t, "azure", value.AsString())
+
+	res, _ = firstDetector{}.Detect(context.TODO())
+	assert.Zero(t, res.Len())
+}
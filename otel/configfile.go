@@ -0,0 +1,264 @@
+package otel
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/trace"
+	"go.yaml.in/yaml/v3"
+)
+
+// outputTypes are the output values of config files.
+var outputTypes = map[string]OutputType{
+	"io":         IO,
+	"grpc":       GRPC,
+	"prometheus": Prometheus,
+	"test":       Test,
+}
+
+// configFile is the layout of the files read by LoadConfig.
+type configFile struct {
+	Service struct {
+		Name        string `yaml:"name"`
+		Version     string `yaml:"version"`
+		InstanceID  string `yaml:"instance_id"`
+		Namespace   string `yaml:"namespace"`
+		Environment string `yaml:"environment"`
+	} `yaml:"service"`
+	Output   string `yaml:"output"`
+	Endpoint string `yaml:"endpoint"`
+	APIKey   string `yaml:"api_key"`
+	Insecure bool   `yaml:"insecure"`
+	TLS      struct {
+		CAFile   string `yaml:"ca_file"`
+		CertFile string `yaml:"cert_file"`
+		KeyFile  string `yaml:"key_file"`
+	} `yaml:"tls"`
+	Propagators        []string          `yaml:"propagators"`
+	ResourceDetectors  []string          `yaml:"resource_detectors"`
+	ResourceAttributes map[string]string `yaml:"resource_attributes"`
+	Processors         struct {
+		Redactions        []string          `yaml:"redactions"`
+		AllowedAttributes []string          `yaml:"allowed_attributes"`
+		DeniedAttributes  []string          `yaml:"denied_attributes"`
+		BaggageAttributes []string          `yaml:"baggage_attributes"`
+		SpanAttributes    map[string]string `yaml:"span_attributes"`
+		MaxSpanDuration   time.Duration     `yaml:"max_span_duration"`
+		ErrorTracesOnly   bool              `yaml:"error_traces_only"`
+		SlowSpanThreshold time.Duration     `yaml:"slow_span_threshold"`
+	} `yaml:"processors"`
+
+	ReloadableConfig `yaml:",inline"`
+}
+
+// LoadConfig reads the config file at path, YAML or JSON, overridden by the
+// environment variables read by NewENVConfig that are set:
+//
+//	service:
+//	  name: checkout
+//	  environment: production
+//	output: grpc
+//	endpoint: otlp.nr-data.net:4317
+//	tls:
+//	  ca_file: /etc/otel/ca.pem
+//	resource_attributes:
+//	  team: payments
+//	processors:
+//	  redactions: [email, credit_card]
+//	  denied_attributes: [http.request.header.*]
+//	  span_attributes:
+//	    cost_center: "42"
+//	  max_span_duration: 1h
+//
+// along with the ReloadableConfig settings, sampling_ratio becomes the
+// Config.Sampler. The output defaults to GRPC when an endpoint is set, IO
+// otherwise. The same file can be watched with WatchConfig. Unknown keys are
+// rejected to catch typos:
+//
+//	output, c, err := otel.LoadConfig("/etc/otel/otel.yaml")
+//	...
+//	exporter := otel.NewExporter(output, c)
+func LoadConfig(path string) (OutputType, *Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, nil, fmt.Errorf("could not read config: %w", err)
+	}
+
+	var f configFile
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&f); err != nil {
+		return 0, nil, fmt.Errorf("could not parse config %s: %w", path, err)
+	}
+
+	output := IO
+	if f.Endpoint != "" {
+		output = GRPC
+	}
+	if f.Output != "" {
+		var ok bool
+		if output, ok = outputTypes[f.Output]; !ok {
+			return 0, nil, fmt.Errorf("unsupported output %q", f.Output)
+		}
+	}
+	if r := f.SamplingRatio; r != nil && (*r < 0 || *r > 1) {
+		return 0, nil, fmt.Errorf("sampling_ratio must be between 0 and 1, got %v", *r)
+	}
+
+	c := &Config{
+		ServiceName:        f.Service.Name,
+		ServiceVersion:     f.Service.Version,
+		ServiceInstanceID:  f.Service.InstanceID,
+		ServiceNamespace:   f.Service.Namespace,
+		Environment:        f.Service.Environment,
+		URL:                f.Endpoint,
+		APIKey:             f.APIKey,
+		Insecure:           f.Insecure,
+		CAFile:             f.TLS.CAFile,
+		ClientCertFile:     f.TLS.CertFile,
+		ClientKeyFile:      f.TLS.KeyFile,
+		Propagators:        f.Propagators,
+		ResourceDetectors:  f.ResourceDetectors,
+		ResourceAttributes: stringAttributes(f.ResourceAttributes),
+		Redactions:         f.Processors.Redactions,
+		AllowedAttributes:  f.Processors.AllowedAttributes,
+		DeniedAttributes:   f.Processors.DeniedAttributes,
+		BaggageAttributes:  f.Processors.BaggageAttributes,
+		SpanAttributes:     stringAttributes(f.Processors.SpanAttributes),
+		MaxSpanDuration:    f.Processors.MaxSpanDuration,
+		ErrorTracesOnly:    f.Processors.ErrorTracesOnly,
+		SlowSpanThreshold:  f.Processors.SlowSpanThreshold,
+		MaxExportBatchSize: f.Batch.MaxExportBatchSize,
+		MaxQueueSize:       f.Batch.MaxQueueSize,
+		BatchTimeout:       f.Batch.Timeout,
+	}
+	if f.SamplingRatio != nil {
+		c.Sampler = trace.ParentBased(ConsistentProbabilitySampler(*f.SamplingRatio))
+	}
+	for _, s := range f.DropSpans {
+		c.DropSpans = append(c.DropSpans, ParseSpanMatcher(s))
+	}
+	if err := overrideConfig(c, NewENVConfig()); err != nil {
+		return 0, nil, err
+	}
+
+	return output, c, nil
+}
+
+// stringAttributes returns the attributes of m sorted by key.
+func stringAttributes(m map[string]string) []attribute.KeyValue {
+	var attrs []attribute.KeyValue
+	for key, value := range m {
+		attrs = append(attrs, attribute.String(key, value))
+	}
+	sort.Slice(attrs, func(i, j int) bool { return attrs[i].Key < attrs[j].Key })
+
+	return attrs
+}
+
+// envVars maps the Config fields read by NewENVConfig to their variable.
+var envVars = map[string]string{
+	"ServiceName":                "OTEL_SERVICE_NAME",
+	"ServiceVersion":             "OTEL_SERVICE_VERSION",
+	"ServiceInstanceID":          "OTEL_SERVICE_ID",
+	"ServiceNamespace":           "OTEL_SERVICE_NAMESPACE",
+	"Environment":                "OTEL_DEPLOYMENT_ENVIRONMENT",
+	"APIKey":                     "OTEL_GRPC_API_KEY",
+	"URL":                        "OTEL_GRPC_URL",
+	"Propagators":                "OTEL_PROPAGATORS",
+	"BatchJitter":                "OTEL_BATCH_JITTER",
+	"MaxSpanDuration":            "OTEL_MAX_SPAN_DURATION",
+	"BaggageAttributes":          "OTEL_BAGGAGE_ATTRIBUTES",
+	"ResourceDetectors":          "OTEL_RESOURCE_DETECTORS",
+	"SchemaURL":                  "OTEL_SCHEMA_URL",
+	"Redactions":                 "OTEL_REDACTIONS",
+	"AllowedAttributes":          "OTEL_ATTRIBUTES_ALLOW",
+	"DeniedAttributes":           "OTEL_ATTRIBUTES_DENY",
+	"DropSpans":                  "OTEL_DROP_SPANS",
+	"SpanAttributes":             "OTEL_SPAN_ATTRIBUTES",
+	"MaxAttributeLength":         "OTEL_ATTRIBUTE_MAX_LENGTH",
+	"AttributeLengthLimits":      "OTEL_ATTRIBUTE_LENGTH_LIMITS",
+	"MetricCardinalityLimit":     "OTEL_METRICS_CARDINALITY_LIMIT",
+	"MetricTemporality":          "OTEL_EXPORTER_OTLP_METRICS_TEMPORALITY_PREFERENCE",
+	"MetricHistogramAggregation": "OTEL_EXPORTER_OTLP_METRICS_DEFAULT_HISTOGRAM_AGGREGATION",
+	"Insecure":                   "OTEL_EXPORTER_OTLP_INSECURE",
+	"CAFile":                     "OTEL_EXPORTER_OTLP_CERTIFICATE",
+	"ClientCertFile":             "OTEL_EXPORTER_OTLP_CLIENT_CERTIFICATE",
+	"ClientKeyFile":              "OTEL_EXPORTER_OTLP_CLIENT_KEY",
+	"TLSMinVersion":              "OTEL_EXPORTER_OTLP_TLS_MIN_VERSION",
+	"TLSCipherSuites":            "OTEL_EXPORTER_OTLP_TLS_CIPHER_SUITES",
+	"TLSFIPS":                    "OTEL_EXPORTER_OTLP_TLS_FIPS",
+	"ProxyURL":                   "OTEL_EXPORTER_OTLP_PROXY",
+	"Endpoints":                  "OTEL_GRPC_ENDPOINTS",
+	"LoadBalance":                "OTEL_GRPC_LOAD_BALANCE",
+	"APIKeyFile":                 "OTEL_GRPC_API_KEY_FILE",
+	"MaxExportBatchBytes":        "OTEL_BATCH_MAX_EXPORT_BYTES",
+	"ExportWorkers":              "OTEL_BATCH_EXPORT_WORKERS",
+	"PriorityQueue":              "OTEL_BATCH_PRIORITY_QUEUE",
+	"BlockOnQueueFull":           "OTEL_BATCH_BLOCK_ON_QUEUE_FULL",
+	"MemoryLimit":                "OTEL_MEMORY_LIMIT_MIB",
+	"ErrorTracesOnly":            "OTEL_ERROR_TRACES_ONLY",
+	"SlowSpanThreshold":          "OTEL_SLOW_SPAN_THRESHOLD",
+	"DropRateThreshold":          "OTEL_DROP_RATE_THRESHOLD",
+	"DropRateWindow":             "OTEL_DROP_RATE_WINDOW",
+	"Disabled":                   "OTEL_SDK_DISABLED",
+}
+
+// overrideConfig sets the fields of c whose variable is set in the
+// environment to their value in overrides, even to a zero value like
+// OTEL_EXPORTER_OTLP_INSECURE=false. Invalid values are reported.
+func overrideConfig(c, overrides *Config) error {
+	var errs []error
+	dst := reflect.ValueOf(c).Elem()
+	src := reflect.ValueOf(overrides).Elem()
+	for i := 0; i < src.NumField(); i++ {
+		key, ok := envVars[src.Type().Field(i).Name]
+		if !ok {
+			continue
+		}
+		// empty values are unset, like in the OpenTelemetry specification
+		value, ok := os.LookupEnv(key)
+		if !ok || value == "" {
+			continue
+		}
+		if err := checkEnv(src.Field(i), value); err != nil {
+			errs = append(errs, fmt.Errorf("invalid %s %q, %w", key, value, err))
+			continue
+		}
+		dst.Field(i).Set(src.Field(i))
+	}
+
+	return errors.Join(errs...)
+}
+
+// checkEnv reports whether value can't be parsed into field, unlike the
+// helpers of NewENVConfig ignoring invalid values.
+func checkEnv(field reflect.Value, value string) error {
+	switch {
+	case field.Type() == reflect.TypeOf(time.Duration(0)):
+		if _, err := time.ParseDuration(value); err != nil {
+			return errors.New("expected a duration like 2s")
+		}
+	case field.Kind() == reflect.Bool:
+		if _, err := strconv.ParseBool(value); err != nil {
+			return errors.New("expected true or false")
+		}
+	case field.Kind() == reflect.Int, field.Kind() == reflect.Int64:
+		if _, err := strconv.Atoi(value); err != nil {
+			return errors.New("expected an integer")
+		}
+	case field.Kind() == reflect.Float64:
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return errors.New("expected a number")
+		}
+	}
+
+	return nil
+}
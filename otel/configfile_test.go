@@ -0,0 +1,110 @@
+package otel
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+func writeConfigFile(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "otel.yaml")
+	assert.Nil(t, os.WriteFile(path, []byte(content), 0o600))
+
+	return path
+}
+
+func TestLoadConfig_EnvOverridesWithZeroValues(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_OTLP_INSECURE", "false")
+	t.Setenv("OTEL_SERVICE_NAME", "")
+	path := writeConfigFile(t, `
+service:
+  name: checkout
+endpoint: collector:4317
+insecure: true
+`)
+
+	_, c, err := LoadConfig(path)
+	assert.Nil(t, err)
+	assert.False(t, c.Insecure)
+	assert.Equal(t, "checkout", c.ServiceName, "empty variables are unset")
+
+	t.Setenv("OTEL_BATCH_EXPORT_WORKERS", "four")
+	t.Setenv("OTEL_SLOW_SPAN_THRESHOLD", "2")
+	_, _, err = LoadConfig(path)
+	assert.EqualError(t, err, `invalid OTEL_BATCH_EXPORT_WORKERS "four", expected an integer
+invalid OTEL_SLOW_SPAN_THRESHOLD "2", expected a duration like 2s`)
+}
+
+func TestEnvVars_NameConfigFields(t *testing.T) {
+	for field := range envVars {
+		_, ok := reflect.TypeOf(Config{}).FieldByName(field)
+		assert.True(t, ok, field)
+	}
+}
+
+func TestLoadConfig_MergesEnvOverrides(t *testing.T) {
+	t.Setenv("OTEL_SERVICE_NAME", "checkout-canary")
+	t.Setenv("OTEL_GRPC_API_KEY", "from-env")
+	path := writeConfigFile(t, `
+service:
+  name: checkout
+  environment: production
+endpoint: otlp.nr-data.net:4317
+api_key: from-file
+tls:
+  ca_file: /etc/otel/ca.pem
+resource_attributes:
+  team: payments
+  build: abc123
+processors:
+  redactions: [email]
+  span_attributes:
+    cost_center: "42"
+  max_span_duration: 1h
+sampling_ratio: 0.25
+drop_spans: ["GET /healthz"]
+batch:
+  max_queue_size: 4096
+  timeout: 2s
+`)
+
+	output, c, err := LoadConfig(path)
+	assert.Nil(t, err)
+	assert.Equal(t, GRPC, output)
+	assert.Equal(t, "checkout-canary", c.ServiceName)
+	assert.Equal(t, "production", c.Environment)
+	assert.Equal(t, "from-env", c.APIKey)
+	assert.Equal(t, "otlp.nr-data.net:4317", c.URL)
+	assert.Equal(t, "/etc/otel/ca.pem", c.CAFile)
+	assert.Equal(t, []attribute.KeyValue{attribute.String("build", "abc123"), attribute.String("team", "payments")}, c.ResourceAttributes)
+	assert.Equal(t, []attribute.KeyValue{attribute.String("cost_center", "42")}, c.SpanAttributes)
+	assert.Equal(t, []string{"email"}, c.Redactions)
+	assert.Equal(t, time.Hour, c.MaxSpanDuration)
+	assert.Equal(t, []SpanMatcher{{Name: "GET /healthz"}}, c.DropSpans)
+	assert.Equal(t, 4096, c.MaxQueueSize)
+	assert.Equal(t, 2*time.Second, c.BatchTimeout)
+	assert.Contains(t, c.Sampler.Description(), "ConsistentProbabilitySampler{0.25}")
+}
+
+func TestLoadConfig_JSON(t *testing.T) {
+	output, c, err := LoadConfig(writeConfigFile(t, `{"service": {"name": "checkout"}, "output": "test"}`))
+	assert.Nil(t, err)
+	assert.Equal(t, Test, output)
+	assert.Equal(t, "checkout", c.ServiceName)
+}
+
+func TestLoadConfig_RejectsInvalidFiles(t *testing.T) {
+	_, _, err := LoadConfig(writeConfigFile(t, "servce:\n  name: checkout\n"))
+	assert.ErrorContains(t, err, "field servce not found")
+
+	_, _, err = LoadConfig(writeConfigFile(t, "output: http\n"))
+	assert.EqualError(t, err, `unsupported output "http"`)
+
+	_, _, err = LoadConfig(writeConfigFile(t, "sampling_ratio: 2\n"))
+	assert.EqualError(t, err, "sampling_ratio must be between 0 and 1, got 2")
+}
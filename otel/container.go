@@ -0,0 +1,61 @@
+package otel
+
+import (
+	"bufio"
+	"context"
+	"os"
+	"regexp"
+
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.43.0"
+)
+
+var (
+	// cgroupContainerID matches the ID ending a cgroup v1 path like
+	// /docker/<id>, /kubepods/.../cri-containerd-<id>.scope or /crio-<id>.scope.
+	cgroupContainerID = regexp.MustCompile(`([0-9a-f]{64})(?:\.scope)?$`)
+
+	// mountinfoContainerID matches the ID in the mount source of the files
+	// runtimes bind into containers under cgroup v2, e.g. /var/lib/docker/containers/<id>/hostname.
+	// The files of containerd under sandboxes/<id> belong to the pause container of the pod.
+	mountinfoContainerID = regexp.MustCompile(`/containers/([0-9a-f]{64})/`)
+)
+
+// containerDetector sets container.id from the cgroup of the process,
+// falling back to its mounts for cgroup v2 hosts.
+type containerDetector struct {
+	cgroupPath    string
+	mountinfoPath string
+}
+
+// Detect implements the resource.Detector interface.
+func (d containerDetector) Detect(context.Context) (*resource.Resource, error) {
+	id := scanContainerID(d.cgroupPath, cgroupContainerID)
+	if id == "" {
+		id = scanContainerID(d.mountinfoPath, mountinfoContainerID)
+	}
+	if id == "" {
+		return resource.Empty(), nil
+	}
+
+	return resource.NewSchemaless(semconv.ContainerIDKey.String(id)), nil
+}
+
+// scanContainerID returns the first ID matched in the lines of path, the file
+// is missing outside of Linux which is the same as not running in a container.
+func scanContainerID(path string, pattern *regexp.Regexp) string {
+	f, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if m := pattern.FindStringSubmatch(scanner.Text()); m != nil {
+			return m[1]
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,126 @@
+package otel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.43.0"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+// Deployment attributes recorded by RecordDeployment.
+const (
+	DeploymentVersionKey = attribute.Key("deployment.version")
+	DeploymentCommitKey  = attribute.Key("deployment.commit")
+	DeploymentActorKey   = attribute.Key("deployment.actor")
+)
+
+// legacyDeploymentEnvironmentKey is the deployment.environment attribute
+// renamed deployment.environment.name by semconv 1.27.0, recorded along with
+// it until the backends querying it, like New Relic, moved to the new one.
+const legacyDeploymentEnvironmentKey = attribute.Key("deployment.environment")
+
+// Deployment describes a release of the service, Version and Environment
+// default to Config.ServiceVersion and Config.Environment.
+type Deployment struct {
+	Version     string
+	Commit      string
+	Actor       string
+	Environment string
+}
+
+func (d Deployment) attributes() []attribute.KeyValue {
+	attrs := []attribute.KeyValue{DeploymentVersionKey.String(d.Version)}
+	if d.Commit != "" {
+		attrs = append(attrs, DeploymentCommitKey.String(d.Commit))
+	}
+	if d.Actor != "" {
+		attrs = append(attrs, DeploymentActorKey.String(d.Actor))
+	}
+	if d.Environment != "" {
+		attrs = append(attrs,
+			semconv.DeploymentEnvironmentNameKey.String(d.Environment),
+			legacyDeploymentEnvironmentKey.String(d.Environment),
+		)
+	}
+
+	return attrs
+}
+
+// deploymentScope is the instrumentation scope of the deployment markers.
+const deploymentScope = instrumentationName + "/deployment"
+
+// deploymentMarkerKey marks the context of the deployment markers so
+// deploymentSampler records them.
+type deploymentMarkerKey struct{}
+
+// isDeploymentMarker reports whether s is a span of RecordDeployment, kept
+// by the processors dropping spans.
+func isDeploymentMarker(s trace.ReadOnlySpan) bool {
+	return s.InstrumentationScope().Name == deploymentScope
+}
+
+// deploymentSampler samples the deployment markers whatever the samplers of
+// the pipeline would decide.
+type deploymentSampler struct {
+	next trace.Sampler
+}
+
+// ShouldSample implements the trace.Sampler interface.
+func (s deploymentSampler) ShouldSample(p trace.SamplingParameters) trace.SamplingResult {
+	if p.ParentContext.Value(deploymentMarkerKey{}) != nil {
+		return trace.AlwaysSample().ShouldSample(p)
+	}
+
+	return s.next.ShouldSample(p)
+}
+
+// Description implements the trace.Sampler interface.
+func (s deploymentSampler) Description() string {
+	return s.next.Description()
+}
+
+// RecordDeployment emits a "deployment" span, with a deployment event, through
+// the pipeline built by e and flushes it, so backends can draw deploy markers.
+// The span is recorded regardless of the sampling, DropSpans, SetEnabled and
+// ErrorTracesOnly, an error is returned when it still isn't, like with a
+// disabled pipeline. Call it once at startup after ExportPipeline.
+func RecordDeployment(ctx context.Context, e Exporter, d Deployment) error {
+	p, ok := pipelineOf(e)
+	if !ok {
+		return errors.New("unsupported exporter")
+	}
+
+	provider := p.current()
+	if provider == nil {
+		return errors.New("pipeline not started")
+	}
+
+	if d.Version == "" {
+		d.Version = p.config.serviceVersion()
+	}
+	if d.Environment == "" {
+		d.Environment = p.config.Environment
+	}
+	attrs := d.attributes()
+
+	ctx = context.WithValue(ctx, deploymentMarkerKey{}, true)
+	_, span := provider.Tracer(deploymentScope).Start(ctx, "deployment",
+		oteltrace.WithNewRoot(),
+		oteltrace.WithAttributes(attrs...),
+	)
+	span.AddEvent("deployment", oteltrace.WithAttributes(attrs...))
+	span.End()
+	if !span.SpanContext().IsSampled() {
+		return errors.New("deployment marker not recorded by the pipeline")
+	}
+
+	if err := provider.ForceFlush(ctx); err != nil {
+		return fmt.Errorf("could not flush provider: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,63 @@
+package otel
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/sdk/trace"
+)
+
+func TestRecordDeployment_EmitsMarker(t *testing.T) {
+	setEnv()
+	defer unsetEnv()
+
+	var output bytes.Buffer
+	c := NewENVConfig()
+	c.Writer = &output
+	exporter := NewExporter(IO, c, WithGlobalRegistration(false))
+
+	assert.EqualError(t, RecordDeployment(context.TODO(), exporter, Deployment{}), "pipeline not started")
+
+	provider, err := exporter.ExportPipeline(context.TODO())
+	assert.Nil(t, err)
+	defer provider.Shutdown(context.TODO())
+
+	assert.Nil(t, RecordDeployment(context.TODO(), exporter, Deployment{Commit: "5b03e96", Actor: "ci"}))
+	assert.Contains(t, output.String(), `"Name":"deployment"`)
+	assert.Contains(t, output.String(), `"Key":"deployment.version","Value":{"Type":"STRING","Value":"v1.0.0.0"}`)
+	assert.Contains(t, output.String(), `"Key":"deployment.commit","Value":{"Type":"STRING","Value":"5b03e96"}`)
+}
+
+func TestRecordDeployment_SkipsSamplingAndFilters(t *testing.T) {
+	var output bytes.Buffer
+	exporter := NewExporter(IO, &Config{
+		Writer:          &output,
+		Sampler:         trace.TraceIDRatioBased(0),
+		DropSpans:       []SpanMatcher{{Name: "deployment"}},
+		ErrorTracesOnly: true,
+	}, WithGlobalRegistration(false))
+	provider, err := exporter.ExportPipeline(context.TODO())
+	assert.Nil(t, err)
+	defer provider.Shutdown(context.TODO())
+	SetEnabled(false)
+	defer SetEnabled(true)
+
+	assert.Nil(t, RecordDeployment(context.TODO(), exporter, Deployment{Version: "v2"}))
+	assert.Contains(t, output.String(), `"Name":"deployment"`)
+
+	_, span := provider.Tracer("sample").Start(context.TODO(), "sample span")
+	span.End()
+	assert.Nil(t, provider.ForceFlush(context.TODO()))
+	assert.NotContains(t, output.String(), "sample span")
+}
+
+func TestRecordDeployment_ReportsUnrecordedMarker(t *testing.T) {
+	exporter := NewExporter(IO, &Config{Writer: &bytes.Buffer{}, Disabled: true}, WithGlobalRegistration(false))
+	provider, err := exporter.ExportPipeline(context.TODO())
+	assert.Nil(t, err)
+	defer provider.Shutdown(context.TODO())
+
+	assert.EqualError(t, RecordDeployment(context.TODO(), exporter, Deployment{Version: "v2"}), "deployment marker not recorded by the pipeline")
+}
@@ -0,0 +1,90 @@
+package otel
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.43.0"
+)
+
+// detectors are the supported Config.ResourceDetectors and OTEL_RESOURCE_DETECTORS values.
+var detectors = map[string]func() []resource.Option{
+	"process": func() []resource.Option {
+		// command line arguments and owner are left out, they may hold secrets
+		return []resource.Option{
+			resource.WithProcessPID(),
+			resource.WithProcessExecutableName(),
+			resource.WithProcessExecutablePath(),
+			resource.WithProcessRuntimeName(),
+			resource.WithProcessRuntimeVersion(),
+			resource.WithProcessRuntimeDescription(),
+		}
+	},
+	"host": func() []resource.Option {
+		return []resource.Option{
+			resource.WithHost(),
+			resource.WithAttributes(semconv.HostArchKey.String(runtime.GOARCH)),
+		}
+	},
+	"os": func() []resource.Option {
+		return []resource.Option{resource.WithOS()}
+	},
+	"container": func() []resource.Option {
+		return []resource.Option{resource.WithDetectors(containerDetector{
+			cgroupPath:    "/proc/self/cgroup",
+			mountinfoPath: "/proc/self/mountinfo",
+		})}
+	},
+	"kubernetes": func() []resource.Option {
+		return []resource.Option{resource.WithDetectors(kubernetesDetector{
+			getenv:            os.Getenv,
+			serviceAccountDir: "/var/run/secrets/kubernetes.io/serviceaccount",
+		})}
+	},
+	"aws": func() []resource.Option {
+		return []resource.Option{resource.WithDetectors(newAWSDetector())}
+	},
+	"gcp": func() []resource.Option {
+		return []resource.Option{resource.WithDetectors(newGCPDetector())}
+	},
+	"azure": func() []resource.Option {
+		return []resource.Option{resource.WithDetectors(newAzureDetector())}
+	},
+	"cloud": func() []resource.Option {
+		return []resource.Option{resource.WithDetectors(firstDetector{
+			newAWSDetector(),
+			newGCPDetector(),
+			newAzureDetector(),
+		})}
+	},
+}
+
+func newAWSDetector() awsDetector {
+	return awsDetector{getenv: os.Getenv, imdsURL: "http://169.254.169.254", client: metadataClient}
+}
+
+func newGCPDetector() gcpDetector {
+	return gcpDetector{getenv: os.Getenv, metadataURL: "http://metadata.google.internal", client: metadataClient}
+}
+
+func newAzureDetector() azureDetector {
+	return azureDetector{getenv: os.Getenv, imdsURL: "http://169.254.169.254", client: metadataClient}
+}
+
+// detectorOptions returns the resource options of Config.ResourceDetectors.
+func (c *Config) detectorOptions() ([]resource.Option, error) {
+	var opts []resource.Option
+	for _, name := range c.ResourceDetectors {
+		name = strings.ToLower(strings.TrimSpace(name))
+		detector, ok := detectors[name]
+		if !ok {
+			return nil, fmt.Errorf("unsupported resource detector %q", name)
+		}
+		opts = append(opts, detector()...)
+	}
+
+	return opts, nil
+}
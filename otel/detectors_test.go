@@ -0,0 +1,147 @@
+package otel
+
+import (
+	"context"
+	"io"
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+func TestResourceDetectors_AddAttributes(t *testing.T) {
+	c := &Config{ServiceName: "orders", ResourceDetectors: []string{"process", " Host", "os"}}
+	res, err := c.resource(context.TODO())
+	assert.Nil(t, err)
+
+	attrs := res.Set()
+	value, _ := attrs.Value("process.pid")
+	assert.Equal(t, int64(os.Getpid()), value.AsInt64())
+	value, _ = attrs.Value("host.arch")
+	assert.Equal(t, runtime.GOARCH, value.AsString())
+	for _, key := range []attribute.Key{"process.runtime.version", "host.name", "os.type", "service.name"} {
+		assert.True(t, attrs.HasValue(key), key)
+	}
+	assert.False(t, attrs.HasValue("process.command_args"))
+}
+
+func TestResourceDetectors_Unsupported(t *testing.T) {
+	c := &Config{Writer: os.Stdout, ResourceDetectors: []string{"mainframe"}}
+	_, err := NewExporter(IO, c).ExportPipeline(context.TODO())
+	assert.EqualError(t, err, `unsupported resource detector "mainframe"`)
+}
+
+func TestContainerDetector_ReadsContainerID(t *testing.T) {
+	const id = "2f7e4b7a1c3d9e8f6a5b4c3d2e1f0a9b8c7d6e5f4a3b2c1d0e9f8a7b6c5d4e3f"
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := dir + "/" + name
+		assert.Nil(t, os.WriteFile(path, []byte(content), 0o600))
+		return path
+	}
+
+	cgroupV1 := write("cgroup-v1", "12:cpu,cpuacct:/kubepods/burstable/pod1234/cri-containerd-"+id+".scope\n")
+	cgroupV2 := write("cgroup-v2", "0::/\n")
+	mountinfo := write("mountinfo", "1472 1461 0:23 / /sys rw - sysfs sysfs rw\n"+
+		"1489 1461 254:1 /var/lib/containerd/io.containerd.grpc.v1.cri/sandboxes/"+strings.Repeat("ab", 32)+"/hostname /etc/hostname rw - ext4 /dev/vda1 rw\n"+
+		"1490 1461 254:1 /var/lib/containerd/io.containerd.grpc.v1.cri/containers/"+id+"/termination-log /dev/termination-log rw - ext4 /dev/vda1 rw\n")
+
+	for name, d := range map[string]containerDetector{
+		"cgroup v1": {cgroupPath: cgroupV1, mountinfoPath: dir + "/missing"},
+		"cgroup v2": {cgroupPath: cgroupV2, mountinfoPath: mountinfo},
+	} {
+		res, err := d.Detect(context.TODO())
+		assert.Nil(t, err)
+		value, _ := res.Set().Value("container.id")
+		assert.Equal(t, id, value.AsString(), name)
+	}
+
+	res, err := containerDetector{cgroupPath: cgroupV2, mountinfoPath: dir + "/missing"}.Detect(context.TODO())
+	assert.Nil(t, err)
+	assert.Zero(t, res.Len())
+}
+
+func TestKubernetesDetector_ReadsPodInfo(t *testing.T) {
+	dir := t.TempDir()
+	assert.Nil(t, os.WriteFile(dir+"/namespace", []byte("shop\n"), 0o600))
+
+	env := map[string]string{
+		"KUBERNETES_SERVICE_HOST": "10.0.0.1",
+		"HOSTNAME":                "orders-5d8f7c9b4-x2k9p",
+		"K8S_NODE_NAME":           "node-1",
+	}
+	d := kubernetesDetector{getenv: func(key string) string { return env[key] }, serviceAccountDir: dir}
+
+	res, err := d.Detect(context.TODO())
+	assert.Nil(t, err)
+	assert.ElementsMatch(t, []attribute.KeyValue{
+		attribute.String("k8s.pod.name", "orders-5d8f7c9b4-x2k9p"),
+		attribute.String("k8s.namespace.name", "shop"),
+		attribute.String("k8s.node.name", "node-1"),
+		attribute.String("k8s.deployment.name", "orders"),
+	}, res.Attributes())
+
+	env["K8S_NAMESPACE_NAME"] = "checkout"
+	env["K8S_DEPLOYMENT_NAME"] = "orders-api"
+	res, _ = d.Detect(context.TODO())
+	value, _ := res.Set().Value("k8s.namespace.name")
+	assert.Equal(t, "checkout", value.AsString())
+	value, _ = res.Set().Value("k8s.deployment.name")
+	assert.Equal(t, "orders-api", value.AsString())
+
+	delete(env, "KUBERNETES_SERVICE_HOST")
+	res, _ = d.Detect(context.TODO())
+	assert.Zero(t, res.Len())
+}
+
+func TestResource_CustomAttributesAndDetectors(t *testing.T) {
+	c := &Config{
+		ServiceName: "orders",
+		Detectors: []resource.Detector{
+			resource.StringDetector("", "team", func() (string, error) { return "detected", nil }),
+			resource.StringDetector("", "region", func() (string, error) { return "eu", nil }),
+		},
+		ResourceAttributes: []attribute.KeyValue{
+			attribute.String("team", "payments"),
+			attribute.String("build.sha", "4f2a9c1"),
+		},
+	}
+	res, err := c.resource(context.TODO())
+	assert.Nil(t, err)
+
+	attrs := res.Set()
+	for key, expected := range map[attribute.Key]string{
+		"service.name": "orders",
+		"team":         "payments",
+		"build.sha":    "4f2a9c1",
+		"region":       "eu",
+	} {
+		value, _ := attrs.Value(key)
+		assert.Equal(t, expected, value.AsString(), key)
+	}
+}
+
+func TestResource_DetectedOncePerPipeline(t *testing.T) {
+	var detections int
+	exporter := NewExporter(IO, &Config{
+		Writer: io.Discard,
+		Detectors: []resource.Detector{resource.StringDetector("", "region", func() (string, error) {
+			detections++
+			return "eu", nil
+		})},
+	}, WithGlobalRegistration(false))
+
+	provider, err := exporter.ExportPipeline(context.TODO())
+	assert.Nil(t, err)
+	defer provider.Shutdown(context.TODO())
+	_, err = ExportMetricPipeline(context.TODO(), exporter)
+	assert.Nil(t, err)
+	_, err = ExportLogPipeline(context.TODO(), exporter)
+	assert.Nil(t, err)
+
+	assert.Equal(t, 1, detections)
+}
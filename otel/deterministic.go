@@ -0,0 +1,83 @@
+package otel
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"sort"
+)
+
+// sortedJSONWriter rewrites every JSON document written to it, one per line,
+// with sorted object keys and attribute lists sorted by key.
+type sortedJSONWriter struct {
+	w   io.Writer
+	buf []byte
+}
+
+func (w *sortedJSONWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			return len(p), nil
+		}
+		line := w.buf[:i+1]
+		if err := w.writeDocument(line); err != nil {
+			return len(p), err
+		}
+		w.buf = w.buf[i+1:]
+	}
+}
+
+func (w *sortedJSONWriter) writeDocument(line []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(line))
+	decoder.UseNumber()
+	var doc interface{}
+	if err := decoder.Decode(&doc); err != nil {
+		// not JSON, like a dry run summary, written as is
+		_, err = w.w.Write(line)
+		return err
+	}
+
+	sortAttributes(doc)
+	sorted, err := json.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	_, err = w.w.Write(append(sorted, '\n'))
+
+	return err
+}
+
+// sortAttributes sorts the lists of key values of doc by key, objects keys
+// are sorted by json.Marshal.
+func sortAttributes(doc interface{}) {
+	switch v := doc.(type) {
+	case map[string]interface{}:
+		for _, value := range v {
+			sortAttributes(value)
+		}
+	case []interface{}:
+		for _, value := range v {
+			sortAttributes(value)
+		}
+		key := func(i int) (string, bool) {
+			kv, ok := v[i].(map[string]interface{})
+			if !ok {
+				return "", false
+			}
+			k, ok := kv["Key"].(string)
+			return k, ok
+		}
+		for i := range v {
+			if _, ok := key(i); !ok {
+				return
+			}
+		}
+		sort.SliceStable(v, func(i, j int) bool {
+			a, _ := key(i)
+			b, _ := key(j)
+			return a < b
+		})
+	}
+}
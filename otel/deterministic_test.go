@@ -0,0 +1,54 @@
+package otel
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+func TestIOOutput_DeterministicOutput(t *testing.T) {
+	run := func(attrs ...attribute.KeyValue) string {
+		var out bytes.Buffer
+		c := &Config{
+			ServiceName:   "orders",
+			Writer:        &out,
+			Deterministic: true,
+			IDGenerator:   NewSequentialIDGenerator(),
+		}
+		pipeline, err := NewExporter(IO, c, WithGlobalRegistration(false)).ExportPipeline(context.TODO())
+		assert.Nil(t, err)
+
+		ctx, parent := pipeline.Tracer("sample").Start(context.TODO(), "parent", oteltrace.WithAttributes(attrs...))
+		_, child := pipeline.Tracer("sample").Start(ctx, "child")
+		child.AddEvent("cache miss")
+		child.End()
+		parent.End()
+		assert.Nil(t, pipeline.Shutdown(context.TODO()))
+
+		return out.String()
+	}
+
+	first := run(attribute.String("b", "2"), attribute.String("a", "1"))
+	assert.Equal(t, first, run(attribute.String("a", "1"), attribute.String("b", "2")))
+	assert.Contains(t, first, `"Attributes":[{"Key":"a","Value":{"Type":"STRING","Value":"1"}},{"Key":"b"`)
+	assert.Contains(t, first, `"EndTime":"0001-01-01T00:00:00Z"`)
+	assert.Contains(t, first, `"TraceID":"00000000000000000000000000000001"`)
+	assert.Contains(t, first, `{"Key":"service.instance.id","Value":{"Type":"STRING","Value":"d2466ee2-52d9-5083-a1e9-60a0d796e9c6"}}`)
+	assert.NotContains(t, first, processInstanceID())
+}
+
+func TestSortedJSONWriter_KeepsOtherLines(t *testing.T) {
+	var out bytes.Buffer
+	w := &sortedJSONWriter{w: &out}
+
+	_, err := w.Write([]byte(`{"b":1,"a":12345678901234567890}` + "\ndry run: "))
+	assert.Nil(t, err)
+	_, err = w.Write([]byte("1 span\n"))
+	assert.Nil(t, err)
+
+	assert.Equal(t, "{\"a\":12345678901234567890,\"b\":1}\ndry run: 1 span\n", out.String())
+}
@@ -0,0 +1,68 @@
+package otel
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"strings"
+
+	"go.opentelemetry.io/otel/sdk/trace"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+// orphanGuard reports the call sites starting root spans which aren't entry
+// points, usually a lost context like context.Background() in a request path.
+//
+// Server and consumer spans are legitimate roots, other root spans are
+// reported to the otel error handler.
+type orphanGuard struct{}
+
+// OnStart implements the trace.SpanProcessor interface.
+func (orphanGuard) OnStart(_ context.Context, s trace.ReadWriteSpan) {
+	if s.Parent().IsValid() {
+		return
+	}
+	switch s.SpanKind() {
+	case oteltrace.SpanKindServer, oteltrace.SpanKindConsumer:
+		return
+	}
+
+	handle(errorKindDevMode, fmt.Errorf("span %q started without a parent at %s", s.Name(), callSite()))
+}
+
+// callSite returns the first frame outside the otel packages.
+func callSite() string {
+	pcs := make([]uintptr, 32)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(3, pcs)])
+	for {
+		frame, more := frames.Next()
+		if !isOtelFrame(frame) {
+			return fmt.Sprintf("%s (%s:%d)", frame.Function, frame.File, frame.Line)
+		}
+		if !more {
+			return "unknown"
+		}
+	}
+}
+
+func isOtelFrame(frame runtime.Frame) bool {
+	if strings.HasSuffix(frame.File, "_test.go") {
+		return false
+	}
+
+	return strings.HasPrefix(frame.Function, "go.opentelemetry.io/") ||
+		strings.HasPrefix(frame.Function, "github.com/rezazadehramin/opentelemetry-go/otel")
+}
+
+// OnEnd implements the trace.SpanProcessor interface.
+func (orphanGuard) OnEnd(trace.ReadOnlySpan) {}
+
+// Shutdown implements the trace.SpanProcessor interface.
+func (orphanGuard) Shutdown(context.Context) error {
+	return nil
+}
+
+// ForceFlush implements the trace.SpanProcessor interface.
+func (orphanGuard) ForceFlush(context.Context) error {
+	return nil
+}
@@ -0,0 +1,42 @@
+package otel
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+func TestDevMode_ReportsOrphanSpans(t *testing.T) {
+	setEnv()
+	defer unsetEnv()
+	defer otel.SetErrorHandler(otel.GetErrorHandler())
+
+	var reported []error
+	c := NewENVConfig()
+	c.Writer = io.Discard
+	c.DevMode = true
+	c.ErrorRateLimit = -1
+	c.ErrorHandler = func(err error) { reported = append(reported, err) }
+
+	provider, err := NewExporter(IO, c).ExportPipeline(context.TODO())
+	assert.Nil(t, err)
+	defer provider.Shutdown(context.TODO())
+	tracer := provider.Tracer("sample")
+
+	ctx, server := tracer.Start(context.TODO(), "server", oteltrace.WithSpanKind(oteltrace.SpanKindServer))
+	_, child := tracer.Start(ctx, "child")
+	child.End()
+	server.End()
+	assert.Empty(t, reported)
+
+	_, orphan := tracer.Start(context.Background(), "orphan")
+	orphan.End()
+
+	assert.Len(t, reported, 1)
+	assert.Contains(t, reported[0].Error(), `span "orphan" started without a parent at`)
+	assert.Contains(t, reported[0].Error(), "devmode_test.go")
+}
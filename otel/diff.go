@@ -0,0 +1,142 @@
+package otel
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+)
+
+// secretFields are Config fields whose values must never be printed,
+// a drift on them is reported without exposing the actual values.
+var secretFields = map[string]bool{
+	"APIKey": true,
+}
+
+// ConfigDiff describes a single Config field whose value
+// differs between the running and the desired configuration.
+type ConfigDiff struct {
+	Field   string
+	Current string
+	Desired string
+}
+
+// String returns a human readable representation of the difference.
+func (d ConfigDiff) String() string {
+	return fmt.Sprintf("%s: %q -> %q", d.Field, d.Current, d.Desired)
+}
+
+// Diff compares c, the effective config of a running pipeline, to the
+// desired config and reports every field that differs.
+//
+// An empty result means there is no drift. A nil config is treated as
+// the zero Config. Functions and writers are compared by identity.
+func (c *Config) Diff(desired *Config) []ConfigDiff {
+	current := reflect.ValueOf(Config{})
+	if c != nil {
+		current = reflect.ValueOf(*c)
+	}
+
+	want := reflect.ValueOf(Config{})
+	if desired != nil {
+		want = reflect.ValueOf(*desired)
+	}
+
+	var diffs []ConfigDiff
+	t := current.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		a, b := current.Field(i), want.Field(i)
+		if equalField(a, b) {
+			continue
+		}
+
+		diff := ConfigDiff{Field: field.Name}
+		if secretFields[field.Name] {
+			diff.Current, diff.Desired = redact(a), redact(b)
+		} else if field.Name == "ProxyURL" {
+			diff.Current, diff.Desired = redactURL(a.String()), redactURL(b.String())
+		} else {
+			diff.Current, diff.Desired = formatField(a), formatField(b)
+		}
+		diffs = append(diffs, diff)
+	}
+
+	return diffs
+}
+
+func equalField(a, b reflect.Value) bool {
+	switch a.Kind() {
+	case reflect.Func:
+		return a.Pointer() == b.Pointer()
+	case reflect.Interface:
+		if a.IsNil() || b.IsNil() {
+			return a.IsNil() == b.IsNil()
+		}
+		if a.Elem().Type() != b.Elem().Type() {
+			return false
+		}
+		if a.Elem().Comparable() {
+			return a.Interface() == b.Interface()
+		}
+	case reflect.Slice:
+		if a.Len() != b.Len() {
+			return false
+		}
+		for i := 0; i < a.Len(); i++ {
+			if !equalField(a.Index(i), b.Index(i)) {
+				return false
+			}
+		}
+		return true
+	case reflect.Struct:
+		// Comparable structs, like attribute.KeyValue, may have unexported
+		// fields whose values can't be read.
+		if a.Comparable() {
+			return a.Equal(b)
+		}
+		for i := 0; i < a.NumField(); i++ {
+			if !equalField(a.Field(i), b.Field(i)) {
+				return false
+			}
+		}
+		return true
+	}
+
+	return reflect.DeepEqual(a.Interface(), b.Interface())
+}
+
+func formatField(v reflect.Value) string {
+	switch v.Kind() {
+	case reflect.String:
+		return v.String()
+	case reflect.Func, reflect.Interface, reflect.Ptr:
+		if v.IsNil() {
+			return "<nil>"
+		}
+		return fmt.Sprintf("%T", v.Interface())
+	}
+
+	return fmt.Sprint(v.Interface())
+}
+
+func redact(v reflect.Value) string {
+	if v.IsZero() {
+		return ""
+	}
+
+	return "<redacted>"
+}
+
+// redactURL hides the password of the user of s, like the proxy credentials.
+func redactURL(s string) string {
+	u, err := url.Parse(s)
+	if err != nil {
+		return redact(reflect.ValueOf(s))
+	}
+
+	return u.Redacted()
+}
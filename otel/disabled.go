@@ -0,0 +1,43 @@
+package otel
+
+import (
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/log/global"
+	lognoop "go.opentelemetry.io/otel/log/noop"
+	metricnoop "go.opentelemetry.io/otel/metric/noop"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	"go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/trace"
+	tracenoop "go.opentelemetry.io/otel/trace/noop"
+)
+
+// The providers of the pipelines built with Config.Disabled have no processor,
+// exporter or reader, the no-op providers of the API are registered globally
+// instead so instrumentations don't even create spans or measurements.
+
+func (p *pipeline) disabledTracerProvider() *trace.TracerProvider {
+	p.provider = trace.NewTracerProvider(trace.WithSampler(trace.NeverSample()))
+	if p.globalRegistration {
+		otel.SetTracerProvider(tracenoop.NewTracerProvider())
+	}
+
+	return p.provider
+}
+
+func (p *pipeline) disabledMeterProvider() *metric.MeterProvider {
+	p.meterProvider = metric.NewMeterProvider()
+	if p.globalRegistration {
+		otel.SetMeterProvider(metricnoop.NewMeterProvider())
+	}
+
+	return p.meterProvider
+}
+
+func (p *pipeline) disabledLoggerProvider() *sdklog.LoggerProvider {
+	p.loggerProvider = sdklog.NewLoggerProvider()
+	if p.globalRegistration {
+		global.SetLoggerProvider(lognoop.NewLoggerProvider())
+	}
+
+	return p.loggerProvider
+}
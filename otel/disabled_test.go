@@ -0,0 +1,47 @@
+package otel
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/log/global"
+)
+
+func TestDisabled_BuildsNoopPipelines(t *testing.T) {
+	defer otel.SetTracerProvider(otel.GetTracerProvider())
+	defer otel.SetMeterProvider(otel.GetMeterProvider())
+	defer global.SetLoggerProvider(global.GetLoggerProvider())
+	t.Setenv("OTEL_SDK_DISABLED", "true")
+
+	c := NewENVConfig()
+	assert.True(t, c.Disabled)
+	// an unsupported propagator and unreachable endpoint aren't even looked at
+	c.Propagators = []string{"unknown"}
+	c.URL = "unreachable.invalid:4317"
+	exporter := NewExporter(GRPC, c, WithRuntimeMetrics())
+
+	provider, err := exporter.ExportPipeline(context.TODO())
+	assert.Nil(t, err)
+	_, span := provider.Tracer("sample").Start(context.TODO(), "sample span")
+	assert.False(t, span.IsRecording())
+	span.End()
+
+	_, span = otel.Tracer("sample").Start(context.TODO(), "sample span")
+	assert.False(t, span.SpanContext().IsValid())
+
+	meterProvider, err := ExportMetricPipeline(context.TODO(), exporter)
+	assert.Nil(t, err)
+	loggerProvider, err := ExportLogPipeline(context.TODO(), exporter)
+	assert.Nil(t, err)
+
+	snapshot, _ := Snapshot(exporter)
+	assert.Equal(t, "none", snapshot.Exporter)
+	assert.Empty(t, snapshot.Processors)
+	assert.Equal(t, int64(0), snapshot.Counters.SpansStarted)
+
+	assert.Nil(t, provider.Shutdown(context.TODO()))
+	assert.Nil(t, meterProvider.Shutdown(context.TODO()))
+	assert.Nil(t, loggerProvider.Shutdown(context.TODO()))
+}
@@ -0,0 +1,98 @@
+package otel
+
+import (
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/trace"
+)
+
+// SpanMatcher matches the spans whose name is Name, when set, and whose start
+// attribute Key, when set, has the string value Value. Name and Value ending
+// with "*" match every span name or value of that prefix, like "GET /static/*".
+type SpanMatcher struct {
+	Name  string
+	Key   attribute.Key
+	Value string
+}
+
+// HealthCheckSpans matches the server spans of the health check, readiness,
+// liveness and metrics endpoints, by the url.path or http.target attribute
+// set by the HTTP instrumentations.
+var HealthCheckSpans = func() []SpanMatcher {
+	var matchers []SpanMatcher
+	for _, path := range []string{"/healthz", "/health", "/readyz", "/livez", "/metrics"} {
+		matchers = append(matchers,
+			SpanMatcher{Key: "url.path", Value: path},
+			SpanMatcher{Key: "http.target", Value: path},
+		)
+	}
+	return matchers
+}()
+
+// ParseSpanMatcher parses a matcher of OTEL_DROP_SPANS, either a span name like
+// "GET /healthz" or an attribute like "url.path=/static/*".
+func ParseSpanMatcher(s string) SpanMatcher {
+	if key, value, ok := strings.Cut(s, "="); ok {
+		return SpanMatcher{Key: attribute.Key(strings.TrimSpace(key)), Value: strings.TrimSpace(value)}
+	}
+
+	return SpanMatcher{Name: strings.TrimSpace(s)}
+}
+
+func matchPattern(pattern, s string) bool {
+	if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+		return strings.HasPrefix(s, prefix)
+	}
+
+	return s == pattern
+}
+
+// Match reports whether a span started with name and attrs is matched.
+func (m SpanMatcher) Match(name string, attrs []attribute.KeyValue) bool {
+	if m.Name != "" && !matchPattern(m.Name, name) {
+		return false
+	}
+	if m.Key == "" {
+		return m.Name != ""
+	}
+	for _, kv := range attrs {
+		if kv.Key == m.Key && kv.Value.Type() == attribute.STRING && matchPattern(m.Value, kv.Value.AsString()) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// dropSampler drops the spans matched by one of its matchers, along with
+// their children through the parent based samplers, and delegates the others.
+type dropSampler struct {
+	next     trace.Sampler
+	matchers []SpanMatcher
+}
+
+// NewDropSampler returns a sampler dropping the spans matched by one of the
+// matchers and delegating the others to next, so noise like health checks
+// never reaches the export queue. Set Config.DropSpans to use it on the pipeline.
+func NewDropSampler(next trace.Sampler, matchers ...SpanMatcher) trace.Sampler {
+	return dropSampler{next: next, matchers: matchers}
+}
+
+// ShouldSample implements the trace.Sampler interface.
+func (s dropSampler) ShouldSample(p trace.SamplingParameters) trace.SamplingResult {
+	for _, m := range s.matchers {
+		if m.Match(p.Name, p.Attributes) {
+			result := s.next.ShouldSample(p)
+			return trace.SamplingResult{Decision: trace.Drop, Tracestate: result.Tracestate}
+		}
+	}
+
+	return s.next.ShouldSample(p)
+}
+
+// Description implements the trace.Sampler interface.
+func (s dropSampler) Description() string {
+	return fmt.Sprintf("DropSampler{%d matchers,%s}", len(s.matchers), s.next.Description())
+}
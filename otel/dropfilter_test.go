@@ -0,0 +1,56 @@
+package otel
+
+import (
+	"context"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+func TestDropSampler_DropsMatchedSpansAndChildren(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	matchers := append([]SpanMatcher{{Name: "GET /static/*"}}, HealthCheckSpans...)
+	tp := trace.NewTracerProvider(
+		trace.WithSampler(trace.ParentBased(NewDropSampler(trace.AlwaysSample(), matchers...))),
+		trace.WithSpanProcessor(recorder),
+	)
+	defer tp.Shutdown(context.TODO())
+	tracer := tp.Tracer("sample")
+
+	ctx, probe := tracer.Start(context.TODO(), "GET", oteltrace.WithAttributes(attribute.String("url.path", "/healthz")))
+	_, child := tracer.Start(ctx, "ping database")
+	child.End()
+	probe.End()
+	_, asset := tracer.Start(context.TODO(), "GET /static/app.js")
+	asset.End()
+	_, order := tracer.Start(context.TODO(), "GET", oteltrace.WithAttributes(attribute.String("url.path", "/orders")))
+	order.End()
+
+	assert.Len(t, recorder.Ended(), 1)
+	assert.Equal(t, order.SpanContext().SpanID(), recorder.Ended()[0].SpanContext().SpanID())
+}
+
+func TestDropSampler_ConfiguredFromEnv(t *testing.T) {
+	setEnv()
+	defer unsetEnv()
+	os.Setenv("OTEL_DROP_SPANS", "GET /metrics, url.path=/readyz")
+	defer os.Unsetenv("OTEL_DROP_SPANS")
+
+	c := NewENVConfig()
+	c.Writer = io.Discard
+	assert.Equal(t, []SpanMatcher{{Name: "GET /metrics"}, {Key: "url.path", Value: "/readyz"}}, c.DropSpans)
+
+	pipeline, err := NewExporter(IO, c).ExportPipeline(context.TODO())
+	assert.Nil(t, err)
+	defer pipeline.Shutdown(context.TODO())
+
+	_, span := pipeline.Tracer("sample").Start(context.TODO(), "GET /metrics")
+	assert.False(t, span.SpanContext().IsSampled())
+	span.End()
+}
@@ -0,0 +1,133 @@
+package otel
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/metric"
+)
+
+// Names of the self-metrics of the drop rate alert.
+const (
+	SpansDroppedName = "otel.pipeline.span.dropped"
+	SpanDropRateName = "otel.pipeline.span.drop_rate"
+)
+
+// defaultDropRateWindow is the window of the drop rate by default.
+const defaultDropRateWindow = time.Minute
+
+// DropRateAlert describes a window over which the drop rate of a pipeline
+// exceeded Config.DropRateThreshold. Dropped counts the spans of the failed
+// exports and the ones dropped from a full queue, Ended the sampled spans
+// ended.
+type DropRateAlert struct {
+	Window  time.Duration
+	Ended   int64
+	Dropped int64
+	Rate    float64
+}
+
+// dropRateMonitor computes the drop rate of an exportHealth over every
+// window and alerts once it exceeds threshold.
+type dropRateMonitor struct {
+	threshold float64
+	window    time.Duration
+	onAlert   func(DropRateAlert)
+
+	mu       sync.Mutex
+	start    time.Time
+	ended    int64
+	dropped  int64
+	rate     atomic.Uint64
+	deadline atomic.Int64
+}
+
+func newDropRateMonitor(c *Config) *dropRateMonitor {
+	if c.DropRateThreshold <= 0 {
+		return nil
+	}
+	window := c.DropRateWindow
+	if window <= 0 {
+		window = defaultDropRateWindow
+	}
+
+	m := &dropRateMonitor{
+		threshold: c.DropRateThreshold,
+		window:    window,
+		onAlert:   c.OnDropRate,
+		start:     time.Now(),
+	}
+	m.deadline.Store(m.start.Add(window).UnixNano())
+
+	return m
+}
+
+// check closes the window once elapsed, it's called for every span.
+func (m *dropRateMonitor) check(h *exportHealth, now time.Time) {
+	if m == nil || now.UnixNano() < m.deadline.Load() {
+		return
+	}
+
+	m.mu.Lock()
+	if now.Sub(m.start) < m.window {
+		m.mu.Unlock()
+		return
+	}
+	ended, dropped := h.queued.Load(), h.dropped.Load()
+	alert := DropRateAlert{
+		Window:  now.Sub(m.start),
+		Ended:   ended - m.ended,
+		Dropped: dropped - m.dropped,
+	}
+	m.start, m.ended, m.dropped = now, ended, dropped
+	m.deadline.Store(now.Add(m.window).UnixNano())
+	if alert.Ended > 0 {
+		alert.Rate = min(float64(alert.Dropped)/float64(alert.Ended), 1)
+	} else if alert.Dropped > 0 {
+		alert.Rate = 1
+	}
+	m.rate.Store(math.Float64bits(alert.Rate))
+	m.mu.Unlock()
+
+	if alert.Rate <= m.threshold {
+		return
+	}
+	if m.onAlert != nil {
+		m.onAlert(alert)
+		return
+	}
+	handle(errorKindDropRate, fmt.Errorf("dropped %d of %d spans (%.1f%%) over the last %s, telemetry is being lost",
+		alert.Dropped, alert.Ended, alert.Rate*100, alert.Window.Round(time.Second)))
+}
+
+// register records the spans dropped and the drop rate of the last window
+// on the meter provider.
+func (m *dropRateMonitor) register(h *exportHealth, mp metric.MeterProvider) error {
+	meter := mp.Meter(instrumentationName)
+
+	dropped, err := meter.Int64ObservableCounter(SpansDroppedName,
+		metric.WithDescription("Number of spans dropped by the pipeline."),
+		metric.WithUnit("{span}"))
+	if err != nil {
+		return err
+	}
+
+	rate, err := meter.Float64ObservableGauge(SpanDropRateName,
+		metric.WithDescription("Ratio of the spans dropped by the pipeline over the last window."),
+		metric.WithUnit("1"))
+	if err != nil {
+		return err
+	}
+
+	_, err = meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		o.ObserveInt64(dropped, h.dropped.Load())
+		o.ObserveFloat64(rate, math.Float64frombits(m.rate.Load()))
+		return nil
+	}, dropped, rate)
+
+	return err
+}
@@ -0,0 +1,75 @@
+package otel
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestDropRateMonitor_AlertsOverThreshold(t *testing.T) {
+	var alerts []DropRateAlert
+	m := newDropRateMonitor(&Config{
+		DropRateThreshold: 0.1,
+		OnDropRate:        func(a DropRateAlert) { alerts = append(alerts, a) },
+	})
+	h := &exportHealth{alert: m}
+	start := m.start
+
+	h.queued.Add(100)
+	h.dropped.Add(5)
+	m.check(h, start.Add(time.Second))
+	assert.Empty(t, alerts)
+	m.check(h, start.Add(time.Minute))
+	assert.Empty(t, alerts)
+
+	h.queued.Add(100)
+	h.dropped.Add(40)
+	m.check(h, start.Add(2*time.Minute))
+	assert.Equal(t, []DropRateAlert{{Window: time.Minute, Ended: 100, Dropped: 40, Rate: 0.4}}, alerts)
+
+	reader := metric.NewManualReader()
+	assert.Nil(t, m.register(h, metric.NewMeterProvider(metric.WithReader(reader))))
+	var rm metricdata.ResourceMetrics
+	assert.Nil(t, reader.Collect(context.TODO(), &rm))
+	metrics := rm.ScopeMetrics[0].Metrics
+	assert.Equal(t, SpansDroppedName, metrics[0].Name)
+	assert.Equal(t, int64(45), metrics[0].Data.(metricdata.Sum[int64]).DataPoints[0].Value)
+	assert.Equal(t, SpanDropRateName, metrics[1].Name)
+	assert.Equal(t, 0.4, metrics[1].Data.(metricdata.Gauge[float64]).DataPoints[0].Value)
+}
+
+func TestNewDropRateMonitor_DisabledWithoutThreshold(t *testing.T) {
+	assert.Nil(t, newDropRateMonitor(&Config{}))
+}
+
+// gatedWriter blocks the writes until its gate is closed.
+type gatedWriter struct {
+	gate chan struct{}
+}
+
+func (w gatedWriter) Write(p []byte) (int, error) {
+	<-w.gate
+	return io.Discard.Write(p)
+}
+
+func TestDropRateMonitor_CountsFullQueueDrops(t *testing.T) {
+	w := gatedWriter{gate: make(chan struct{})}
+	exporter := NewExporter(IO, &Config{Writer: w, MaxQueueSize: 1, DropRateThreshold: 0.1}, WithGlobalRegistration(false))
+	provider, err := exporter.ExportPipeline(context.TODO())
+	assert.Nil(t, err)
+
+	for i := 0; i < 20; i++ {
+		_, span := provider.Tracer("sample").Start(context.TODO(), "sample span")
+		span.End()
+	}
+	p, _ := pipelineOf(exporter)
+	assert.Positive(t, p.health.dropped.Load())
+
+	close(w.gate)
+	assert.Nil(t, provider.Shutdown(context.TODO()))
+}
@@ -0,0 +1,122 @@
+package otel
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	"go.opentelemetry.io/otel/sdk/trace"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// DryRunSummary reports what a pipeline running with Config.DryRun
+// would have sent so far, Bytes being the size of the OTLP requests before
+// compression.
+type DryRunSummary struct {
+	Batches int64
+	Spans   int64
+	Bytes   int64
+}
+
+// sizingClient measures the OTLP requests instead of uploading them.
+type sizingClient struct {
+	n int64
+}
+
+func (c *sizingClient) Start(context.Context) error { return nil }
+
+func (c *sizingClient) Stop(context.Context) error { return nil }
+
+func (c *sizingClient) UploadTraces(_ context.Context, spans []*tracepb.ResourceSpans) error {
+	c.n += int64(proto.Size(&coltracepb.ExportTraceServiceRequest{ResourceSpans: spans}))
+	return nil
+}
+
+// dryRunExporter encodes spans into the OTLP requests the output would send
+// and counts their uncompressed bytes, a summary line is written to report
+// for every exported batch.
+type dryRunExporter struct {
+	mu      sync.Mutex
+	encoder *otlptrace.Exporter
+	counter sizingClient
+	summary DryRunSummary
+	report  io.Writer
+}
+
+func newDryRunExporter(report io.Writer) (*dryRunExporter, error) {
+	e := &dryRunExporter{report: report}
+
+	encoder, err := otlptrace.New(context.Background(), &e.counter)
+	if err != nil {
+		return nil, err
+	}
+	e.encoder = encoder
+
+	return e, nil
+}
+
+// ExportSpans implements the trace.SpanExporter interface.
+func (e *dryRunExporter) ExportSpans(ctx context.Context, spans []trace.ReadOnlySpan) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	before := e.counter.n
+	if err := e.encoder.ExportSpans(ctx, spans); err != nil {
+		return err
+	}
+
+	e.summary.Batches++
+	e.summary.Spans += int64(len(spans))
+	e.summary.Bytes += e.counter.n - before
+
+	if e.report != nil {
+		fmt.Fprintf(e.report, "dry-run: would export %d spans (%d bytes)\n", len(spans), e.counter.n-before)
+	}
+
+	return nil
+}
+
+// Shutdown implements the trace.SpanExporter interface.
+func (e *dryRunExporter) Shutdown(ctx context.Context) error {
+	return e.encoder.Shutdown(ctx)
+}
+
+func (e *dryRunExporter) Summary() DryRunSummary {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.summary
+}
+
+// newDryRunExporter replaces the output exporter when Config.DryRun is set.
+func (p *pipeline) newDryRunExporter() (trace.SpanExporter, error) {
+	exp, err := newDryRunExporter(p.config.Writer)
+	if err != nil {
+		return nil, fmt.Errorf("could not create dry-run exporter: %w", err)
+	}
+	p.dryRun = exp
+
+	return exp, nil
+}
+
+// DryRunReport returns what the pipeline built by e would have sent so far,
+// ok is false when e has no dry-run pipeline built.
+func DryRunReport(e Exporter) (summary DryRunSummary, ok bool) {
+	p, ok := pipelineOf(e)
+	if !ok {
+		return DryRunSummary{}, false
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.dryRun == nil {
+		return DryRunSummary{}, false
+	}
+
+	return p.dryRun.Summary(), true
+}
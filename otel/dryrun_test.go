@@ -0,0 +1,84 @@
+package otel
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	"go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestDryRun_ReportsWithoutSending(t *testing.T) {
+	setEnv()
+	defer unsetEnv()
+
+	var report bytes.Buffer
+	c := NewENVConfig()
+	c.Writer = &report
+	c.DryRun = true
+
+	exporter := NewExporter(GRPC, c)
+	pipeline, err := exporter.ExportPipeline(context.TODO())
+	assert.Nil(t, err)
+	defer pipeline.Shutdown(context.TODO())
+
+	for i := 0; i < 3; i++ {
+		_, span := pipeline.Tracer("sample").Start(context.TODO(), "sample span")
+		span.End()
+	}
+	assert.Nil(t, pipeline.ForceFlush(context.TODO()))
+
+	summary, ok := DryRunReport(exporter)
+	assert.True(t, ok)
+	assert.Equal(t, int64(1), summary.Batches)
+	assert.Equal(t, int64(3), summary.Spans)
+	assert.NotZero(t, summary.Bytes)
+	assert.Contains(t, report.String(), "dry-run: would export 3 spans")
+}
+
+// uploadClient keeps the last request uploaded.
+type uploadClient struct {
+	request *coltracepb.ExportTraceServiceRequest
+}
+
+func (c *uploadClient) Start(context.Context) error { return nil }
+
+func (c *uploadClient) Stop(context.Context) error { return nil }
+
+func (c *uploadClient) UploadTraces(_ context.Context, spans []*tracepb.ResourceSpans) error {
+	c.request = &coltracepb.ExportTraceServiceRequest{ResourceSpans: spans}
+	return nil
+}
+
+func TestDryRun_CountsOTLPRequestBytes(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := trace.NewTracerProvider(trace.WithSpanProcessor(recorder))
+	_, span := provider.Tracer("sample").Start(context.TODO(), "sample span")
+	span.End()
+
+	client := &uploadClient{}
+	otlp, err := otlptrace.New(context.TODO(), client)
+	assert.Nil(t, err)
+	assert.Nil(t, otlp.ExportSpans(context.TODO(), recorder.Ended()))
+
+	exp, err := newDryRunExporter(nil)
+	assert.Nil(t, err)
+	assert.Nil(t, exp.ExportSpans(context.TODO(), recorder.Ended()))
+
+	assert.Equal(t, int64(proto.Size(client.request)), exp.Summary().Bytes)
+}
+
+func TestDryRun_NotConfigured(t *testing.T) {
+	setEnv()
+	defer unsetEnv()
+
+	_, ok := DryRunReport(NewExporter(GRPC, NewENVConfig()))
+
+	assert.False(t, ok)
+}
@@ -0,0 +1,65 @@
+package otel
+
+import (
+	"context"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel/sdk/trace"
+)
+
+// disabled is set by SetEnabled(false), zero value keeping tracing enabled.
+var disabled atomic.Bool
+
+// SetEnabled turns span production on and off at runtime for every pipeline
+// built by NewExporter, e.g. from a feature flag. While disabled new spans
+// aren't recorded and the spans started before but ended while disabled
+// aren't exported, providers are untouched so enabling it again takes
+// effect immediately. See also the /tracing endpoint of AdminHandler.
+func SetEnabled(enabled bool) {
+	disabled.Store(!enabled)
+}
+
+// Enabled reports whether span production is enabled, see SetEnabled.
+func Enabled() bool {
+	return !disabled.Load()
+}
+
+// enabledSampler drops every span while tracing is disabled.
+type enabledSampler struct {
+	next trace.Sampler
+}
+
+// ShouldSample implements the trace.Sampler interface.
+func (s enabledSampler) ShouldSample(p trace.SamplingParameters) trace.SamplingResult {
+	if disabled.Load() {
+		return trace.NeverSample().ShouldSample(p)
+	}
+
+	return s.next.ShouldSample(p)
+}
+
+// Description implements the trace.Sampler interface.
+func (s enabledSampler) Description() string {
+	return s.next.Description()
+}
+
+// enabledProcessor drops the spans ended while tracing is disabled.
+type enabledProcessor struct {
+	trace.SpanProcessor
+}
+
+// OnStart implements the trace.SpanProcessor interface.
+func (p enabledProcessor) OnStart(ctx context.Context, s trace.ReadWriteSpan) {
+	if disabled.Load() && !isDeploymentMarker(s) {
+		return
+	}
+	p.SpanProcessor.OnStart(ctx, s)
+}
+
+// OnEnd implements the trace.SpanProcessor interface.
+func (p enabledProcessor) OnEnd(s trace.ReadOnlySpan) {
+	if disabled.Load() && !isDeploymentMarker(s) {
+		return
+	}
+	p.SpanProcessor.OnEnd(s)
+}
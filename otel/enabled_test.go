@@ -0,0 +1,41 @@
+package otel
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetEnabled_GatesSpanProduction(t *testing.T) {
+	defer SetEnabled(true)
+
+	exporter := NewExporter(Test, &Config{}, WithGlobalRegistration(false))
+	provider, err := exporter.ExportPipeline(context.TODO())
+	assert.Nil(t, err)
+	defer provider.Shutdown(context.TODO())
+	recorder, _ := Recorder(exporter)
+
+	_, inflight := provider.Tracer("sample").Start(context.TODO(), "in flight")
+	SetEnabled(false)
+	assert.False(t, Enabled())
+	_, span := provider.Tracer("sample").Start(context.TODO(), "disabled")
+	assert.False(t, span.IsRecording())
+	span.End()
+	inflight.End()
+	assert.Empty(t, recorder.Spans())
+
+	h := AdminHandler(exporter, func(*http.Request) bool { return true })
+	code, health := adminRequest(t, h, http.MethodGet, "/health")
+	assert.Equal(t, http.StatusOK, code)
+	assert.True(t, health.TracingDisabled)
+
+	code, health = adminRequest(t, h, http.MethodPost, "/tracing?enabled=true")
+	assert.Equal(t, http.StatusOK, code)
+	assert.False(t, health.TracingDisabled)
+	_, span = provider.Tracer("sample").Start(context.TODO(), "enabled")
+	span.End()
+	assert.Len(t, recorder.Spans(), 1)
+	assert.Equal(t, "enabled", recorder.Spans()[0].Name)
+}
@@ -0,0 +1,65 @@
+package otel
+
+import (
+	"context"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/trace"
+)
+
+// EnrichmentProcessor is a span processor stamping static attributes like the
+// owning team or build ID onto every span, for backends indexing span
+// attributes better than resource ones. The attributes set when starting a
+// span are kept. Set Config.SpanAttributes or register it on the provider
+// returned by ExportPipeline with RegisterSpanProcessor.
+type EnrichmentProcessor struct {
+	attrs []attribute.KeyValue
+}
+
+var _ trace.SpanProcessor = (*EnrichmentProcessor)(nil)
+
+// NewEnrichmentProcessor creates a processor setting attrs on every span.
+func NewEnrichmentProcessor(attrs ...attribute.KeyValue) *EnrichmentProcessor {
+	return &EnrichmentProcessor{attrs: attrs}
+}
+
+// OnStart implements the trace.SpanProcessor interface.
+func (p *EnrichmentProcessor) OnStart(_ context.Context, s trace.ReadWriteSpan) {
+	set := make(map[attribute.Key]bool)
+	for _, kv := range s.Attributes() {
+		set[kv.Key] = true
+	}
+	for _, kv := range p.attrs {
+		if !set[kv.Key] {
+			s.SetAttributes(kv)
+		}
+	}
+}
+
+// OnEnd implements the trace.SpanProcessor interface.
+func (p *EnrichmentProcessor) OnEnd(trace.ReadOnlySpan) {}
+
+// Shutdown implements the trace.SpanProcessor interface.
+func (p *EnrichmentProcessor) Shutdown(context.Context) error {
+	return nil
+}
+
+// ForceFlush implements the trace.SpanProcessor interface.
+func (p *EnrichmentProcessor) ForceFlush(context.Context) error {
+	return nil
+}
+
+// attributesEnv reads a comma separated list of key=value string attributes
+// from the environment, entries without a key are ignored.
+func attributesEnv(key string) []attribute.KeyValue {
+	var attrs []attribute.KeyValue
+	for _, entry := range listEnv(key) {
+		k, v, _ := strings.Cut(entry, "=")
+		if k = strings.TrimSpace(k); k != "" {
+			attrs = append(attrs, attribute.String(k, strings.TrimSpace(v)))
+		}
+	}
+
+	return attrs
+}
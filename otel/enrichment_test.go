@@ -0,0 +1,51 @@
+package otel
+
+import (
+	"context"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+func TestEnrichmentProcessor_KeepsStartAttributes(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := trace.NewTracerProvider(
+		trace.WithSpanProcessor(NewEnrichmentProcessor(attribute.String("team", "payments"), attribute.String("build.id", "42"))),
+		trace.WithSpanProcessor(recorder),
+	)
+	defer tp.Shutdown(context.TODO())
+
+	_, span := tp.Tracer("sample").Start(context.TODO(), "sample span", oteltrace.WithAttributes(attribute.String("team", "checkout")))
+	span.End()
+
+	attrs := recorder.Ended()[0].Attributes()
+	assert.ElementsMatch(t, []attribute.KeyValue{attribute.String("team", "checkout"), attribute.String("build.id", "42")}, attrs)
+}
+
+func TestEnrichmentProcessor_ConfiguredFromEnv(t *testing.T) {
+	setEnv()
+	defer unsetEnv()
+	os.Setenv("OTEL_SPAN_ATTRIBUTES", "team=payments, cost_center=42,=ignored")
+	defer os.Unsetenv("OTEL_SPAN_ATTRIBUTES")
+
+	c := NewENVConfig()
+	c.Writer = io.Discard
+	assert.Equal(t, []attribute.KeyValue{attribute.String("team", "payments"), attribute.String("cost_center", "42")}, c.SpanAttributes)
+
+	recorder := tracetest.NewSpanRecorder()
+	c.SpanProcessors = []trace.SpanProcessor{recorder}
+	pipeline, err := NewExporter(IO, c).ExportPipeline(context.TODO())
+	assert.Nil(t, err)
+	defer pipeline.Shutdown(context.TODO())
+
+	_, span := pipeline.Tracer("sample").Start(context.TODO(), "sample span")
+	span.End()
+
+	assert.Contains(t, recorder.Ended()[0].Attributes(), attribute.String("team", "payments"))
+}
@@ -0,0 +1,127 @@
+package otel
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+)
+
+// defaultErrorRateLimit is the minimum interval between two reported
+// errors when Config.ErrorRateLimit is not set.
+const defaultErrorRateLimit = 10 * time.Second
+
+// Kinds of the errors reported by the pipelines, rate limited apart from
+// each other so export failures don't hide a drop rate alert.
+const (
+	errorKindOther     = "other"
+	errorKindExport    = "export"
+	errorKindReload    = "reload"
+	errorKindAPIKey    = "api_key"
+	errorKindTenant    = "tenant"
+	errorKindFallback  = "fallback"
+	errorKindDropRate  = "drop_rate"
+	errorKindDevMode   = "dev_mode"
+	errorKindTLSReload = "tls_reload"
+	errorKindConfig    = "config"
+)
+
+// kindError is an error reported with handle, Error being the one of err.
+type kindError struct {
+	kind string
+	err  error
+}
+
+func (e *kindError) Error() string {
+	return e.err.Error()
+}
+
+func (e *kindError) Unwrap() error {
+	return e.err
+}
+
+// handle reports err of kind to the otel error handler.
+func handle(kind string, err error) {
+	otel.Handle(&kindError{kind: kind, err: err})
+}
+
+// errorKind returns the kind of err given to handle, errorKindOther for the
+// errors reported by otel and the instrumentations.
+func errorKind(err error) string {
+	var kerr *kindError
+	if errors.As(err, &kerr) {
+		return kerr.kind
+	}
+
+	return errorKindOther
+}
+
+// errorLimit is the rate limiting state of a kind of errors.
+type errorLimit struct {
+	last       time.Time
+	suppressed int
+}
+
+// rateLimitedHandler forwards otel errors to a user callback reporting at most
+// one error of every kind per interval, errors received in between are only
+// counted and the count is attached to the next reported error of the kind.
+type rateLimitedHandler struct {
+	mu       sync.Mutex
+	handler  func(error)
+	interval time.Duration
+	now      func() time.Time
+	limits   map[string]*errorLimit
+}
+
+func newRateLimitedHandler(handler func(error), interval time.Duration) *rateLimitedHandler {
+	if interval == 0 {
+		interval = defaultErrorRateLimit
+	}
+
+	return &rateLimitedHandler{
+		handler:  handler,
+		interval: interval,
+		now:      time.Now,
+		limits:   map[string]*errorLimit{},
+	}
+}
+
+// Handle implements the otel.ErrorHandler interface.
+func (h *rateLimitedHandler) Handle(err error) {
+	kind := errorKind(err)
+
+	h.mu.Lock()
+	limit, ok := h.limits[kind]
+	if !ok {
+		limit = &errorLimit{}
+		h.limits[kind] = limit
+	}
+	now := h.now()
+	if !limit.last.IsZero() && now.Sub(limit.last) < h.interval {
+		limit.suppressed++
+		h.mu.Unlock()
+		return
+	}
+
+	suppressed := limit.suppressed
+	limit.last, limit.suppressed = now, 0
+	h.mu.Unlock()
+
+	if suppressed > 0 {
+		err = fmt.Errorf("%w (%d similar errors suppressed)", err, suppressed)
+	}
+	h.handler(err)
+}
+
+// registerErrorHandler routes otel errors, mostly export failures,
+// to Config.ErrorHandler instead of the default stderr logger. The handler
+// is global, it's only registered along with the provider.
+func (p *pipeline) registerErrorHandler() {
+	if p.config.ErrorHandler == nil || !p.globalRegistration {
+		return
+	}
+
+	otel.SetErrorHandler(newRateLimitedHandler(p.config.ErrorHandler, p.config.ErrorRateLimit))
+}
@@ -0,0 +1,70 @@
+package otel
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+)
+
+func TestErrorHandler_RateLimitsErrors(t *testing.T) {
+	var reported []error
+	now := time.Unix(0, 0)
+
+	h := newRateLimitedHandler(func(err error) { reported = append(reported, err) }, time.Minute)
+	h.now = func() time.Time { return now }
+
+	h.Handle(errors.New("export failed"))
+	h.Handle(errors.New("export failed"))
+	h.Handle(errors.New("export failed"))
+	assert.Len(t, reported, 1)
+
+	now = now.Add(time.Minute)
+	h.Handle(errors.New("export failed"))
+
+	assert.Len(t, reported, 2)
+	assert.EqualError(t, reported[1], "export failed (2 similar errors suppressed)")
+}
+
+func TestErrorHandler_NegativeRateLimitReportsAll(t *testing.T) {
+	var reported int
+	h := newRateLimitedHandler(func(error) { reported++ }, -1)
+
+	for i := 0; i < 5; i++ {
+		h.Handle(errors.New("export failed"))
+	}
+
+	assert.Equal(t, 5, reported)
+}
+
+func TestErrorHandler_RateLimitsKindsApart(t *testing.T) {
+	var reported []error
+	h := newRateLimitedHandler(func(err error) { reported = append(reported, err) }, time.Minute)
+
+	h.Handle(errors.New("export failed"))
+	h.Handle(errors.New("export failed"))
+	h.Handle(&kindError{kind: errorKindDropRate, err: errors.New("telemetry is being lost")})
+
+	assert.Len(t, reported, 2)
+	assert.EqualError(t, reported[1], "telemetry is being lost")
+}
+
+func TestErrorHandler_RegisteredGlobally(t *testing.T) {
+	defer otel.SetErrorHandler(otel.GetErrorHandler())
+	var reported []error
+	handler := func(err error) { reported = append(reported, err) }
+
+	_, err := NewExporter(IO, &Config{Writer: io.Discard, ErrorHandler: handler}, WithGlobalRegistration(false)).ExportPipeline(context.TODO())
+	assert.Nil(t, err)
+	otel.Handle(errors.New("export failed"))
+	assert.Empty(t, reported)
+
+	_, err = NewExporter(IO, &Config{Writer: io.Discard, ErrorHandler: handler}).ExportPipeline(context.TODO())
+	assert.Nil(t, err)
+	otel.Handle(errors.New("export failed"))
+	assert.Len(t, reported, 1)
+}
@@ -0,0 +1,171 @@
+package otel
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+var (
+	// errorOnlyWindow is how long the spans of a trace are buffered waiting
+	// for an error or slow span, since its last span ended.
+	errorOnlyWindow = 30 * time.Second
+	// errorOnlyMaxTraces bounds the traces buffered, the spans of other
+	// traces are discarded until some expire.
+	errorOnlyMaxTraces = 10000
+	// errorOnlyMaxSpans bounds the spans buffered per trace.
+	errorOnlyMaxSpans = 1000
+	// errorOnlyMaxBytes bounds the estimated size of all the spans buffered.
+	errorOnlyMaxBytes int64 = 64 << 20
+)
+
+// bufferedTrace holds the ended spans of a trace until it's kept.
+type bufferedTrace struct {
+	spans   []trace.ReadOnlySpan
+	size    int64
+	keep    bool
+	expires time.Time
+}
+
+// errorOnlyProcessor only hands to its processor the spans of the traces
+// with an error span, or a span lasting at least threshold when set.
+// Spans are buffered until one of them is found and, once the trace is
+// kept, the later ones are passed through.
+//
+// The buffered spans count toward the memory limit of the pipeline, the
+// ones over it or over errorOnlyMaxBytes being discarded.
+type errorOnlyProcessor struct {
+	next      trace.SpanProcessor
+	threshold time.Duration
+	memory    *memoryLimiter
+
+	mu        sync.Mutex
+	traces    map[oteltrace.TraceID]*bufferedTrace
+	size      int64
+	nextSweep time.Time
+}
+
+// errorOnlyProcessor wraps export with the processor of Config.ErrorTracesOnly,
+// accounting for the spans it buffers in memory.
+func (c *Config) errorOnlyProcessor(export trace.SpanProcessor, memory *memoryLimiter) trace.SpanProcessor {
+	if !c.ErrorTracesOnly {
+		return export
+	}
+
+	return &errorOnlyProcessor{
+		next:      export,
+		threshold: c.SlowSpanThreshold,
+		memory:    memory,
+		traces:    make(map[oteltrace.TraceID]*bufferedTrace),
+	}
+}
+
+// OnStart implements the trace.SpanProcessor interface.
+func (p *errorOnlyProcessor) OnStart(ctx context.Context, s trace.ReadWriteSpan) {
+	p.next.OnStart(ctx, s)
+}
+
+// OnEnd implements the trace.SpanProcessor interface.
+func (p *errorOnlyProcessor) OnEnd(s trace.ReadOnlySpan) {
+	if !s.SpanContext().IsSampled() {
+		return
+	}
+	if isDeploymentMarker(s) {
+		p.next.OnEnd(s)
+		return
+	}
+
+	now := time.Now()
+	p.mu.Lock()
+	p.sweep(now)
+
+	id := s.SpanContext().TraceID()
+	t, ok := p.traces[id]
+	if !ok {
+		if len(p.traces) >= errorOnlyMaxTraces && !notableSpan(s, p.threshold) {
+			p.mu.Unlock()
+			return
+		}
+		t = &bufferedTrace{}
+		p.traces[id] = t
+	}
+	t.expires = now.Add(errorOnlyWindow)
+
+	var flush []trace.ReadOnlySpan
+	switch {
+	case t.keep:
+	case notableSpan(s, p.threshold):
+		t.keep = true
+		flush = t.spans
+		p.discard(t)
+	default:
+		size := spanSize(s)
+		if len(t.spans) < errorOnlyMaxSpans && p.size+size <= errorOnlyMaxBytes && p.memory.reserve(s) {
+			t.spans = append(t.spans, s)
+			t.size += size
+			p.size += size
+		}
+		p.mu.Unlock()
+		return
+	}
+	p.mu.Unlock()
+
+	for _, span := range flush {
+		p.next.OnEnd(span)
+	}
+	p.next.OnEnd(s)
+}
+
+// discard releases the memory of the spans buffered for t.
+func (p *errorOnlyProcessor) discard(t *bufferedTrace) {
+	p.memory.release(t.spans...)
+	p.size -= t.size
+	t.spans, t.size = nil, 0
+}
+
+// notableSpan reports whether s ended with an error status or lasted at
+// least threshold, when set.
+func notableSpan(s trace.ReadOnlySpan, threshold time.Duration) bool {
+	if s.Status().Code == codes.Error {
+		return true
+	}
+
+	return threshold > 0 && s.EndTime().Sub(s.StartTime()) >= threshold
+}
+
+// sweep forgets the expired traces, at most once per second.
+func (p *errorOnlyProcessor) sweep(now time.Time) {
+	if now.Before(p.nextSweep) {
+		return
+	}
+	p.nextSweep = now.Add(time.Second)
+
+	for id, t := range p.traces {
+		if now.After(t.expires) {
+			p.discard(t)
+			delete(p.traces, id)
+		}
+	}
+}
+
+// Shutdown implements the trace.SpanProcessor interface,
+// the spans still buffered are discarded.
+func (p *errorOnlyProcessor) Shutdown(ctx context.Context) error {
+	p.mu.Lock()
+	for _, t := range p.traces {
+		p.discard(t)
+	}
+	clear(p.traces)
+	p.mu.Unlock()
+
+	return p.next.Shutdown(ctx)
+}
+
+// ForceFlush implements the trace.SpanProcessor interface.
+func (p *errorOnlyProcessor) ForceFlush(ctx context.Context) error {
+	return p.next.ForceFlush(ctx)
+}
@@ -0,0 +1,72 @@
+package otel
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+func TestErrorOnlyProcessor_ExportsErrorAndSlowTraces(t *testing.T) {
+	exporter := NewExporter(Test, &Config{ErrorTracesOnly: true, SlowSpanThreshold: time.Second}, WithGlobalRegistration(false))
+	provider, err := exporter.ExportPipeline(context.TODO())
+	assert.Nil(t, err)
+	defer provider.Shutdown(context.TODO())
+	recorder, _ := Recorder(exporter)
+	tracer := provider.Tracer("sample")
+
+	ctx, root := tracer.Start(context.TODO(), "ok root")
+	_, child := tracer.Start(ctx, "ok child")
+	child.End()
+	root.End()
+	assert.Empty(t, recorder.Spans())
+
+	ctx, root = tracer.Start(context.TODO(), "failed root")
+	_, child = tracer.Start(ctx, "buffered child")
+	child.End()
+	assert.Empty(t, recorder.Spans())
+	root.SetStatus(codes.Error, "payment declined")
+	root.End()
+	_, late := tracer.Start(ctx, "late child")
+	late.End()
+	assert.Equal(t, []string{"buffered child", "failed root", "late child"}, spanNames(recorder))
+
+	start := time.Now()
+	_, slow := tracer.Start(context.TODO(), "slow root", oteltrace.WithTimestamp(start))
+	slow.End(oteltrace.WithTimestamp(start.Add(2 * time.Second)))
+	assert.Equal(t, "slow root", recorder.Spans()[3].Name)
+
+	snapshot, _ := Snapshot(exporter)
+	assert.Contains(t, snapshot.Processors, "error_only")
+}
+
+func spanNames(recorder *SpanRecorder) []string {
+	var names []string
+	for _, s := range recorder.Spans() {
+		names = append(names, s.Name)
+	}
+
+	return names
+}
+
+func TestErrorOnlyProcessor_BoundsBufferedBytes(t *testing.T) {
+	defer func(max int64) { errorOnlyMaxBytes = max }(errorOnlyMaxBytes)
+	size := spanSize(sampledSpan("ok span", codes.Unset))
+	errorOnlyMaxBytes = 2 * size
+	memory := &memoryLimiter{limit: 1 << 20}
+	recorder := tracetest.NewSpanRecorder()
+	p := (&Config{ErrorTracesOnly: true}).errorOnlyProcessor(recorder, memory)
+
+	for range 3 {
+		p.OnEnd(sampledSpan("ok span", codes.Unset))
+	}
+	assert.Equal(t, 2*size, memory.buffered.Load(), "counted toward the memory limit up to the bound")
+
+	p.OnEnd(sampledSpan("failed span", codes.Error))
+	assert.Len(t, recorder.Ended(), 3)
+	assert.Zero(t, memory.buffered.Load(), "released once handed over")
+}
@@ -3,10 +3,14 @@ package otel
 import (
 	"bytes"
 	"context"
+	"log/slog"
 	"os"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
 )
 
 func TestExporter_TraceSpan(t *testing.T) {
@@ -80,6 +84,66 @@ func TestExporter_GetPipelineWithOutputTypeGRPC(t *testing.T) {
 
 }
 
+func TestExporter_BatchTimeoutJitter(t *testing.T) {
+	c := &Config{BatchJitter: time.Second}
+	for i := 0; i < 100; i++ {
+		timeout := c.batchTimeout(defaultBatchTimeout)
+		assert.GreaterOrEqual(t, timeout, defaultBatchTimeout)
+		assert.Less(t, timeout, defaultBatchTimeout+time.Second)
+	}
+
+	assert.Equal(t, defaultBatchTimeout, (&Config{}).batchTimeout(defaultBatchTimeout))
+}
+
+func TestExporter_BatchJitterFromEnv(t *testing.T) {
+	os.Setenv("OTEL_BATCH_JITTER", "2s")
+	defer os.Unsetenv("OTEL_BATCH_JITTER")
+
+	assert.Equal(t, 2*time.Second, NewENVConfig().BatchJitter)
+}
+
+func TestExporter_ReportsInvalidEnv(t *testing.T) {
+	defer otel.SetErrorHandler(otel.GetErrorHandler())
+	var reported []error
+	otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) { reported = append(reported, err) }))
+
+	t.Setenv("OTEL_BATCH_JITTER", "2")
+	t.Setenv("OTEL_BATCH_EXPORT_WORKERS", "four")
+	t.Setenv("OTEL_GRPC_LOAD_BALANCE", "yes")
+	t.Setenv("OTEL_DROP_RATE_THRESHOLD", "5%")
+	t.Setenv("OTEL_ATTRIBUTE_LENGTH_LIMITS", "db.statement=16384,http.url")
+
+	c := NewENVConfig()
+	assert.Zero(t, c.BatchJitter)
+	assert.Zero(t, c.ExportWorkers)
+	assert.False(t, c.LoadBalance)
+	assert.Zero(t, c.DropRateThreshold)
+	assert.Equal(t, map[string]int{"db.statement": 16384}, c.AttributeLengthLimits)
+
+	assert.Len(t, reported, 5)
+	assert.Contains(t, reported[0].Error(), `ignoring invalid OTEL_BATCH_JITTER value "2"`)
+	assert.Equal(t, errorKindConfig, errorKind(reported[0]))
+}
+
+func TestExporter_EnvironmentAndNamespace(t *testing.T) {
+	t.Setenv("OTEL_SERVICE_NAMESPACE", "shop")
+	t.Setenv("OTEL_DEPLOYMENT_ENVIRONMENT", "staging")
+
+	resource, err := NewENVConfig().resource(context.TODO())
+	assert.Nil(t, err)
+
+	namespace, _ := resource.Set().Value("service.namespace")
+	assert.Equal(t, "shop", namespace.AsString())
+	environment, _ := resource.Set().Value("deployment.environment.name")
+	assert.Equal(t, "staging", environment.AsString())
+	environment, _ = resource.Set().Value("deployment.environment")
+	assert.Equal(t, "staging", environment.AsString(), "the name before semconv 1.27.0")
+
+	resource, _ = (&Config{}).resource(context.TODO())
+	assert.False(t, resource.Set().HasValue("deployment.environment.name"))
+	assert.False(t, resource.Set().HasValue("deployment.environment"))
+}
+
 func setEnv() {
 	os.Setenv("OTEL_SERVICE_NAME", "sampleServiceName")
 	os.Setenv("OTEL_SERVICE_VERSION", "v1.0.0.0")
@@ -95,3 +159,50 @@ func unsetEnv() {
 	os.Unsetenv("OTEL_GRPC_API_KEY")
 	os.Unsetenv("OTEL_GRPC_URL")
 }
+
+func TestExporter_ResourceSchemaURL(t *testing.T) {
+	c := &Config{ServiceName: "orders", ResourceDetectors: []string{"host"}}
+	resource, err := c.resource(context.TODO())
+	assert.Nil(t, err)
+	assert.Equal(t, "https://opentelemetry.io/schemas/1.43.0", resource.SchemaURL())
+
+	c.SchemaURL = "https://opentelemetry.io/schemas/1.4.0"
+	resource, err = c.resource(context.TODO())
+	assert.Nil(t, err)
+	assert.Equal(t, c.SchemaURL, resource.SchemaURL())
+	assert.True(t, resource.Set().HasValue("host.name"))
+}
+
+func TestIOOutput_WritesEverySignal(t *testing.T) {
+	var out bytes.Buffer
+	exporter := NewExporter(IO, &Config{ServiceName: "orders", Writer: &out}, WithGlobalRegistration(false))
+
+	tracerProvider, err := exporter.ExportPipeline(context.TODO())
+	assert.Nil(t, err)
+	meterProvider, err := ExportMetricPipeline(context.TODO(), exporter)
+	assert.Nil(t, err)
+	loggerProvider, err := ExportLogPipeline(context.TODO(), exporter)
+	assert.Nil(t, err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ctx, span := tracerProvider.Tracer("sample").Start(context.TODO(), "sample span")
+			slog.New(NewSlogBridge(loggerProvider)).InfoContext(ctx, "order placed")
+			counter, _ := meterProvider.Meter("sample").Int64Counter("orders.placed")
+			counter.Add(ctx, 1)
+			span.End()
+		}()
+	}
+	wg.Wait()
+
+	assert.Nil(t, tracerProvider.Shutdown(context.TODO()))
+	assert.Nil(t, meterProvider.Shutdown(context.TODO()))
+	assert.Nil(t, loggerProvider.Shutdown(context.TODO()))
+
+	assert.Contains(t, out.String(), `"Name":"sample span"`)
+	assert.Contains(t, out.String(), `"Name":"orders.placed"`)
+	assert.Contains(t, out.String(), "order placed")
+}
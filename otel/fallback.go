@@ -0,0 +1,141 @@
+package otel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/sdk/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+)
+
+// Fallback thresholds of WithFallback, variables for the tests.
+var (
+	// fallbackConnectTimeout bounds the wait for the endpoint at startup.
+	fallbackConnectTimeout = 5 * time.Second
+	// fallbackFailures is the number of consecutive failed exports falling back.
+	fallbackFailures = 3
+)
+
+// fallbackExporter exports spans to primary, or to fallback while primary is
+// considered unreachable. The batch failing over is written to fallback too,
+// so no span is lost when switching.
+type fallbackExporter struct {
+	primary  trace.SpanExporter
+	fallback trace.SpanExporter
+	// reachable reports whether primary may be tried again while falling back
+	reachable func() bool
+	endpoint  string
+
+	mu       sync.Mutex
+	active   bool
+	failures int
+}
+
+var _ trace.SpanExporter = (*fallbackExporter)(nil)
+
+// ExportSpans implements the trace.SpanExporter interface. Only the state
+// transitions are locked, the exports of the workers running concurrently.
+func (e *fallbackExporter) ExportSpans(ctx context.Context, spans []trace.ReadOnlySpan) error {
+	e.mu.Lock()
+	if e.active && e.reachable() {
+		e.active = false
+		e.failures = 0
+		handle(errorKindFallback, fmt.Errorf("OTLP endpoint %s reachable again, no longer falling back", e.endpoint))
+	}
+	active := e.active
+	e.mu.Unlock()
+	if active {
+		return e.fallback.ExportSpans(ctx, spans)
+	}
+
+	err := e.primary.ExportSpans(ctx, spans)
+
+	e.mu.Lock()
+	if err == nil {
+		e.failures = 0
+		e.mu.Unlock()
+		return nil
+	}
+	if e.failures++; e.failures < fallbackFailures {
+		e.mu.Unlock()
+		return err
+	}
+	// another worker may have fallen back meanwhile
+	if !e.active {
+		e.activate(fmt.Errorf("%d consecutive exports failed: %w", e.failures, err))
+	}
+	e.mu.Unlock()
+
+	return e.fallback.ExportSpans(ctx, spans)
+}
+
+// activate falls back, reporting why to the error handler. It's called with mu held.
+func (e *fallbackExporter) activate(cause error) {
+	e.active = true
+	handle(errorKindFallback, fmt.Errorf("OTLP endpoint %s unreachable, falling back: %w", e.endpoint, cause))
+}
+
+// Shutdown implements the trace.SpanExporter interface.
+func (e *fallbackExporter) Shutdown(ctx context.Context) error {
+	if err := e.primary.Shutdown(ctx); err != nil {
+		return err
+	}
+
+	return e.fallback.Shutdown(ctx)
+}
+
+// withFallback wraps the OTLP exporter of the output, falling back right away
+// when the shared connection isn't ready within fallbackConnectTimeout.
+func (g *grpcOutput) withFallback(ctx context.Context, exp trace.SpanExporter) (trace.SpanExporter, error) {
+	g.connMu.Lock()
+	conn := g.conn
+	g.connMu.Unlock()
+
+	console, err := newConsoleExporter(&syncWriter{w: g.options.fallback}, g.Config.Format)
+	if err != nil {
+		return nil, errors.Join(err, exp.Shutdown(ctx))
+	}
+
+	e := &fallbackExporter{
+		primary:  exp,
+		fallback: console,
+		reachable: func() bool {
+			state := conn.GetState()
+			if state == connectivity.Idle {
+				conn.Connect()
+			}
+			return state == connectivity.Ready
+		},
+		endpoint: g.Config.URL,
+	}
+	if !waitReady(ctx, conn, fallbackConnectTimeout) {
+		e.activate(fmt.Errorf("no connection within %s", fallbackConnectTimeout))
+	}
+
+	return e, nil
+}
+
+// waitReady connects conn, it reports whether it's ready before timeout
+// or a failed attempt.
+func waitReady(ctx context.Context, conn *grpc.ClientConn, timeout time.Duration) bool {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	conn.Connect()
+	for {
+		state := conn.GetState()
+		switch state {
+		case connectivity.Ready:
+			return true
+		case connectivity.TransientFailure, connectivity.Shutdown:
+			return false
+		}
+		if !conn.WaitForStateChange(ctx, state) {
+			return false
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package otel
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// failingExporter fails every export while err is set.
+type failingExporter struct {
+	err      error
+	exported int
+}
+
+func (e *failingExporter) ExportSpans(_ context.Context, spans []trace.ReadOnlySpan) error {
+	if e.err != nil {
+		return e.err
+	}
+	e.exported += len(spans)
+	return nil
+}
+
+func (e *failingExporter) Shutdown(context.Context) error {
+	return nil
+}
+
+func TestWithFallback_UnreachableAtStartup(t *testing.T) {
+	defer otel.SetErrorHandler(otel.GetErrorHandler())
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	addr := lis.Addr().String()
+	lis.Close()
+
+	var mu sync.Mutex
+	var reported []error
+	var out bytes.Buffer
+	exporter := NewExporter(GRPC, &Config{
+		URL:            addr,
+		Insecure:       true,
+		SyncExport:     true,
+		ErrorRateLimit: -1,
+		ErrorHandler: func(err error) {
+			mu.Lock()
+			defer mu.Unlock()
+			reported = append(reported, err)
+		},
+	}, WithFallback(&out))
+
+	pipeline, err := exporter.ExportPipeline(context.TODO())
+	assert.Nil(t, err)
+	defer pipeline.Shutdown(context.TODO())
+
+	_, span := pipeline.Tracer("sample").Start(context.TODO(), "sample span")
+	span.End()
+
+	assert.Contains(t, out.String(), `"Name":"sample span"`)
+	mu.Lock()
+	defer mu.Unlock()
+	assert.NotEmpty(t, reported)
+	assert.Contains(t, reported[0].Error(), "OTLP endpoint "+addr+" unreachable, falling back")
+}
+
+func TestFallbackExporter_PersistentFailures(t *testing.T) {
+	defer otel.SetErrorHandler(otel.GetErrorHandler())
+	var reported []error
+	otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) { reported = append(reported, err) }))
+
+	primary := &failingExporter{err: errors.New("unavailable")}
+	fallback := tracetest.NewInMemoryExporter()
+	reachable := false
+	e := &fallbackExporter{primary: primary, fallback: fallback, reachable: func() bool { return reachable }, endpoint: "collector:4317"}
+	spans := tracetest.SpanStubs{{Name: "sample span"}}.Snapshots()
+
+	for i := 1; i < fallbackFailures; i++ {
+		assert.EqualError(t, e.ExportSpans(context.TODO(), spans), "unavailable")
+	}
+	assert.Empty(t, fallback.GetSpans())
+
+	assert.Nil(t, e.ExportSpans(context.TODO(), spans))
+	assert.Nil(t, e.ExportSpans(context.TODO(), spans))
+	assert.Len(t, fallback.GetSpans(), 2)
+	assert.Len(t, reported, 1)
+	assert.EqualError(t, reported[0], "OTLP endpoint collector:4317 unreachable, falling back: 3 consecutive exports failed: unavailable")
+
+	primary.err = nil
+	reachable = true
+	assert.Nil(t, e.ExportSpans(context.TODO(), spans))
+	assert.Equal(t, 1, primary.exported)
+	assert.Len(t, fallback.GetSpans(), 2)
+	assert.Len(t, reported, 2)
+}
+
+// barrierExporter waits for n exports to run at the same time.
+type barrierExporter struct {
+	failingExporter
+	wg sync.WaitGroup
+}
+
+func (e *barrierExporter) ExportSpans(context.Context, []trace.ReadOnlySpan) error {
+	e.wg.Done()
+	e.wg.Wait()
+	return nil
+}
+
+func TestFallbackExporter_ExportsConcurrently(t *testing.T) {
+	primary := &barrierExporter{}
+	primary.wg.Add(2)
+	e := &fallbackExporter{primary: primary, fallback: tracetest.NewInMemoryExporter(), reachable: func() bool { return true }}
+
+	done := make(chan error, 2)
+	for range 2 {
+		go func() { done <- e.ExportSpans(context.TODO(), nil) }()
+	}
+	for range 2 {
+		select {
+		case err := <-done:
+			assert.Nil(t, err)
+		case <-time.After(5 * time.Second):
+			t.Fatal("exports serialized")
+		}
+	}
+}
@@ -0,0 +1,359 @@
+package otel
+
+import (
+	"context"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/stats"
+	"google.golang.org/grpc/status"
+)
+
+// Attributes of the rpc message events.
+const (
+	messageTypeKey             = attribute.Key("message.type")
+	messageIDKey               = attribute.Key("message.id")
+	messageCompressedSizeKey   = attribute.Key("message.compressed_size")
+	messageUncompressedSizeKey = attribute.Key("message.uncompressed_size")
+)
+
+// connectionRPCsKey is the number of rpcs carried by a server connection, set on its span.
+const connectionRPCsKey = attribute.Key("rpc.grpc.connection.rpcs")
+
+// metadataCarrier adapts gRPC metadata to the propagation.TextMapCarrier interface.
+type metadataCarrier metadata.MD
+
+var _ propagation.TextMapCarrier = metadataCarrier{}
+
+// Get implements the propagation.TextMapCarrier interface.
+func (c metadataCarrier) Get(key string) string {
+	if values := metadata.MD(c).Get(key); len(values) > 0 {
+		return values[0]
+	}
+
+	return ""
+}
+
+// Set implements the propagation.TextMapCarrier interface.
+func (c metadataCarrier) Set(key, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+// Keys implements the propagation.TextMapCarrier interface.
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+
+	return keys
+}
+
+// rpcAttributes returns the span name and attributes of a gRPC method
+// named like /package.Service/Method.
+func rpcAttributes(fullMethod string) (string, []attribute.KeyValue) {
+	name := strings.TrimPrefix(fullMethod, "/")
+	attrs := []attribute.KeyValue{semconv.RPCSystemKey.String("grpc")}
+	if service, method, ok := strings.Cut(name, "/"); ok {
+		attrs = append(attrs, semconv.RPCServiceKey.String(service), semconv.RPCMethodKey.String(method))
+	}
+
+	return name, attrs
+}
+
+// peerAttributes returns the attributes of the remote end of a connection.
+func peerAttributes(addr net.Addr) []attribute.KeyValue {
+	if addr == nil {
+		return nil
+	}
+
+	host, port, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return nil
+	}
+
+	return []attribute.KeyValue{semconv.NetPeerIPKey.String(host), semconv.NetPeerPortKey.String(port)}
+}
+
+// startRPCSpan starts the span of an rpc, extracting the remote context from
+// the incoming metadata for servers and injecting it in the outgoing one
+// for clients.
+func startRPCSpan(ctx context.Context, kind trace.SpanKind, fullMethod string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	propagator := otel.GetTextMapPropagator()
+	if kind == trace.SpanKindServer {
+		md, _ := metadata.FromIncomingContext(ctx)
+		ctx = propagator.Extract(ctx, metadataCarrier(md))
+	}
+
+	name, rpcAttrs := rpcAttributes(fullMethod)
+	ctx, span := otel.GetTracerProvider().Tracer(instrumentationName, trace.WithSchemaURL(semconv.SchemaURL)).Start(ctx, name,
+		trace.WithSpanKind(kind),
+		trace.WithAttributes(append(rpcAttrs, attrs...)...),
+	)
+
+	if kind == trace.SpanKindClient {
+		md, _ := metadata.FromOutgoingContext(ctx)
+		md = md.Copy()
+		propagator.Inject(ctx, metadataCarrier(md))
+		ctx = metadata.NewOutgoingContext(ctx, md)
+	}
+
+	return ctx, span
+}
+
+// endRPCSpan records the status of an rpc on its span, the span is not ended.
+func endRPCSpan(span trace.Span, err error) {
+	s, _ := status.FromError(err)
+	span.SetAttributes(semconv.RPCGRPCStatusCodeKey.Int(int(s.Code())))
+	if err != nil {
+		span.SetStatus(codes.Error, s.Message())
+	}
+}
+
+type rpcStateKey struct{}
+
+type connStateKey struct{}
+
+// connState tracks the span of a connection between the stats handler events.
+type connState struct {
+	info *stats.ConnTagInfo
+	span trace.Span
+	rpcs atomic.Int64
+}
+
+// rpcState tracks the span of an rpc between the stats handler events.
+type rpcState struct {
+	span     trace.Span
+	sent     int64
+	received int64
+}
+
+// grpcStatsHandler records rpc spans from the gRPC stats events, including
+// the wire size of every message and the peer of the connection.
+//
+// Connections are recorded as grpc.connection spans of their own, from the
+// connection being established to it being closed. Servers also record the
+// number of rpcs carried by the connection, the rpcs of clients not being
+// tied to a connection by gRPC.
+type grpcStatsHandler struct {
+	kind trace.SpanKind
+}
+
+var _ stats.Handler = (*grpcStatsHandler)(nil)
+
+// NewGRPCServerHandler returns a gRPC stats.Handler recording server spans with
+// the provider and propagators registered by the pipeline, to be installed with
+// grpc.StatsHandler when the interceptor chain is already used for something else.
+func NewGRPCServerHandler() stats.Handler {
+	return &grpcStatsHandler{kind: trace.SpanKindServer}
+}
+
+// NewGRPCClientHandler returns a gRPC stats.Handler recording client spans with
+// the provider and propagators registered by the pipeline, to be installed with
+// grpc.WithStatsHandler.
+func NewGRPCClientHandler() stats.Handler {
+	return &grpcStatsHandler{kind: trace.SpanKindClient}
+}
+
+// TagConn implements the stats.Handler interface.
+func (h *grpcStatsHandler) TagConn(ctx context.Context, info *stats.ConnTagInfo) context.Context {
+	return context.WithValue(ctx, connStateKey{}, &connState{info: info})
+}
+
+// HandleConn implements the stats.Handler interface.
+func (h *grpcStatsHandler) HandleConn(ctx context.Context, cs stats.ConnStats) {
+	state, ok := ctx.Value(connStateKey{}).(*connState)
+	if !ok {
+		return
+	}
+
+	switch cs.(type) {
+	case *stats.ConnBegin:
+		_, state.span = otel.GetTracerProvider().Tracer(instrumentationName, trace.WithSchemaURL(semconv.SchemaURL)).Start(ctx, "grpc.connection",
+			trace.WithNewRoot(),
+			trace.WithSpanKind(h.kind),
+			trace.WithAttributes(append(peerAttributes(state.info.RemoteAddr), semconv.RPCSystemKey.String("grpc"))...),
+		)
+	case *stats.ConnEnd:
+		if state.span == nil {
+			return
+		}
+		if h.kind == trace.SpanKindServer {
+			state.span.SetAttributes(connectionRPCsKey.Int64(state.rpcs.Load()))
+		}
+		state.span.End()
+	}
+}
+
+// TagRPC implements the stats.Handler interface.
+func (h *grpcStatsHandler) TagRPC(ctx context.Context, info *stats.RPCTagInfo) context.Context {
+	var attrs []attribute.KeyValue
+	if conn, ok := ctx.Value(connStateKey{}).(*connState); ok {
+		conn.rpcs.Add(1)
+		attrs = peerAttributes(conn.info.RemoteAddr)
+	}
+
+	ctx, span := startRPCSpan(ctx, h.kind, info.FullMethodName, attrs...)
+
+	return context.WithValue(ctx, rpcStateKey{}, &rpcState{span: span})
+}
+
+// HandleRPC implements the stats.Handler interface.
+func (h *grpcStatsHandler) HandleRPC(ctx context.Context, rs stats.RPCStats) {
+	state, ok := ctx.Value(rpcStateKey{}).(*rpcState)
+	if !ok {
+		return
+	}
+
+	switch rs := rs.(type) {
+	case *stats.InPayload:
+		state.received++
+		state.span.AddEvent("message", trace.WithAttributes(
+			messageTypeKey.String("RECEIVED"),
+			messageIDKey.Int64(state.received),
+			messageCompressedSizeKey.Int(rs.WireLength),
+			messageUncompressedSizeKey.Int(rs.Length),
+		))
+	case *stats.OutPayload:
+		state.sent++
+		state.span.AddEvent("message", trace.WithAttributes(
+			messageTypeKey.String("SENT"),
+			messageIDKey.Int64(state.sent),
+			messageCompressedSizeKey.Int(rs.WireLength),
+			messageUncompressedSizeKey.Int(rs.Length),
+		))
+	case *stats.End:
+		endRPCSpan(state.span, rs.Error)
+		state.span.End(trace.WithTimestamp(rs.EndTime))
+	}
+}
+
+// GRPCServerOptions returns the server options installing the interceptors
+// recording server spans with the provider and propagators registered by the pipeline.
+//
+//	server := grpc.NewServer(otel.GRPCServerOptions()...)
+func GRPCServerOptions() []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(unaryServerInterceptor),
+		grpc.ChainStreamInterceptor(streamServerInterceptor),
+	}
+}
+
+// GRPCClientOptions returns the dial options installing the interceptors
+// recording client spans with the provider and propagators registered by the pipeline.
+//
+//	conn, err := grpc.NewClient(target, otel.GRPCClientOptions()...)
+func GRPCClientOptions() []grpc.DialOption {
+	return []grpc.DialOption{
+		grpc.WithChainUnaryInterceptor(unaryClientInterceptor),
+		grpc.WithChainStreamInterceptor(streamClientInterceptor),
+	}
+}
+
+func peerAttributesFromContext(ctx context.Context) []attribute.KeyValue {
+	if p, ok := peer.FromContext(ctx); ok {
+		return peerAttributes(p.Addr)
+	}
+
+	return nil
+}
+
+func unaryServerInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx, span := startRPCSpan(ctx, trace.SpanKindServer, info.FullMethod, peerAttributesFromContext(ctx)...)
+	defer span.End()
+
+	resp, err := handler(ctx, req)
+	endRPCSpan(span, err)
+
+	return resp, err
+}
+
+// serverStream replaces the context of a stream with the one holding its span.
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+func streamServerInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx := ss.Context()
+	ctx, span := startRPCSpan(ctx, trace.SpanKindServer, info.FullMethod, peerAttributesFromContext(ctx)...)
+	defer span.End()
+
+	err := handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+	endRPCSpan(span, err)
+
+	return err
+}
+
+func unaryClientInterceptor(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	ctx, span := startRPCSpan(ctx, trace.SpanKindClient, method)
+	defer span.End()
+
+	err := invoker(ctx, method, req, reply, cc, opts...)
+	endRPCSpan(span, err)
+
+	return err
+}
+
+// clientStream ends the span of a stream once it's finished, which is when
+// receiving a message fails or its context is done, whichever comes first.
+type clientStream struct {
+	grpc.ClientStream
+	span trace.Span
+	once sync.Once
+	stop func() bool
+}
+
+func (s *clientStream) RecvMsg(m interface{}) error {
+	err := s.ClientStream.RecvMsg(m)
+	if err != nil {
+		s.stop()
+		s.end(err)
+	}
+
+	return err
+}
+
+func (s *clientStream) end(err error) {
+	s.once.Do(func() {
+		if err == io.EOF {
+			err = nil
+		}
+		endRPCSpan(s.span, err)
+		s.span.End()
+	})
+}
+
+func streamClientInterceptor(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	ctx, span := startRPCSpan(ctx, trace.SpanKindClient, method)
+
+	cs, err := streamer(ctx, desc, cc, method, opts...)
+	if err != nil {
+		endRPCSpan(span, err)
+		span.End()
+		return nil, err
+	}
+
+	stream := &clientStream{ClientStream: cs, span: span}
+	stream.stop = context.AfterFunc(ctx, func() {
+		stream.end(status.FromContextError(ctx.Err()).Err())
+	})
+
+	return stream, nil
+}
@@ -0,0 +1,146 @@
+package otel
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	oteltrace "go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// dialTestServer serves the gRPC health service in memory
+// and returns a client connected to it.
+func dialTestServer(t *testing.T, serverOpts []grpc.ServerOption, dialOpts []grpc.DialOption) healthpb.HealthClient {
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer(serverOpts...)
+	healthpb.RegisterHealthServer(server, health.NewServer())
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	dialOpts = append(dialOpts,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+	)
+	conn, err := grpc.NewClient("passthrough:///bufconn", dialOpts...)
+	assert.Nil(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return healthpb.NewHealthClient(conn)
+}
+
+func TestGRPC_StatsHandlers(t *testing.T) {
+	recorder := setTestGlobals(t)
+
+	client := dialTestServer(t,
+		[]grpc.ServerOption{grpc.StatsHandler(NewGRPCServerHandler())},
+		[]grpc.DialOption{grpc.WithStatsHandler(NewGRPCClientHandler())},
+	)
+	_, err := client.Check(context.TODO(), &healthpb.HealthCheckRequest{})
+	assert.Nil(t, err)
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 2)
+
+	var server, clientSpan = spans[0], spans[1]
+	if server.SpanKind() != oteltrace.SpanKindServer {
+		server, clientSpan = clientSpan, server
+	}
+	assert.Equal(t, "grpc.health.v1.Health/Check", server.Name())
+	assert.Equal(t, clientSpan.SpanContext().SpanID(), server.Parent().SpanID())
+	assert.Contains(t, server.Attributes(), attribute.String("rpc.method", "Check"))
+	assert.Contains(t, server.Attributes(), attribute.Int("rpc.grpc.status_code", 0))
+	assert.Len(t, server.Events(), 2)
+	assert.Equal(t, "message", server.Events()[0].Name)
+}
+
+func TestGRPC_UnaryInterceptors(t *testing.T) {
+	recorder := setTestGlobals(t)
+
+	client := dialTestServer(t, GRPCServerOptions(), GRPCClientOptions())
+	_, err := client.Check(context.TODO(), &healthpb.HealthCheckRequest{Service: "unknown"})
+	assert.NotNil(t, err)
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 2)
+	assert.Equal(t, oteltrace.SpanKindServer, spans[0].SpanKind())
+	assert.Equal(t, spans[1].SpanContext().SpanID(), spans[0].Parent().SpanID())
+	assert.Contains(t, spans[0].Attributes(), attribute.Int("rpc.grpc.status_code", 5))
+	assert.Equal(t, codes.Error, spans[1].Status().Code)
+}
+
+func TestGRPC_StreamInterceptors(t *testing.T) {
+	recorder := setTestGlobals(t)
+
+	client := dialTestServer(t, GRPCServerOptions(), GRPCClientOptions())
+	ctx, cancel := context.WithCancel(context.TODO())
+	stream, err := client.Watch(ctx, &healthpb.HealthCheckRequest{})
+	assert.Nil(t, err)
+
+	_, err = stream.Recv()
+	assert.Nil(t, err)
+	cancel()
+	_, err = stream.Recv()
+	assert.NotNil(t, err)
+
+	assert.Eventually(t, func() bool { return len(recorder.Ended()) == 2 }, time.Second, 10*time.Millisecond)
+	for _, span := range recorder.Ended() {
+		assert.Equal(t, "grpc.health.v1.Health/Watch", span.Name())
+	}
+}
+
+func TestGRPC_StreamEndsOnCancel(t *testing.T) {
+	recorder := setTestGlobals(t)
+
+	client := dialTestServer(t, nil, GRPCClientOptions())
+	ctx, cancel := context.WithCancel(context.TODO())
+	stream, err := client.Watch(ctx, &healthpb.HealthCheckRequest{})
+	assert.Nil(t, err)
+	_, err = stream.Recv()
+	assert.Nil(t, err)
+
+	// the stream is abandoned without receiving its error
+	cancel()
+
+	assert.Eventually(t, func() bool { return len(recorder.Ended()) == 1 }, time.Second, 10*time.Millisecond)
+	assert.Contains(t, recorder.Ended()[0].Attributes(), attribute.Int("rpc.grpc.status_code", 1))
+}
+
+func TestGRPC_StatsHandlersRecordConnections(t *testing.T) {
+	recorder := setTestGlobals(t)
+
+	t.Run("connection", func(t *testing.T) {
+		client := dialTestServer(t,
+			[]grpc.ServerOption{grpc.StatsHandler(NewGRPCServerHandler())},
+			[]grpc.DialOption{grpc.WithStatsHandler(NewGRPCClientHandler())},
+		)
+		for range 2 {
+			_, err := client.Check(context.TODO(), &healthpb.HealthCheckRequest{})
+			assert.Nil(t, err)
+		}
+	})
+
+	assert.Eventually(t, func() bool {
+		kinds := map[oteltrace.SpanKind]bool{}
+		for _, span := range recorder.Ended() {
+			if span.Name() != "grpc.connection" {
+				continue
+			}
+			kinds[span.SpanKind()] = true
+			if span.SpanKind() == oteltrace.SpanKindServer {
+				assert.Contains(t, span.Attributes(), attribute.Int64("rpc.grpc.connection.rpcs", 2))
+			}
+		}
+		return kinds[oteltrace.SpanKindServer] && kinds[oteltrace.SpanKindClient]
+	}, time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,192 @@
+package otel
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/sdk/trace"
+)
+
+// PipelineHealth is the export state of a pipeline reported by HealthHandler.
+//
+// Status is "ok", "degraded" when the last export failed, "not started"
+// before ExportPipeline succeeded or "disabled" with Config.Disabled.
+// SpansDropped counts the spans of the failed exports and QueueDepth the
+// spans ended waiting for export, including the batch being exported.
+// SpansRejected counts the spans of the exports partially rejected by the
+// endpoint, they're counted as dropped too, see PartialSuccessError.
+// BufferedBytes is their estimated size, only set with Config.MemoryLimit.
+type PipelineHealth struct {
+	Status        string     `json:"status"`
+	Exporter      string     `json:"exporter"`
+	LastExport    *time.Time `json:"last_export,omitempty"`
+	LastError     string     `json:"last_error,omitempty"`
+	SpansExported int64      `json:"spans_exported"`
+	SpansDropped  int64      `json:"spans_dropped"`
+	QueueDepth    int64      `json:"queue_depth"`
+	SpansRejected int64      `json:"spans_rejected,omitempty"`
+	BufferedBytes int64      `json:"buffered_bytes,omitempty"`
+}
+
+// exportHealth tracks the spans handed to the export processor of
+// a pipeline and the outcome of their exports.
+type exportHealth struct {
+	queued   atomic.Int64
+	exported atomic.Int64
+	dropped  atomic.Int64
+	rejected atomic.Int64
+	alert    *dropRateMonitor
+	memory   *memoryLimiter
+
+	mu          sync.Mutex
+	lastExport  time.Time
+	lastError   error
+	lastErrorAt time.Time
+}
+
+func (h *exportHealth) exporter(exp trace.SpanExporter) trace.SpanExporter {
+	return &healthExporter{SpanExporter: exp, health: h}
+}
+
+func (h *exportHealth) processor(p trace.SpanProcessor) trace.SpanProcessor {
+	return &healthProcessor{SpanProcessor: p, health: h}
+}
+
+// drop accounts for s dropped before being exported.
+func (h *exportHealth) drop(s trace.ReadOnlySpan) {
+	h.dropped.Add(1)
+	h.memory.release(s)
+}
+
+// healthProcessor counts the sampled spans, the ones its processor exports.
+type healthProcessor struct {
+	trace.SpanProcessor
+	health *exportHealth
+}
+
+// OnEnd implements the trace.SpanProcessor interface.
+func (p *healthProcessor) OnEnd(s trace.ReadOnlySpan) {
+	h := p.health
+	if s.SpanContext().IsSampled() {
+		if !h.memory.reserve(s) {
+			h.dropped.Add(1)
+			h.alert.check(h, time.Now())
+			return
+		}
+		h.queued.Add(1)
+	}
+	p.SpanProcessor.OnEnd(s)
+	h.alert.check(h, time.Now())
+}
+
+// healthExporter records the outcome of every export.
+type healthExporter struct {
+	trace.SpanExporter
+	health *exportHealth
+}
+
+// ExportSpans implements the trace.SpanExporter interface.
+func (e *healthExporter) ExportSpans(ctx context.Context, spans []trace.ReadOnlySpan) error {
+	err := e.SpanExporter.ExportSpans(ctx, spans)
+
+	h := e.health
+	h.mu.Lock()
+	var partial *PartialSuccessError
+	switch {
+	case errors.As(err, &partial):
+		rejected := min(partial.Rejected, int64(len(spans)))
+		h.rejected.Add(rejected)
+		h.dropped.Add(rejected)
+		h.exported.Add(int64(len(spans)) - rejected)
+		h.lastExport = time.Now()
+		h.lastError, h.lastErrorAt = err, h.lastExport
+	case err != nil:
+		h.dropped.Add(int64(len(spans)))
+		h.lastError, h.lastErrorAt = err, time.Now()
+	default:
+		h.exported.Add(int64(len(spans)))
+		h.lastExport = time.Now()
+	}
+	h.mu.Unlock()
+	h.memory.release(spans...)
+	h.alert.check(h, time.Now())
+
+	return err
+}
+
+// HealthHandler returns a handler reporting the PipelineHealth of the
+// pipeline built by e as JSON, with a 503 status unless it's ok or disabled
+// so it can back a readiness probe:
+//
+//	mux.Handle("/healthz/otel", otel.HealthHandler(exporter))
+func HealthHandler(e Exporter) http.Handler {
+	p, ok := pipelineOf(e)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !ok {
+			http.Error(w, "unsupported exporter", http.StatusNotImplemented)
+			return
+		}
+
+		health := p.healthOf(e)
+		w.Header().Set("Content-Type", "application/json")
+		if health.Status != "ok" && health.Status != "disabled" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		_ = json.NewEncoder(w).Encode(health)
+	})
+}
+
+// Health returns the PipelineHealth of the pipeline built by e,
+// ok is false when e wasn't created by NewExporter.
+func Health(e Exporter) (health PipelineHealth, ok bool) {
+	p, ok := pipelineOf(e)
+	if !ok {
+		return PipelineHealth{}, false
+	}
+
+	return p.healthOf(e), true
+}
+
+func (p *pipeline) healthOf(e Exporter) PipelineHealth {
+	h := p.health
+	exported, dropped := h.exported.Load(), h.dropped.Load()
+	health := PipelineHealth{
+		Status:        "ok",
+		Exporter:      exporterName(e, p),
+		SpansExported: exported,
+		SpansDropped:  dropped,
+		QueueDepth:    max(h.queued.Load()-exported-dropped, 0),
+		SpansRejected: h.rejected.Load(),
+	}
+	if h.memory != nil {
+		health.BufferedBytes = h.memory.buffered.Load()
+	}
+
+	h.mu.Lock()
+	if !h.lastExport.IsZero() {
+		lastExport := h.lastExport
+		health.LastExport = &lastExport
+	}
+	if h.lastError != nil {
+		health.LastError = h.lastError.Error()
+		if h.lastErrorAt.After(h.lastExport) {
+			health.Status = "degraded"
+		}
+	}
+	h.mu.Unlock()
+
+	switch {
+	case p.config.Disabled:
+		health.Status = "disabled"
+	case p.current() == nil:
+		health.Status = "not started"
+	}
+
+	return health
+}
@@ -0,0 +1,72 @@
+package otel
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// toggleWriter fails the writes while err is set.
+type toggleWriter struct {
+	err error
+}
+
+func (w *toggleWriter) Write(p []byte) (int, error) {
+	if w.err != nil {
+		return 0, w.err
+	}
+	return len(p), nil
+}
+
+func getHealth(t *testing.T, h http.Handler) (int, PipelineHealth) {
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	var health PipelineHealth
+	assert.Nil(t, json.NewDecoder(rec.Body).Decode(&health))
+	return rec.Code, health
+}
+
+func TestHealthHandler_ReportsExports(t *testing.T) {
+	w := &toggleWriter{}
+	exporter := NewExporter(IO, &Config{Writer: w, SyncExport: true}, WithGlobalRegistration(false))
+	handler := HealthHandler(exporter)
+
+	code, health := getHealth(t, handler)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, "not started", health.Status)
+
+	pipeline, err := exporter.ExportPipeline(context.TODO())
+	assert.Nil(t, err)
+	defer pipeline.Shutdown(context.TODO())
+	_, span := pipeline.Tracer("sample").Start(context.TODO(), "sample span")
+	span.End()
+
+	code, health = getHealth(t, handler)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, PipelineHealth{Status: "ok", Exporter: "stdout", LastExport: health.LastExport, SpansExported: 1}, health)
+	assert.NotNil(t, health.LastExport)
+
+	w.err = errors.New("disk full")
+	_, span = pipeline.Tracer("sample").Start(context.TODO(), "sample span")
+	span.End()
+
+	code, health = getHealth(t, handler)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, "degraded", health.Status)
+	assert.Contains(t, health.LastError, "disk full")
+	assert.Equal(t, int64(1), health.SpansDropped)
+	assert.Equal(t, int64(0), health.QueueDepth)
+}
+
+func TestHealthHandler_UnsupportedExporter(t *testing.T) {
+	rec := httptest.NewRecorder()
+	HealthHandler(nil).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	assert.Equal(t, http.StatusNotImplemented, rec.Code)
+}
@@ -0,0 +1,50 @@
+package otel
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/shirou/gopsutil/v4/disk"
+	"go.opentelemetry.io/contrib/instrumentation/host"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// diskDirectionKey tells system.disk.io observations apart.
+const diskDirectionKey = attribute.Key("disk.io.direction")
+
+// startHostMetrics records the system.cpu, system.memory and system.network
+// metrics of the host instrumentation along with system.disk.io on provider.
+func startHostMetrics(provider metric.MeterProvider) error {
+	if err := host.Start(host.WithMeterProvider(provider)); err != nil {
+		return err
+	}
+
+	meter := provider.Meter(instrumentationName)
+	diskIO, err := meter.Int64ObservableCounter("system.disk.io",
+		metric.WithUnit("By"),
+		metric.WithDescription("Disk bytes transferred."),
+	)
+	if err != nil {
+		return err
+	}
+
+	read := metric.WithAttributes(diskDirectionKey.String("read"))
+	write := metric.WithAttributes(diskDirectionKey.String("write"))
+	_, err = meter.RegisterCallback(func(ctx context.Context, o metric.Observer) error {
+		counters, err := disk.IOCountersWithContext(ctx)
+		if err != nil {
+			return fmt.Errorf("could not read disk counters: %w", err)
+		}
+
+		for name, c := range counters {
+			device := attribute.String("system.device", name)
+			o.ObserveInt64(diskIO, int64(c.ReadBytes), read, metric.WithAttributes(device))
+			o.ObserveInt64(diskIO, int64(c.WriteBytes), write, metric.WithAttributes(device))
+		}
+
+		return nil
+	}, diskIO)
+
+	return err
+}
@@ -0,0 +1,61 @@
+package otel
+
+import (
+	"net/http"
+
+	"github.com/rezazadehramin/opentelemetry-go/otel/internal/httpconv"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// httpTransport records a client span for every outgoing request
+// and injects its context in the request headers.
+type httpTransport struct {
+	base http.RoundTripper
+}
+
+// RoundTrip implements the http.RoundTripper interface.
+func (t *httpTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	tracer := otel.GetTracerProvider().Tracer(instrumentationName, trace.WithSchemaURL(httpconv.SchemaURL))
+	ctx, span := tracer.Start(r.Context(), httpconv.SpanName(r.Method, ""),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(httpconv.ClientRequest(r)...),
+	)
+	defer span.End()
+
+	r = r.Clone(ctx)
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(r.Header))
+
+	resp, err := t.base.RoundTrip(r)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+	httpconv.ClientEnd(span, resp.StatusCode)
+
+	return resp, nil
+}
+
+// WrapHTTPClient returns a copy of client, http.DefaultClient when nil, whose
+// requests are recorded as client spans and carry the trace context with the
+// propagators registered by the pipeline.
+//
+// The span ends once the response headers are received.
+func WrapHTTPClient(client *http.Client) *http.Client {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	base := client.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	wrapped := *client
+	wrapped.Transport = &httpTransport{base: base}
+
+	return &wrapped
+}
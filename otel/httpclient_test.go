@@ -0,0 +1,76 @@
+package otel
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+// setTestGlobals registers a recording provider and the W3C propagator
+// globally until the test ends.
+func setTestGlobals(t *testing.T) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	provider, propagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(trace.NewTracerProvider(trace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(provider)
+		otel.SetTextMapPropagator(propagator)
+	})
+
+	return recorder
+}
+
+func TestHTTPClient_RecordsClientSpan(t *testing.T) {
+	recorder := setTestGlobals(t)
+
+	var traceparent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	req, _ := http.NewRequestWithContext(context.TODO(), http.MethodGet, server.URL+"/orders", nil)
+	resp, err := WrapHTTPClient(server.Client()).Do(req)
+	assert.Nil(t, err)
+	resp.Body.Close()
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 1)
+	assert.Equal(t, oteltrace.SpanKindClient, spans[0].SpanKind())
+	assert.Equal(t, "https://opentelemetry.io/schemas/1.4.0", spans[0].InstrumentationScope().SchemaURL)
+	assert.Contains(t, traceparent, spans[0].SpanContext().SpanID().String())
+	assert.Contains(t, spans[0].Attributes(), attribute.Int("http.status_code", http.StatusServiceUnavailable))
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	assert.Empty(t, req.Header.Get("traceparent"))
+}
+
+type failingTransport struct{}
+
+func (failingTransport) RoundTrip(*http.Request) (*http.Response, error) {
+	return nil, errors.New("connection refused")
+}
+
+func TestHTTPClient_RecordsTransportError(t *testing.T) {
+	recorder := setTestGlobals(t)
+
+	client := WrapHTTPClient(&http.Client{Transport: failingTransport{}})
+	_, err := client.Get("http://localhost/orders")
+	assert.NotNil(t, err)
+
+	spans := recorder.Ended()
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	assert.Equal(t, "exception", spans[0].Events()[0].Name)
+}
@@ -0,0 +1,99 @@
+package otel
+
+import (
+	"context"
+	"encoding/binary"
+	"math/rand/v2"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/sdk/trace"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+// timestampIDGenerator generates trace IDs prefixed with the big endian
+// epoch seconds of the trace start, the remaining bytes are random.
+type timestampIDGenerator struct {
+	now func() time.Time
+}
+
+var _ trace.IDGenerator = (*timestampIDGenerator)(nil)
+
+// NewTimestampIDGenerator returns a trace.IDGenerator embedding a coarse
+// timestamp in the first 4 bytes of every trace ID, so downstream storage
+// can partition and expire traces by ID alone.
+//
+// now is the clock the timestamp is read from, a skew corrected clock
+// can be plugged in, time.Now is used when nil.
+func NewTimestampIDGenerator(now func() time.Time) trace.IDGenerator {
+	if now == nil {
+		now = time.Now
+	}
+
+	return &timestampIDGenerator{now: now}
+}
+
+// NewXRayIDGenerator returns a trace.IDGenerator compatible with AWS X-Ray,
+// which rejects trace IDs not starting with the epoch seconds of the trace.
+// Use it with the xray propagator when exporting to X-Ray through a collector.
+func NewXRayIDGenerator() trace.IDGenerator {
+	// X-Ray trace IDs are the 4 bytes timestamp followed by 12 random bytes
+	return NewTimestampIDGenerator(nil)
+}
+
+// NewIDs implements the trace.IDGenerator interface.
+func (g *timestampIDGenerator) NewIDs(ctx context.Context) (oteltrace.TraceID, oteltrace.SpanID) {
+	var tid oteltrace.TraceID
+	binary.BigEndian.PutUint32(tid[:4], uint32(g.now().Unix()))
+	binary.BigEndian.PutUint32(tid[4:8], rand.Uint32())
+	binary.BigEndian.PutUint64(tid[8:], rand.Uint64())
+
+	return tid, g.NewSpanID(ctx, tid)
+}
+
+// NewSpanID implements the trace.IDGenerator interface.
+func (g *timestampIDGenerator) NewSpanID(context.Context, oteltrace.TraceID) oteltrace.SpanID {
+	var sid oteltrace.SpanID
+	for !sid.IsValid() {
+		binary.BigEndian.PutUint64(sid[:], rand.Uint64())
+	}
+
+	return sid
+}
+
+// TraceIDTime returns the timestamp embedded in a trace ID
+// generated by NewTimestampIDGenerator.
+func TraceIDTime(id oteltrace.TraceID) time.Time {
+	return time.Unix(int64(binary.BigEndian.Uint32(id[:4])), 0)
+}
+
+// sequentialIDGenerator numbers the trace and span IDs it generates.
+type sequentialIDGenerator struct {
+	traces atomic.Uint64
+	spans  atomic.Uint64
+}
+
+var _ trace.IDGenerator = (*sequentialIDGenerator)(nil)
+
+// NewSequentialIDGenerator returns a trace.IDGenerator numbering trace and
+// span IDs from 1, so the spans written by tests with Config.Deterministic
+// can be compared to golden files. It must not be used in production.
+func NewSequentialIDGenerator() trace.IDGenerator {
+	return &sequentialIDGenerator{}
+}
+
+// NewIDs implements the trace.IDGenerator interface.
+func (g *sequentialIDGenerator) NewIDs(ctx context.Context) (oteltrace.TraceID, oteltrace.SpanID) {
+	var tid oteltrace.TraceID
+	binary.BigEndian.PutUint64(tid[8:], g.traces.Add(1))
+
+	return tid, g.NewSpanID(ctx, tid)
+}
+
+// NewSpanID implements the trace.IDGenerator interface.
+func (g *sequentialIDGenerator) NewSpanID(context.Context, oteltrace.TraceID) oteltrace.SpanID {
+	var sid oteltrace.SpanID
+	binary.BigEndian.PutUint64(sid[:], g.spans.Add(1))
+
+	return sid
+}
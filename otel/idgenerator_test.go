@@ -0,0 +1,68 @@
+package otel
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIDGenerator_EmbedsTimestamp(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	g := NewTimestampIDGenerator(func() time.Time { return now })
+
+	tid, sid := g.NewIDs(context.TODO())
+
+	assert.True(t, tid.IsValid())
+	assert.True(t, sid.IsValid())
+	assert.Equal(t, "6553f100", tid.String()[:8])
+	assert.Equal(t, now, TraceIDTime(tid))
+}
+
+func TestIDGenerator_ConfiguredOnPipeline(t *testing.T) {
+	setEnv()
+	defer unsetEnv()
+
+	c := NewENVConfig()
+	c.Writer = io.Discard
+	c.TimestampTraceIDs = true
+
+	pipeline, err := NewExporter(IO, c).ExportPipeline(context.TODO())
+	assert.Nil(t, err)
+	defer pipeline.Shutdown(context.TODO())
+
+	_, span := pipeline.Tracer("sample").Start(context.TODO(), "sample span")
+	span.End()
+
+	assert.WithinDuration(t, time.Now(), TraceIDTime(span.SpanContext().TraceID()), 2*time.Second)
+}
+
+func TestIDGenerator_CustomGeneratorTakesPrecedence(t *testing.T) {
+	setEnv()
+	defer unsetEnv()
+
+	now := time.Date(2023, 11, 14, 22, 13, 20, 0, time.UTC)
+	c := NewENVConfig()
+	c.Writer = io.Discard
+	c.IDGenerator = NewTimestampIDGenerator(func() time.Time { return now })
+	c.TimestampTraceIDs = true
+
+	pipeline, err := NewExporter(IO, c).ExportPipeline(context.TODO())
+	assert.Nil(t, err)
+	defer pipeline.Shutdown(context.TODO())
+
+	_, span := pipeline.Tracer("sample").Start(context.TODO(), "sample span")
+	span.End()
+
+	assert.True(t, now.Equal(TraceIDTime(span.SpanContext().TraceID())))
+}
+
+func TestXRayIDGenerator_PrefixesEpochSeconds(t *testing.T) {
+	tid, sid := NewXRayIDGenerator().NewIDs(context.TODO())
+
+	assert.True(t, tid.IsValid())
+	assert.True(t, sid.IsValid())
+	assert.WithinDuration(t, time.Now(), TraceIDTime(tid), 2*time.Second)
+}
@@ -0,0 +1,28 @@
+package otel
+
+import (
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// processInstanceID is the service.instance.id of the pipelines of this
+// process without Config.ServiceInstanceID, shared so all of them report
+// the same instance.
+var processInstanceID = sync.OnceValue(func() string {
+	return uuid.NewString()
+})
+
+// serviceInstanceID returns Config.ServiceInstanceID, defaulting to
+// a random UUID generated once per process, or to a UUID derived from the
+// service name with Config.Deterministic so golden files stay stable.
+func (c *Config) serviceInstanceID() string {
+	if c.ServiceInstanceID != "" {
+		return c.ServiceInstanceID
+	}
+	if c.Deterministic {
+		return uuid.NewSHA1(uuid.NameSpaceOID, []byte(c.ServiceName)).String()
+	}
+
+	return processInstanceID()
+}
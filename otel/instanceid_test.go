@@ -0,0 +1,24 @@
+package otel
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResource_GeneratesInstanceID(t *testing.T) {
+	instanceID := func(c *Config) string {
+		res, err := c.resource(context.TODO())
+		assert.Nil(t, err)
+		value, _ := res.Set().Value("service.instance.id")
+		return value.AsString()
+	}
+
+	generated := instanceID(&Config{ServiceName: "orders"})
+	_, err := uuid.Parse(generated)
+	assert.Nil(t, err)
+	assert.Equal(t, generated, instanceID(&Config{ServiceName: "billing"}))
+	assert.Equal(t, "orders-1", instanceID(&Config{ServiceInstanceID: "orders-1"}))
+}
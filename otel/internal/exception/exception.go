@@ -0,0 +1,29 @@
+// Package exception records recovered panics as semconv exception events.
+package exception
+
+import (
+	"fmt"
+	"runtime/debug"
+
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RecordPanic adds an exception event holding the type, message and stack
+// trace of a recovered panic to span and sets its status to error, escaped
+// tells whether the panic is propagated afterwards.
+func RecordPanic(span trace.Span, recovered interface{}, escaped bool) {
+	message := fmt.Sprint(recovered)
+	if err, ok := recovered.(error); ok {
+		message = err.Error()
+	}
+
+	span.AddEvent(semconv.ExceptionEventName, trace.WithAttributes(
+		semconv.ExceptionTypeKey.String(fmt.Sprintf("%T", recovered)),
+		semconv.ExceptionMessageKey.String(message),
+		semconv.ExceptionStacktraceKey.String(string(debug.Stack())),
+		semconv.ExceptionEscapedKey.Bool(escaped),
+	))
+	span.SetStatus(codes.Error, "panic: "+message)
+}
@@ -0,0 +1,150 @@
+// Package httpconv holds the HTTP semantic conventions and server span
+// handling shared by the HTTP instrumentations of this module.
+package httpconv
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/rezazadehramin/opentelemetry-go/otel/internal/exception"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// SchemaURL is the schema URL of the semantic conventions of the attributes,
+// set on the tracers of the HTTP instrumentations.
+const SchemaURL = semconv.SchemaURL
+
+// RouteKey is the attribute holding the matched route template.
+const RouteKey = semconv.HTTPRouteKey
+
+// ClientRequest returns the attributes of an outgoing request.
+func ClientRequest(r *http.Request) []attribute.KeyValue {
+	return semconv.HTTPClientAttributesFromHTTPRequest(r)
+}
+
+// ServerRequest returns the attributes of an incoming request
+// served by route, route can be empty when unknown.
+func ServerRequest(route string, r *http.Request) []attribute.KeyValue {
+	return semconv.HTTPServerAttributesFromHTTPRequest("", route, r)
+}
+
+// SpanName returns the name of the span of a request served by route,
+// only the method is used when the route is unknown to keep cardinality low.
+func SpanName(method, route string) string {
+	if route == "" {
+		return "HTTP " + method
+	}
+
+	return method + " " + route
+}
+
+// Server starts and ends the spans of incoming requests.
+type Server struct {
+	Tracer     trace.Tracer
+	Propagator propagation.TextMapPropagator
+}
+
+// Start extracts the remote span context from r and starts its server span.
+func (s Server) Start(r *http.Request, route string) (context.Context, trace.Span) {
+	ctx := s.Propagator.Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+
+	return s.Tracer.Start(ctx, SpanName(r.Method, route),
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithAttributes(ServerRequest(route, r)...),
+	)
+}
+
+// End records the response status code and, when it became known while
+// serving, the route on span before ending it.
+func (s Server) End(span trace.Span, method, route string, status int) {
+	if route != "" {
+		span.SetName(SpanName(method, route))
+		span.SetAttributes(RouteKey.String(route))
+	}
+
+	span.SetAttributes(semconv.HTTPAttributesFromHTTPStatusCode(status)...)
+	span.SetStatus(semconv.SpanStatusFromHTTPStatusCodeAndSpanKind(status, trace.SpanKindServer))
+	span.End()
+}
+
+// EndPanic records a panic recovered while serving and ends span with a 500
+// status, the panic should be propagated afterwards.
+func (s Server) EndPanic(span trace.Span, method, route string, recovered interface{}) {
+	exception.RecordPanic(span, recovered, true)
+	s.End(span, method, route, http.StatusInternalServerError)
+}
+
+// ClientEnd records the response status code on an outgoing request span.
+func ClientEnd(span trace.Span, status int) {
+	span.SetAttributes(semconv.HTTPAttributesFromHTTPStatusCode(status)...)
+	span.SetStatus(semconv.SpanStatusFromHTTPStatusCodeAndSpanKind(status, trace.SpanKindClient))
+}
+
+// PatternRoute returns the route of a net/http ServeMux pattern
+// like "GET example.com/users/{id}", without the method and host.
+func PatternRoute(pattern string) string {
+	if i := strings.IndexByte(pattern, ' '); i >= 0 {
+		pattern = strings.TrimLeft(pattern[i+1:], " ")
+	}
+	if i := strings.IndexByte(pattern, '/'); i > 0 {
+		pattern = pattern[i:]
+	}
+
+	return pattern
+}
+
+// ResponseWriter records the status code written by a handler.
+type ResponseWriter struct {
+	http.ResponseWriter
+	Status int
+}
+
+// NewResponseWriter wraps w, the status defaults to 200 until
+// the handler writes another one.
+func NewResponseWriter(w http.ResponseWriter) *ResponseWriter {
+	return &ResponseWriter{ResponseWriter: w, Status: http.StatusOK}
+}
+
+// WriteHeader implements the http.ResponseWriter interface.
+func (w *ResponseWriter) WriteHeader(status int) {
+	w.Status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Flush implements the http.Flusher interface.
+func (w *ResponseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack implements the http.Hijacker interface, http.ErrNotSupported is
+// returned when the wrapped writer isn't one.
+func (w *ResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if h, ok := w.ResponseWriter.(http.Hijacker); ok {
+		return h.Hijack()
+	}
+
+	return nil, nil, http.ErrNotSupported
+}
+
+// Push implements the http.Pusher interface, http.ErrNotSupported is
+// returned when the wrapped writer isn't one.
+func (w *ResponseWriter) Push(target string, opts *http.PushOptions) error {
+	if p, ok := w.ResponseWriter.(http.Pusher); ok {
+		return p.Push(target, opts)
+	}
+
+	return http.ErrNotSupported
+}
+
+// Unwrap returns the wrapped writer for the http.ResponseController.
+func (w *ResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
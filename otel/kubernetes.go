@@ -0,0 +1,71 @@
+package otel
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.43.0"
+)
+
+// replicaSetPodName matches the names given to the pods of a deployment,
+// <deployment>-<replica set hash>-<pod suffix>.
+var replicaSetPodName = regexp.MustCompile(`^(.+)-[a-z0-9]{6,10}-[a-z0-9]{5}$`)
+
+// kubernetesDetector sets the k8s.* attributes of the pod the process runs in.
+//
+// Values come from the K8S_POD_NAME, K8S_NAMESPACE_NAME, K8S_NODE_NAME and
+// K8S_DEPLOYMENT_NAME variables, usually set with the downward API, falling
+// back to the hostname for the pod name and the service account files for the
+// namespace. The deployment is derived from the pod name when not set.
+type kubernetesDetector struct {
+	getenv            func(string) string
+	serviceAccountDir string
+}
+
+// Detect implements the resource.Detector interface.
+func (d kubernetesDetector) Detect(context.Context) (*resource.Resource, error) {
+	if d.getenv("KUBERNETES_SERVICE_HOST") == "" {
+		return resource.Empty(), nil
+	}
+
+	pod := d.getenv("K8S_POD_NAME")
+	if pod == "" {
+		pod = d.getenv("HOSTNAME")
+	}
+	namespace := d.getenv("K8S_NAMESPACE_NAME")
+	if namespace == "" {
+		namespace = d.readFile("namespace")
+	}
+	deployment := d.getenv("K8S_DEPLOYMENT_NAME")
+	if m := replicaSetPodName.FindStringSubmatch(pod); deployment == "" && m != nil {
+		deployment = m[1]
+	}
+
+	var attrs []attribute.KeyValue
+	for _, kv := range []attribute.KeyValue{
+		semconv.K8SPodNameKey.String(pod),
+		semconv.K8SNamespaceNameKey.String(namespace),
+		semconv.K8SNodeNameKey.String(d.getenv("K8S_NODE_NAME")),
+		semconv.K8SDeploymentNameKey.String(deployment),
+	} {
+		if kv.Value.AsString() != "" {
+			attrs = append(attrs, kv)
+		}
+	}
+
+	return resource.NewSchemaless(attrs...), nil
+}
+
+func (d kubernetesDetector) readFile(name string) string {
+	content, err := os.ReadFile(filepath.Join(d.serviceAccountDir, name))
+	if err != nil {
+		return ""
+	}
+
+	return strings.TrimSpace(string(content))
+}
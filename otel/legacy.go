@@ -0,0 +1,138 @@
+package otel
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/trace"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+// LegacyExtractor extracts a span context from a legacy or custom header,
+// easing the migration from homegrown tracing.
+//
+// The span context is used as the parent of the server span when the
+// standard propagators found none, or recorded as a link of the server span
+// when Link is set.
+type LegacyExtractor struct {
+	Header string
+	Parse  func(value string) (oteltrace.SpanContext, bool)
+	Link   bool
+}
+
+// NewXRayExtractor returns an extractor of the AWS X-Ray header,
+// X-Amzn-Trace-Id.
+func NewXRayExtractor() LegacyExtractor {
+	return LegacyExtractor{Header: xrayHeader, Parse: parseXRayHeader}
+}
+
+// NewCorrelationIDExtractor returns an extractor of a correlation ID header
+// like X-Correlation-Id, a sampled span context is derived from its value so
+// every request sharing the ID ends up in the same trace.
+//
+// UUIDs and 32 hex digit IDs are used as the trace ID as is,
+// other values are hashed.
+func NewCorrelationIDExtractor(header string) LegacyExtractor {
+	return LegacyExtractor{Header: header, Parse: parseCorrelationID}
+}
+
+func parseCorrelationID(value string) (oteltrace.SpanContext, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return oteltrace.SpanContext{}, false
+	}
+
+	sum := sha256.Sum256([]byte(value))
+	cfg := oteltrace.SpanContextConfig{TraceFlags: oteltrace.FlagsSampled, Remote: true}
+	copy(cfg.TraceID[:], sum[:16])
+	copy(cfg.SpanID[:], sum[16:24])
+
+	if id, err := hex.DecodeString(strings.ReplaceAll(value, "-", "")); err == nil && len(id) == len(cfg.TraceID) {
+		copy(cfg.TraceID[:], id)
+	}
+
+	sc := oteltrace.NewSpanContext(cfg)
+	return sc, sc.IsValid()
+}
+
+type legacyLinksKey struct{}
+
+// legacyPropagator runs the legacy extractors after the standard propagators,
+// it doesn't inject anything.
+type legacyPropagator struct {
+	extractors []LegacyExtractor
+}
+
+// Inject implements the propagation.TextMapPropagator interface.
+func (legacyPropagator) Inject(context.Context, propagation.TextMapCarrier) {}
+
+// Extract implements the propagation.TextMapPropagator interface.
+func (p legacyPropagator) Extract(ctx context.Context, carrier propagation.TextMapCarrier) context.Context {
+	var links []oteltrace.Link
+	for _, e := range p.extractors {
+		value := carrier.Get(e.Header)
+		if value == "" {
+			continue
+		}
+
+		sc, ok := e.Parse(value)
+		if !ok {
+			continue
+		}
+
+		switch {
+		case e.Link:
+			links = append(links, oteltrace.Link{SpanContext: sc})
+		case !oteltrace.SpanContextFromContext(ctx).IsValid():
+			ctx = oteltrace.ContextWithRemoteSpanContext(ctx, sc)
+		}
+	}
+
+	if len(links) > 0 {
+		ctx = context.WithValue(ctx, legacyLinksKey{}, links)
+	}
+
+	return ctx
+}
+
+// Fields implements the propagation.TextMapPropagator interface.
+func (p legacyPropagator) Fields() []string {
+	fields := make([]string, len(p.extractors))
+	for i, e := range p.extractors {
+		fields[i] = e.Header
+	}
+
+	return fields
+}
+
+// legacyLinkProcessor adds the links found by the legacy extractors
+// to the first span started from the extracted context.
+type legacyLinkProcessor struct{}
+
+// OnStart implements the trace.SpanProcessor interface.
+func (legacyLinkProcessor) OnStart(ctx context.Context, s trace.ReadWriteSpan) {
+	if s.Parent().IsValid() && !s.Parent().IsRemote() {
+		return
+	}
+
+	links, _ := ctx.Value(legacyLinksKey{}).([]oteltrace.Link)
+	for _, link := range links {
+		s.AddLink(link)
+	}
+}
+
+// OnEnd implements the trace.SpanProcessor interface.
+func (legacyLinkProcessor) OnEnd(trace.ReadOnlySpan) {}
+
+// Shutdown implements the trace.SpanProcessor interface.
+func (legacyLinkProcessor) Shutdown(context.Context) error {
+	return nil
+}
+
+// ForceFlush implements the trace.SpanProcessor interface.
+func (legacyLinkProcessor) ForceFlush(context.Context) error {
+	return nil
+}
@@ -0,0 +1,28 @@
+package otel
+
+import "os"
+
+// localDevEndpoint is the OTLP gRPC endpoint of a collector, Jaeger or Tempo
+// instance running on the developer machine.
+const localDevEndpoint = "localhost:4317"
+
+// NewLocalDevConfig returns the configuration of the GRPC output sending to
+// a local collector, Jaeger or Tempo instance on localhost:4317 without TLS,
+// the other fields are read from the environment like NewENVConfig.
+//
+// Spans are exported as soon as they end and also written to stderr in the pretty format, so they
+// show up in the local UI and the console without waiting for a batch.
+// It's meant for development, synchronous exports slow down the application.
+func NewLocalDevConfig() *Config {
+	c := NewENVConfig()
+	c.URL = localDevEndpoint
+	if c.ServiceName == "" {
+		c.ServiceName = "local-dev"
+	}
+	c.Insecure = true
+	c.SyncExport = true
+	c.ConsoleWriter = os.Stderr
+	c.Format = FormatPretty
+
+	return c
+}
@@ -0,0 +1,68 @@
+package otel
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"google.golang.org/grpc"
+)
+
+type traceCollector struct {
+	coltracepb.UnimplementedTraceServiceServer
+	requests chan *coltracepb.ExportTraceServiceRequest
+}
+
+func (c *traceCollector) Export(_ context.Context, req *coltracepb.ExportTraceServiceRequest) (*coltracepb.ExportTraceServiceResponse, error) {
+	c.requests <- req
+	return &coltracepb.ExportTraceServiceResponse{}, nil
+}
+
+// startTraceCollector serves an insecure OTLP trace endpoint, returning its address.
+func startTraceCollector(t *testing.T) (string, *traceCollector) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+
+	collector := &traceCollector{requests: make(chan *coltracepb.ExportTraceServiceRequest, 10)}
+	server := grpc.NewServer()
+	coltracepb.RegisterTraceServiceServer(server, collector)
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	return lis.Addr().String(), collector
+}
+
+func TestNewLocalDevConfig(t *testing.T) {
+	c := NewLocalDevConfig()
+	assert.Equal(t, "localhost:4317", c.URL)
+	assert.True(t, c.Insecure)
+	assert.True(t, c.SyncExport)
+	assert.NotNil(t, c.ConsoleWriter)
+}
+
+func TestGRPCOutput_InsecureSyncExportWithConsole(t *testing.T) {
+	addr, collector := startTraceCollector(t)
+	var console bytes.Buffer
+	c := NewLocalDevConfig()
+	c.URL = addr
+	c.ConsoleWriter = &console
+	exporter := NewExporter(GRPC, c, WithGlobalRegistration(false))
+
+	pipeline, err := exporter.ExportPipeline(context.TODO())
+	assert.Nil(t, err)
+	defer pipeline.Shutdown(context.TODO())
+
+	_, span := pipeline.Tracer("sample").Start(context.TODO(), "sample span")
+	span.End()
+
+	// exported when the span ends, without flushing the provider
+	req := <-collector.requests
+	assert.Equal(t, "sample span", req.ResourceSpans[0].ScopeSpans[0].Spans[0].Name)
+	assert.Contains(t, console.String(), "  sample span ")
+
+	snapshot, _ := Snapshot(exporter)
+	assert.Equal(t, []string{"debug", "counter", "simple", "console"}, snapshot.Processors)
+}
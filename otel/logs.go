@@ -0,0 +1,151 @@
+package otel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"time"
+
+	"go.opentelemetry.io/contrib/bridges/otelslog"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploggrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdoutlog"
+	"go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/log/global"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+)
+
+// logOutput is implemented by the outputs able to export logs.
+type logOutput interface {
+	newLoggerProvider(ctx context.Context) (*sdklog.LoggerProvider, error)
+}
+
+// ExportLogPipeline builds the logger provider of the pipeline built by e,
+// sending log records to the same output as spans: the Writer for IO and the
+// gRPC connection of the trace exporter for GRPC. With Config.DryRun records
+// are discarded.
+//
+// Like ExportPipeline it's built once and registered globally unless
+// WithGlobalRegistration disabled it, shut it down along with the
+// tracer provider. Application logs are forwarded with NewSlogBridge
+// or the otelzap bridge core.
+func ExportLogPipeline(ctx context.Context, e Exporter) (*sdklog.LoggerProvider, error) {
+	p, ok := pipelineOf(e)
+	output, logs := e.(logOutput)
+	if !ok || !logs {
+		return nil, errors.New("unsupported exporter")
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.loggerProvider != nil {
+		return p.loggerProvider, nil
+	}
+	if p.config.Disabled {
+		return p.disabledLoggerProvider(), nil
+	}
+
+	provider, err := output.newLoggerProvider(ctx)
+	if err != nil {
+		return nil, err
+	}
+	p.loggerProvider = provider
+
+	if p.globalRegistration {
+		global.SetLoggerProvider(provider)
+	}
+
+	return provider, nil
+}
+
+func (c *ioOutput) newLoggerProvider(ctx context.Context) (*sdklog.LoggerProvider, error) {
+	writer := c.writer()
+	if c.Config.DryRun {
+		writer = io.Discard
+	}
+
+	opts := []stdoutlog.Option{stdoutlog.WithWriter(writer)}
+	if c.Config.Deterministic {
+		opts = append(opts, stdoutlog.WithoutTimestamps())
+	}
+	exp, err := stdoutlog.New(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("could not create log exporter: %w", err)
+	}
+
+	resource := c.detectResource(ctx)
+	return sdklog.NewLoggerProvider(
+		sdklog.WithProcessor(sdklog.NewBatchProcessor(exp)),
+		sdklog.WithResource(resource),
+	), nil
+}
+
+func (g *grpcOutput) newLoggerProvider(ctx context.Context) (*sdklog.LoggerProvider, error) {
+	var exp sdklog.Exporter
+	var err error
+	if g.Config.DryRun {
+		exp, err = stdoutlog.New(stdoutlog.WithWriter(io.Discard))
+	} else {
+		exp, err = g.newOTLPLogExporter(ctx)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("creating OTLP log exporter: %w", err)
+	}
+
+	resource := g.detectResource(ctx)
+	return sdklog.NewLoggerProvider(
+		sdklog.WithProcessor(sdklog.NewBatchProcessor(exp)),
+		sdklog.WithResource(resource),
+	), nil
+}
+
+func (g *grpcOutput) newOTLPLogExporter(ctx context.Context) (sdklog.Exporter, error) {
+	conn, err := g.dial()
+	if err != nil {
+		return nil, err
+	}
+
+	exp, err := otlploggrpc.New(ctx,
+		otlploggrpc.WithGRPCConn(conn),
+		otlploggrpc.WithTimeout(30*time.Second),
+		otlploggrpc.WithHeaders(g.headers()),
+	)
+	if err != nil {
+		return nil, errors.Join(err, g.release())
+	}
+
+	var shared sdklog.Exporter = sharedConnLogExporter{Exporter: exp, release: g.release}
+	if g.Config.OnExportError != nil {
+		shared = observedLogExporter{Exporter: shared, onError: g.Config.OnExportError}
+	}
+
+	return shared, nil
+}
+
+// sharedConnLogExporter releases the shared connection of its output once shut down.
+type sharedConnLogExporter struct {
+	sdklog.Exporter
+	release func() error
+}
+
+// Shutdown implements the sdklog.Exporter interface.
+func (e sharedConnLogExporter) Shutdown(ctx context.Context) error {
+	return errors.Join(e.Exporter.Shutdown(ctx), e.release())
+}
+
+// NewSlogBridge returns a slog.Handler forwarding records as log signals
+// through provider, the global one registered by ExportLogPipeline when nil.
+// Records logged with a context carry the trace and span of its span:
+//
+//	logger := slog.New(otel.NewSlogBridge(nil))
+//	logger.InfoContext(ctx, "order shipped")
+func NewSlogBridge(provider log.LoggerProvider) slog.Handler {
+	var opts []otelslog.Option
+	if provider != nil {
+		opts = append(opts, otelslog.WithLoggerProvider(provider))
+	}
+
+	return otelslog.NewHandler(instrumentationName, opts...)
+}
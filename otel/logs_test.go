@@ -0,0 +1,58 @@
+package otel
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/connectivity"
+)
+
+func TestExportLogPipeline_ForwardsSlogRecords(t *testing.T) {
+	var out bytes.Buffer
+	c := &Config{ServiceName: "orders", Writer: &out}
+	exporter := NewExporter(IO, c, WithGlobalRegistration(false))
+
+	tp, err := exporter.ExportPipeline(context.TODO())
+	assert.Nil(t, err)
+	defer tp.Shutdown(context.TODO())
+
+	provider, err := ExportLogPipeline(context.TODO(), exporter)
+	assert.Nil(t, err)
+	defer provider.Shutdown(context.TODO())
+
+	again, err := ExportLogPipeline(context.TODO(), exporter)
+	assert.Nil(t, err)
+	assert.Same(t, provider, again)
+
+	ctx, span := tp.Tracer("sample").Start(context.TODO(), "sample span")
+	slog.New(NewSlogBridge(provider)).InfoContext(ctx, "order shipped", "lines", 3)
+	span.End()
+	assert.Nil(t, provider.ForceFlush(context.TODO()))
+
+	assert.Contains(t, out.String(), "order shipped")
+	assert.Contains(t, out.String(), span.SpanContext().TraceID().String())
+	assert.Contains(t, out.String(), `"Value":"orders"`)
+}
+
+func TestExportLogPipeline_UnsupportedExporter(t *testing.T) {
+	_, err := ExportLogPipeline(context.TODO(), nil)
+	assert.EqualError(t, err, "unsupported exporter")
+}
+
+func TestGRPCOutput_SharesConnection(t *testing.T) {
+	g := NewExporter(GRPC, &Config{URL: "localhost:4317"}).(*grpcOutput)
+
+	traces, err := g.dial()
+	assert.Nil(t, err)
+	logs, err := g.dial()
+	assert.Nil(t, err)
+	assert.Same(t, traces, logs)
+
+	assert.Nil(t, g.release())
+	assert.NotEqual(t, connectivity.Shutdown, traces.GetState())
+	assert.Nil(t, g.release())
+	assert.Equal(t, connectivity.Shutdown, traces.GetState())
+}
@@ -0,0 +1,170 @@
+package otel
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/go-logr/logr"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	logEventName     = "log"
+	logMessageKey    = attribute.Key("log.message")
+	logLevelKey      = attribute.Key("log.level")
+	logLoggerNameKey = attribute.Key("log.logger")
+)
+
+// maxLogLineLength bounds the bytes buffered by a spanEventWriter for a line,
+// longer lines are split into several events.
+const maxLogLineLength = 64 << 10
+
+type spanEventWriter struct {
+	mu   sync.Mutex
+	span trace.Span
+	buf  []byte
+}
+
+// NewSpanEventWriter returns an io.WriteCloser turning every written line
+// into an event on the span found in ctx, so the output of libraries only
+// accepting a writer (e.g. through log.New) lands in the trace.
+//
+// Lines longer than 64KiB are split into several events. Close, or Sync for
+// loggers syncing their writer, records the last line when it isn't
+// terminated by a newline. Lines are dropped when the span is not recording.
+func NewSpanEventWriter(ctx context.Context) io.WriteCloser {
+	return &spanEventWriter{span: trace.SpanFromContext(ctx)}
+}
+
+// Write implements the io.Writer interface.
+func (w *spanEventWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			break
+		}
+
+		w.addEvent(w.buf[:i])
+		w.buf = w.buf[i+1:]
+	}
+	for len(w.buf) > maxLogLineLength {
+		w.addEvent(w.buf[:maxLogLineLength])
+		w.buf = w.buf[maxLogLineLength:]
+	}
+
+	return len(p), nil
+}
+
+// Sync records the buffered partial line.
+func (w *spanEventWriter) Sync() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.addEvent(w.buf)
+	w.buf = nil
+
+	return nil
+}
+
+// Close implements the io.Closer interface, see Sync.
+func (w *spanEventWriter) Close() error {
+	return w.Sync()
+}
+
+func (w *spanEventWriter) addEvent(line []byte) {
+	if line = bytes.TrimSpace(line); len(line) > 0 && w.span.IsRecording() {
+		w.span.AddEvent(logEventName, trace.WithAttributes(logMessageKey.String(string(line))))
+	}
+}
+
+type spanEventSink struct {
+	span   trace.Span
+	name   string
+	values []attribute.KeyValue
+}
+
+// NewSpanEventLogger returns a logr.Logger recording log lines as events
+// on the span found in ctx, errors are recorded as span exceptions.
+func NewSpanEventLogger(ctx context.Context) logr.Logger {
+	return logr.New(&spanEventSink{span: trace.SpanFromContext(ctx)})
+}
+
+// Init implements the logr.LogSink interface.
+func (s *spanEventSink) Init(logr.RuntimeInfo) {}
+
+// Enabled implements the logr.LogSink interface.
+func (s *spanEventSink) Enabled(int) bool {
+	return s.span.IsRecording()
+}
+
+// Info implements the logr.LogSink interface.
+func (s *spanEventSink) Info(level int, msg string, keysAndValues ...interface{}) {
+	attrs := s.attributes(msg, keysAndValues)
+	attrs = append(attrs, logLevelKey.Int(level))
+	s.span.AddEvent(logEventName, trace.WithAttributes(attrs...))
+}
+
+// Error implements the logr.LogSink interface.
+func (s *spanEventSink) Error(err error, msg string, keysAndValues ...interface{}) {
+	if err == nil {
+		err = fmt.Errorf("%s", msg)
+	}
+	s.span.RecordError(err, trace.WithAttributes(s.attributes(msg, keysAndValues)...))
+}
+
+// WithValues implements the logr.LogSink interface.
+func (s *spanEventSink) WithValues(keysAndValues ...interface{}) logr.LogSink {
+	sink := *s
+	sink.values = append(append([]attribute.KeyValue{}, s.values...), keyValues(keysAndValues)...)
+	return &sink
+}
+
+// WithName implements the logr.LogSink interface.
+func (s *spanEventSink) WithName(name string) logr.LogSink {
+	sink := *s
+	if sink.name != "" {
+		name = sink.name + "/" + name
+	}
+	sink.name = name
+	return &sink
+}
+
+func (s *spanEventSink) attributes(msg string, keysAndValues []interface{}) []attribute.KeyValue {
+	attrs := append([]attribute.KeyValue{logMessageKey.String(msg)}, s.values...)
+	if s.name != "" {
+		attrs = append(attrs, logLoggerNameKey.String(s.name))
+	}
+
+	return append(attrs, keyValues(keysAndValues)...)
+}
+
+func keyValues(keysAndValues []interface{}) []attribute.KeyValue {
+	attrs := make([]attribute.KeyValue, 0, len(keysAndValues)/2)
+	for i := 0; i+1 < len(keysAndValues); i += 2 {
+		key := attribute.Key(fmt.Sprint(keysAndValues[i]))
+		switch v := keysAndValues[i+1].(type) {
+		case string:
+			attrs = append(attrs, key.String(v))
+		case bool:
+			attrs = append(attrs, key.Bool(v))
+		case int:
+			attrs = append(attrs, key.Int(v))
+		case int64:
+			attrs = append(attrs, key.Int64(v))
+		case float64:
+			attrs = append(attrs, key.Float64(v))
+		default:
+			attrs = append(attrs, key.String(fmt.Sprint(v)))
+		}
+	}
+
+	return attrs
+}
@@ -0,0 +1,65 @@
+package otel
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestLogShim_WriterAddsSpanEvents(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := trace.NewTracerProvider(trace.WithSpanProcessor(recorder))
+
+	ctx, span := tp.Tracer("sample").Start(context.TODO(), "sample span")
+	logger := log.New(NewSpanEventWriter(ctx), "", 0)
+	logger.Println("first line")
+	logger.Print("second line\nthird line")
+	span.End()
+
+	events := recorder.Ended()[0].Events()
+	assert.Len(t, events, 3)
+	assert.Equal(t, logMessageKey.String("second line"), events[1].Attributes[0])
+}
+
+func TestLogShim_WriterFlushesPartialLines(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := trace.NewTracerProvider(trace.WithSpanProcessor(recorder))
+
+	ctx, span := tp.Tracer("sample").Start(context.TODO(), "sample span")
+	w := NewSpanEventWriter(ctx)
+	w.Write([]byte(strings.Repeat("a", maxLogLineLength+10)))
+	w.Write([]byte("last line"))
+	assert.Nil(t, w.Close())
+	span.End()
+
+	events := recorder.Ended()[0].Events()
+	assert.Len(t, events, 2)
+	assert.Equal(t, logMessageKey.String(strings.Repeat("a", maxLogLineLength)), events[0].Attributes[0])
+	assert.Equal(t, logMessageKey.String("aaaaaaaaaalast line"), events[1].Attributes[0])
+}
+
+func TestLogShim_LogrAddsSpanEvents(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := trace.NewTracerProvider(trace.WithSpanProcessor(recorder))
+
+	ctx, span := tp.Tracer("sample").Start(context.TODO(), "sample span")
+	logger := NewSpanEventLogger(ctx).WithName("client").WithValues("attempt", 2)
+	logger.Info("retrying", "backoff", "2s")
+	logger.Error(errors.New("connection refused"), "request failed")
+	span.End()
+
+	events := recorder.Ended()[0].Events()
+	assert.Len(t, events, 2)
+	assert.Equal(t, logEventName, events[0].Name)
+	assert.Contains(t, events[0].Attributes, attribute.Int("attempt", 2))
+	assert.Contains(t, events[0].Attributes, attribute.String("backoff", "2s"))
+	assert.Contains(t, events[0].Attributes, logLoggerNameKey.String("client"))
+	assert.Equal(t, "exception", events[1].Name)
+}
@@ -0,0 +1,120 @@
+package otel
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/trace"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+// TimeoutForcedEndKey flags the spans ended by the MaxDurationProcessor.
+const TimeoutForcedEndKey = attribute.Key("timeout_forced_end")
+
+// minMaxDurationInterval bounds how often the MaxDurationProcessor checks spans.
+const minMaxDurationInterval = 10 * time.Millisecond
+
+// MaxDurationProcessor is a span processor ending the spans still running
+// after a maximum duration, e.g. one hour, with the TimeoutForcedEndKey
+// attribute set. Leaked spans, never ended because of a missing span.End,
+// are then exported and visible as anomalies instead of being held forever.
+//
+// Spans are checked every quarter of the maximum duration, so they're ended
+// up to 25% late. Ending them later on has no effect.
+// Set Config.MaxSpanDuration or register it on the provider returned by
+// ExportPipeline with RegisterSpanProcessor.
+type MaxDurationProcessor struct {
+	max time.Duration
+
+	mu    sync.Mutex
+	spans map[oteltrace.SpanID]trace.ReadWriteSpan
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+var _ trace.SpanProcessor = (*MaxDurationProcessor)(nil)
+
+// NewMaxDurationProcessor creates a processor ending the spans running for
+// longer than max, the checks stop once the processor is shut down.
+func NewMaxDurationProcessor(max time.Duration) *MaxDurationProcessor {
+	p := &MaxDurationProcessor{
+		max:   max,
+		spans: make(map[oteltrace.SpanID]trace.ReadWriteSpan),
+		stop:  make(chan struct{}),
+	}
+
+	interval := max / 4
+	if interval < minMaxDurationInterval {
+		interval = minMaxDurationInterval
+	}
+	go p.run(interval)
+
+	return p
+}
+
+func (p *MaxDurationProcessor) run(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.stop:
+			return
+		case now := <-ticker.C:
+			p.check(now)
+		}
+	}
+}
+
+// check ends the spans started more than max before now.
+func (p *MaxDurationProcessor) check(now time.Time) {
+	var expired []trace.ReadWriteSpan
+
+	p.mu.Lock()
+	for id, s := range p.spans {
+		if now.Sub(s.StartTime()) > p.max {
+			expired = append(expired, s)
+			delete(p.spans, id)
+		}
+	}
+	p.mu.Unlock()
+
+	// spans are ended outside the lock since OnEnd acquires it
+	for _, s := range expired {
+		s.SetAttributes(TimeoutForcedEndKey.Bool(true))
+		s.End(oteltrace.WithTimestamp(now))
+	}
+}
+
+// OnStart implements the trace.SpanProcessor interface.
+func (p *MaxDurationProcessor) OnStart(_ context.Context, s trace.ReadWriteSpan) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.spans[s.SpanContext().SpanID()] = s
+}
+
+// OnEnd implements the trace.SpanProcessor interface.
+func (p *MaxDurationProcessor) OnEnd(s trace.ReadOnlySpan) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	delete(p.spans, s.SpanContext().SpanID())
+}
+
+// Shutdown implements the trace.SpanProcessor interface.
+func (p *MaxDurationProcessor) Shutdown(context.Context) error {
+	p.stopOnce.Do(func() {
+		close(p.stop)
+	})
+
+	return nil
+}
+
+// ForceFlush implements the trace.SpanProcessor interface.
+func (p *MaxDurationProcessor) ForceFlush(context.Context) error {
+	return nil
+}
@@ -0,0 +1,38 @@
+package otel
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestMaxDurationProcessor_EndsLeakedSpans(t *testing.T) {
+	processor := NewMaxDurationProcessor(time.Hour)
+	recorder := tracetest.NewSpanRecorder()
+	tp := trace.NewTracerProvider(trace.WithSpanProcessor(processor), trace.WithSpanProcessor(recorder))
+	defer tp.Shutdown(context.TODO())
+	tracer := tp.Tracer("sample")
+
+	_, leaked := tracer.Start(context.TODO(), "leaked")
+	_, ended := tracer.Start(context.TODO(), "ended")
+	ended.End()
+
+	processor.check(time.Now().Add(30 * time.Minute))
+	assert.Len(t, recorder.Ended(), 1)
+
+	now := time.Now().Add(2 * time.Hour)
+	processor.check(now)
+	spans := recorder.Ended()
+	assert.Len(t, spans, 2)
+	assert.Equal(t, "leaked", spans[1].Name())
+	assert.Equal(t, now, spans[1].EndTime())
+	assert.Contains(t, spans[1].Attributes(), TimeoutForcedEndKey.Bool(true))
+	assert.NotContains(t, spans[0].Attributes(), TimeoutForcedEndKey.Bool(true))
+
+	leaked.End()
+	assert.Len(t, recorder.Ended(), 2)
+}
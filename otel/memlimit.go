@@ -0,0 +1,120 @@
+package otel
+
+import (
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/trace"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+// memorySoftLimit is the ratio of Config.MemoryLimit above which new
+// traces aren't sampled anymore.
+const memorySoftLimit = 0.8
+
+// spanOverhead is the estimated size of a span besides its strings and
+// attributes: IDs, timestamps, status and the SDK bookkeeping.
+const spanOverhead = 256
+
+// memoryLimiter tracks the estimated size of the spans waiting for export.
+type memoryLimiter struct {
+	limit    int64
+	buffered atomic.Int64
+}
+
+func newMemoryLimiter(c *Config) *memoryLimiter {
+	if c.MemoryLimit <= 0 {
+		return nil
+	}
+
+	return &memoryLimiter{limit: c.MemoryLimit}
+}
+
+// soft reports whether the buffered spans are over the soft limit.
+func (m *memoryLimiter) soft() bool {
+	return m != nil && float64(m.buffered.Load()) >= memorySoftLimit*float64(m.limit)
+}
+
+// reserve accounts for s unless it would exceed the limit.
+func (m *memoryLimiter) reserve(s trace.ReadOnlySpan) bool {
+	if m == nil {
+		return true
+	}
+	size := spanSize(s)
+	if m.buffered.Add(size) > m.limit {
+		m.buffered.Add(-size)
+		return false
+	}
+
+	return true
+}
+
+func (m *memoryLimiter) release(spans ...trace.ReadOnlySpan) {
+	if m == nil {
+		return
+	}
+	var size int64
+	for _, s := range spans {
+		size += spanSize(s)
+	}
+	m.buffered.Add(-size)
+}
+
+// memoryLimitSampler drops the new traces while the buffered spans are
+// over the soft limit, the spans of the sampled traces are still sampled
+// so they stay complete.
+type memoryLimitSampler struct {
+	next    trace.Sampler
+	limiter *memoryLimiter
+}
+
+// ShouldSample implements the trace.Sampler interface.
+func (s memoryLimitSampler) ShouldSample(p trace.SamplingParameters) trace.SamplingResult {
+	if s.limiter.soft() && !oteltrace.SpanContextFromContext(p.ParentContext).IsSampled() {
+		return trace.NeverSample().ShouldSample(p)
+	}
+
+	return s.next.ShouldSample(p)
+}
+
+// Description implements the trace.Sampler interface.
+func (s memoryLimitSampler) Description() string {
+	return s.next.Description()
+}
+
+// spanSize estimates the memory held by s.
+func spanSize(s trace.ReadOnlySpan) int64 {
+	size := int64(spanOverhead + len(s.Name()) + len(s.Status().Description))
+	size += attributesSize(s.Attributes())
+	for _, e := range s.Events() {
+		size += int64(64+len(e.Name)) + attributesSize(e.Attributes)
+	}
+	for _, l := range s.Links() {
+		size += 64 + attributesSize(l.Attributes)
+	}
+
+	return size
+}
+
+func attributesSize(attrs []attribute.KeyValue) int64 {
+	var size int64
+	for _, kv := range attrs {
+		size += int64(32 + len(kv.Key))
+		switch kv.Value.Type() {
+		case attribute.STRING:
+			size += int64(len(kv.Value.AsString()))
+		case attribute.STRINGSLICE:
+			for _, v := range kv.Value.AsStringSlice() {
+				size += int64(16 + len(v))
+			}
+		case attribute.BOOLSLICE:
+			size += int64(len(kv.Value.AsBoolSlice()))
+		case attribute.INT64SLICE:
+			size += int64(8 * len(kv.Value.AsInt64Slice()))
+		case attribute.FLOAT64SLICE:
+			size += int64(8 * len(kv.Value.AsFloat64Slice()))
+		}
+	}
+
+	return size
+}
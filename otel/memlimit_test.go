@@ -0,0 +1,69 @@
+package otel
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+func TestMemoryLimiter_ShedsLoadOverBudget(t *testing.T) {
+	span := sampledSpan("buffered", codes.Unset)
+	size := spanSize(span)
+	m := newMemoryLimiter(&Config{MemoryLimit: 4 * size})
+	sampler := memoryLimitSampler{next: trace.AlwaysSample(), limiter: m}
+	root := trace.SamplingParameters{ParentContext: context.TODO(), TraceID: oteltrace.TraceID{1}}
+	child := trace.SamplingParameters{
+		ParentContext: oteltrace.ContextWithSpanContext(context.TODO(), span.SpanContext()),
+		TraceID:       oteltrace.TraceID{1},
+	}
+
+	for range 3 {
+		assert.True(t, m.reserve(span))
+	}
+	assert.False(t, m.soft())
+	assert.Equal(t, trace.RecordAndSample, sampler.ShouldSample(root).Decision)
+
+	assert.True(t, m.reserve(span))
+	assert.True(t, m.soft())
+	assert.Equal(t, trace.Drop, sampler.ShouldSample(root).Decision)
+	assert.Equal(t, trace.RecordAndSample, sampler.ShouldSample(child).Decision)
+	assert.False(t, m.reserve(span))
+
+	m.release(span, span)
+	assert.False(t, m.soft())
+	assert.Equal(t, 2*size, m.buffered.Load())
+}
+
+func TestSpanSize_CountsAttributesAndEvents(t *testing.T) {
+	span := tracetest.SpanStub{Name: "sample"}.Snapshot()
+	attributed := tracetest.SpanStub{
+		Name:       "sample",
+		Attributes: []attribute.KeyValue{attribute.String("db.statement", "SELECT 1")},
+		Events:     []trace.Event{{Name: "retry"}},
+	}.Snapshot()
+
+	assert.Equal(t, int64(spanOverhead+len("sample")), spanSize(span))
+	assert.Equal(t, spanSize(span)+32+12+8+64+5, spanSize(attributed))
+}
+
+func TestMemoryLimit_ReportsBufferedBytes(t *testing.T) {
+	exporter := NewExporter(IO, &Config{Writer: &toggleWriter{}, MemoryLimit: 1 << 20}, WithGlobalRegistration(false))
+	provider, err := exporter.ExportPipeline(context.TODO())
+	assert.Nil(t, err)
+	defer provider.Shutdown(context.TODO())
+
+	_, span := provider.Tracer("sample").Start(context.TODO(), "sample span")
+	span.End()
+	health, _ := Health(exporter)
+	assert.Positive(t, health.BufferedBytes)
+
+	assert.Nil(t, provider.ForceFlush(context.TODO()))
+	health, _ = Health(exporter)
+	assert.Zero(t, health.BufferedBytes)
+}
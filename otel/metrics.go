@@ -0,0 +1,184 @@
+package otel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"go.opentelemetry.io/contrib/instrumentation/runtime"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdoutmetric"
+	"go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+// metricOutput is implemented by the outputs able to export metrics.
+type metricOutput interface {
+	newMeterProvider(ctx context.Context) (*metric.MeterProvider, error)
+}
+
+// ExportMetricPipeline builds the meter provider of the pipeline built by e,
+// exporting every minute to the same output as spans: the Writer for IO and
+// the gRPC connection of the trace exporter for GRPC. With Config.DryRun
+// metrics are discarded. The Prometheus output serves them instead, see
+// PrometheusHandler.
+//
+// Like ExportPipeline it's built once and registered globally unless
+// WithGlobalRegistration disabled it, shut it down along with the
+// tracer provider. See WithRuntimeMetrics, WithHostMetrics and WithSpanMetrics.
+func ExportMetricPipeline(ctx context.Context, e Exporter) (*metric.MeterProvider, error) {
+	p, ok := pipelineOf(e)
+	output, metrics := e.(metricOutput)
+	if !ok || !metrics {
+		return nil, errors.New("unsupported exporter")
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.meterProvider != nil {
+		return p.meterProvider, nil
+	}
+	if p.config.Disabled {
+		return p.disabledMeterProvider(), nil
+	}
+	if _, err := p.config.temporalitySelector(); err != nil {
+		return nil, err
+	}
+	if _, err := p.config.aggregationSelector(); err != nil {
+		return nil, err
+	}
+
+	provider, err := output.newMeterProvider(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if p.runtimeMetrics {
+		if err := runtime.Start(runtime.WithMeterProvider(provider)); err != nil {
+			return nil, errors.Join(fmt.Errorf("could not start runtime metrics: %w", err), provider.Shutdown(ctx))
+		}
+	}
+	if p.hostMetrics {
+		if err := startHostMetrics(provider); err != nil {
+			return nil, errors.Join(fmt.Errorf("could not start host metrics: %w", err), provider.Shutdown(ctx))
+		}
+	}
+	if p.spanMetrics {
+		if err := p.red.register(provider); err != nil {
+			return nil, errors.Join(fmt.Errorf("could not register span metrics: %w", err), provider.Shutdown(ctx))
+		}
+	}
+	if p.health.alert != nil {
+		if err := p.health.alert.register(p.health, provider); err != nil {
+			return nil, errors.Join(fmt.Errorf("could not register drop rate metrics: %w", err), provider.Shutdown(ctx))
+		}
+	}
+	if _, otlp := e.(*grpcOutput); otlp {
+		if err := p.health.registerRejected(provider); err != nil {
+			return nil, errors.Join(fmt.Errorf("could not register rejected span metrics: %w", err), provider.Shutdown(ctx))
+		}
+	}
+	p.meterProvider = provider
+
+	if p.globalRegistration {
+		otel.SetMeterProvider(provider)
+	}
+
+	return provider, nil
+}
+
+// meterProviderOptions returns the provider options shared by all outputs.
+func (c *Config) meterProviderOptions(reader metric.Reader, res *resource.Resource) []metric.Option {
+	opts := []metric.Option{
+		metric.WithReader(reader),
+		metric.WithResource(res),
+		metric.WithView(c.MetricViews...),
+	}
+	if c.MetricCardinalityLimit != 0 {
+		opts = append(opts, metric.WithCardinalityLimit(c.MetricCardinalityLimit))
+	}
+
+	return opts
+}
+
+func (c *ioOutput) newMeterProvider(ctx context.Context) (*metric.MeterProvider, error) {
+	writer := c.writer()
+	if c.Config.DryRun {
+		writer = io.Discard
+	}
+
+	temporality, _ := c.Config.temporalitySelector()
+	aggregation, _ := c.Config.aggregationSelector()
+	opts := []stdoutmetric.Option{
+		stdoutmetric.WithWriter(writer),
+		stdoutmetric.WithTemporalitySelector(temporality),
+		stdoutmetric.WithAggregationSelector(aggregation),
+	}
+	if c.Config.Deterministic {
+		opts = append(opts, stdoutmetric.WithoutTimestamps())
+	}
+	exp, err := stdoutmetric.New(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("could not create metric exporter: %w", err)
+	}
+
+	resource := c.detectResource(ctx)
+	return metric.NewMeterProvider(c.Config.meterProviderOptions(metric.NewPeriodicReader(exp), resource)...), nil
+}
+
+func (g *grpcOutput) newMeterProvider(ctx context.Context) (*metric.MeterProvider, error) {
+	var exp metric.Exporter
+	var err error
+	if g.Config.DryRun {
+		exp, err = stdoutmetric.New(stdoutmetric.WithWriter(io.Discard))
+	} else {
+		exp, err = g.newOTLPMetricExporter(ctx)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("creating OTLP metric exporter: %w", err)
+	}
+
+	resource := g.detectResource(ctx)
+	return metric.NewMeterProvider(g.Config.meterProviderOptions(metric.NewPeriodicReader(exp), resource)...), nil
+}
+
+func (g *grpcOutput) newOTLPMetricExporter(ctx context.Context) (metric.Exporter, error) {
+	conn, err := g.dial()
+	if err != nil {
+		return nil, err
+	}
+
+	temporality, _ := g.Config.temporalitySelector()
+	aggregation, _ := g.Config.aggregationSelector()
+	exp, err := otlpmetricgrpc.New(ctx,
+		otlpmetricgrpc.WithGRPCConn(conn),
+		otlpmetricgrpc.WithTemporalitySelector(temporality),
+		otlpmetricgrpc.WithAggregationSelector(aggregation),
+		otlpmetricgrpc.WithTimeout(30*time.Second),
+		otlpmetricgrpc.WithHeaders(g.headers()),
+	)
+	if err != nil {
+		return nil, errors.Join(err, g.release())
+	}
+
+	var shared metric.Exporter = sharedConnMetricExporter{Exporter: exp, release: g.release}
+	if g.Config.OnExportError != nil {
+		shared = observedMetricExporter{Exporter: shared, onError: g.Config.OnExportError}
+	}
+
+	return shared, nil
+}
+
+// sharedConnMetricExporter releases the shared connection of its output once shut down.
+type sharedConnMetricExporter struct {
+	metric.Exporter
+	release func() error
+}
+
+// Shutdown implements the metric.Exporter interface.
+func (e sharedConnMetricExporter) Shutdown(ctx context.Context) error {
+	return errors.Join(e.Exporter.Shutdown(ctx), e.release())
+}
@@ -0,0 +1,85 @@
+package otel
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	otelmetric "go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/sdk/metric"
+)
+
+func TestExportMetricPipeline_RuntimeMetrics(t *testing.T) {
+	var out bytes.Buffer
+	c := &Config{ServiceName: "orders", Writer: &out}
+	exporter := NewExporter(IO, c, WithGlobalRegistration(false), WithRuntimeMetrics())
+
+	provider, err := ExportMetricPipeline(context.TODO(), exporter)
+	assert.Nil(t, err)
+	defer provider.Shutdown(context.TODO())
+
+	again, err := ExportMetricPipeline(context.TODO(), exporter)
+	assert.Nil(t, err)
+	assert.Same(t, provider, again)
+
+	assert.Nil(t, provider.ForceFlush(context.TODO()))
+	assert.Contains(t, out.String(), "go.goroutine.count")
+	assert.Contains(t, out.String(), "go.memory.used")
+}
+
+func TestExportMetricPipeline_WithoutRuntimeMetrics(t *testing.T) {
+	var out bytes.Buffer
+	exporter := NewExporter(IO, &Config{Writer: &out}, WithGlobalRegistration(false))
+
+	provider, err := ExportMetricPipeline(context.TODO(), exporter)
+	assert.Nil(t, err)
+	defer provider.Shutdown(context.TODO())
+
+	assert.Nil(t, provider.ForceFlush(context.TODO()))
+	assert.NotContains(t, out.String(), "go.goroutine.count")
+}
+
+func TestExportMetricPipeline_HostMetrics(t *testing.T) {
+	var out bytes.Buffer
+	exporter := NewExporter(IO, &Config{Writer: &out}, WithGlobalRegistration(false), WithHostMetrics())
+
+	provider, err := ExportMetricPipeline(context.TODO(), exporter)
+	assert.Nil(t, err)
+	defer provider.Shutdown(context.TODO())
+
+	assert.Nil(t, provider.ForceFlush(context.TODO()))
+	assert.Contains(t, out.String(), "system.cpu.time")
+	assert.Contains(t, out.String(), "system.memory.usage")
+	assert.Contains(t, out.String(), "system.network.io")
+	assert.Contains(t, out.String(), "system.disk.io")
+}
+
+func TestExportMetricPipeline_ViewsAndCardinalityLimit(t *testing.T) {
+	var out bytes.Buffer
+	c := &Config{
+		Writer: &out,
+		MetricViews: []metric.View{metric.NewView(
+			metric.Instrument{Name: "orders"},
+			metric.Stream{Name: "shop.orders", AttributeFilter: attribute.NewDenyKeysFilter("user.id")},
+		)},
+		MetricCardinalityLimit: 2,
+	}
+	exporter := NewExporter(IO, c, WithGlobalRegistration(false))
+
+	provider, err := ExportMetricPipeline(context.TODO(), exporter)
+	assert.Nil(t, err)
+	defer provider.Shutdown(context.TODO())
+
+	counter, err := provider.Meter("sample").Int64Counter("orders")
+	assert.Nil(t, err)
+	for _, country := range []string{"fr", "de", "it", "es"} {
+		counter.Add(context.TODO(), 1, otelmetric.WithAttributes(attribute.String("country", country), attribute.String("user.id", "42")))
+	}
+
+	assert.Nil(t, provider.ForceFlush(context.TODO()))
+	assert.Contains(t, out.String(), `"Name":"shop.orders"`)
+	assert.NotContains(t, out.String(), "user.id")
+	assert.Contains(t, out.String(), "otel.metric.overflow")
+}
@@ -0,0 +1,110 @@
+package otel
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+// NewRelicRegion is the region of a New Relic account, its data is only
+// accepted by the endpoint of that region.
+type NewRelicRegion string
+
+// Supported New Relic regions.
+const (
+	NewRelicUS NewRelicRegion = "US"
+	NewRelicEU NewRelicRegion = "EU"
+)
+
+// newRelicEndpoints are the OTLP gRPC endpoints of the New Relic regions.
+var newRelicEndpoints = map[NewRelicRegion]string{
+	NewRelicUS: "otlp.nr-data.net:4317",
+	NewRelicEU: "otlp.eu01.nr-data.net:4317",
+}
+
+// newRelicLicenseKeyLength is the length of the New Relic license keys, the
+// ones of the EU accounts starting with newRelicEUKeyPrefix.
+const (
+	newRelicLicenseKeyLength = 40
+	newRelicEUKeyPrefix      = "eu01xx"
+)
+
+// newRelicMaxExportBatchSize keeps export requests well under the 1MB
+// payload limit of the New Relic OTLP endpoint.
+const newRelicMaxExportBatchSize = 1000
+
+// NewNewRelicConfig returns the configuration of the GRPC output sending to
+// the New Relic account of apiKey in region, the other fields are read from
+// the environment like NewENVConfig. With an empty region, the region is
+// detected from the license key, and a license key of another region than
+// region is an error since its data would be rejected.
+//
+// The license key is sent in the api-key header and requests are gzip
+// compressed like with any GRPC output. Metrics use the delta temporality
+// New Relic prefers and export requests are kept under its payload limit,
+// unless OTEL_EXPORTER_OTLP_METRICS_TEMPORALITY_PREFERENCE and
+// OTEL_BSP_MAX_EXPORT_BATCH_SIZE set them.
+func NewNewRelicConfig(apiKey string, region NewRelicRegion) (*Config, error) {
+	if region == "" {
+		region, _ = newRelicRegionOf(apiKey)
+	}
+	endpoint, ok := newRelicEndpoints[region]
+	if !ok {
+		return nil, fmt.Errorf("unsupported New Relic region %q", region)
+	}
+
+	if err := checkNewRelicRegion(apiKey, endpoint); err != nil {
+		return nil, err
+	}
+
+	c := NewENVConfig()
+	c.APIKey = apiKey
+	c.URL = endpoint
+	// the settings from the environment take precedence over the defaults
+	if c.MetricTemporality == "" {
+		c.MetricTemporality = "delta"
+	}
+	if c.MaxExportBatchSize == 0 {
+		c.MaxExportBatchSize = intEnv("OTEL_BSP_MAX_EXPORT_BATCH_SIZE")
+	}
+	if c.MaxExportBatchSize <= 0 {
+		c.MaxExportBatchSize = newRelicMaxExportBatchSize
+	}
+
+	return c, nil
+}
+
+// newRelicRegionOf returns the region of a New Relic license key, ok is
+// false for the keys not formatted like one.
+func newRelicRegionOf(apiKey string) (region NewRelicRegion, ok bool) {
+	switch {
+	case len(apiKey) != newRelicLicenseKeyLength:
+		return "", false
+	case strings.HasPrefix(apiKey, newRelicEUKeyPrefix):
+		return NewRelicEU, true
+	}
+
+	return NewRelicUS, true
+}
+
+// checkNewRelicRegion returns an error when endpoint is the one of a New
+// Relic region but apiKey is a license key of another region.
+func checkNewRelicRegion(apiKey, endpoint string) error {
+	keyRegion, ok := newRelicRegionOf(apiKey)
+	if !ok {
+		return nil
+	}
+
+	host := strings.TrimPrefix(strings.TrimPrefix(endpoint, "https://"), "http://")
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	for region, regionEndpoint := range newRelicEndpoints {
+		if region != keyRegion && strings.HasPrefix(regionEndpoint, host+":") {
+			return fmt.Errorf("the New Relic license key belongs to the %s region but %s is the %s endpoint, use %s",
+				keyRegion, endpoint, region, newRelicEndpoints[keyRegion])
+		}
+	}
+
+	return nil
+}
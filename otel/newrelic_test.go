@@ -0,0 +1,62 @@
+package otel
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewNewRelicConfig_SelectsRegionEndpoint(t *testing.T) {
+	c, err := NewNewRelicConfig("license", NewRelicEU)
+	assert.Nil(t, err)
+	assert.Equal(t, "otlp.eu01.nr-data.net:4317", c.URL)
+	assert.Equal(t, "license", c.APIKey)
+	assert.Equal(t, "delta", c.MetricTemporality)
+	assert.Equal(t, 1000, c.maxExportBatchSize(100000))
+
+	c, err = NewNewRelicConfig("license", NewRelicUS)
+	assert.Nil(t, err)
+	assert.Equal(t, "otlp.nr-data.net:4317", c.URL)
+
+	_, err = NewNewRelicConfig("license", "APAC")
+	assert.EqualError(t, err, `unsupported New Relic region "APAC"`)
+}
+
+func TestNewNewRelicConfig_KeepsEnvSettings(t *testing.T) {
+	t.Setenv("OTEL_EXPORTER_OTLP_METRICS_TEMPORALITY_PREFERENCE", "cumulative")
+	t.Setenv("OTEL_BSP_MAX_EXPORT_BATCH_SIZE", "200")
+
+	c, err := NewNewRelicConfig("license", NewRelicUS)
+	assert.Nil(t, err)
+	assert.Equal(t, "cumulative", c.MetricTemporality)
+	assert.Equal(t, 200, c.maxExportBatchSize(100000))
+}
+
+func TestNewNewRelicConfig_ValidatesLicenseKeyRegion(t *testing.T) {
+	euKey := "eu01xx" + strings.Repeat("a", 30) + "NRAL"
+	usKey := strings.Repeat("a", 36) + "NRAL"
+
+	c, err := NewNewRelicConfig(euKey, "")
+	assert.Nil(t, err)
+	assert.Equal(t, "otlp.eu01.nr-data.net:4317", c.URL)
+	c, err = NewNewRelicConfig(usKey, "")
+	assert.Nil(t, err)
+	assert.Equal(t, "otlp.nr-data.net:4317", c.URL)
+
+	_, err = NewNewRelicConfig(euKey, NewRelicUS)
+	assert.EqualError(t, err, "the New Relic license key belongs to the EU region but otlp.nr-data.net:4317 is the US endpoint, use otlp.eu01.nr-data.net:4317")
+	_, err = NewNewRelicConfig("license", "")
+	assert.EqualError(t, err, `unsupported New Relic region ""`)
+}
+
+func TestGRPCOutput_RejectsNewRelicRegionMismatch(t *testing.T) {
+	exporter := NewExporter(GRPC, &Config{
+		URL:    "https://otlp.eu01.nr-data.net:443",
+		APIKey: strings.Repeat("a", 36) + "NRAL",
+	}, WithGlobalRegistration(false))
+
+	_, err := exporter.ExportPipeline(context.TODO())
+	assert.ErrorContains(t, err, "the New Relic license key belongs to the US region but https://otlp.eu01.nr-data.net:443 is the EU endpoint")
+}
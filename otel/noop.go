@@ -0,0 +1,22 @@
+package otel
+
+import (
+	"io"
+
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// NewNoopExporter returns an Exporter for the unit tests of code depending on
+// this package, needing neither env vars nor network: its pipelines are built
+// from an empty Config, aren't registered globally and discard every span,
+// metric and log. Use the Test output to assert on the spans instead.
+func NewNoopExporter(opts ...Option) Exporter {
+	c := &Config{}
+
+	return &ioOutput{
+		Config:        c,
+		pipeline:      newPipeline(c, append(opts, WithGlobalRegistration(false))),
+		spanExporter:  tracetest.NewNoopExporter(),
+		defaultWriter: io.Discard,
+	}
+}
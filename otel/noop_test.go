@@ -0,0 +1,30 @@
+package otel
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+)
+
+func TestNoopExporter_BuildsIsolatedPipelines(t *testing.T) {
+	global := otel.GetTracerProvider()
+
+	exporter := NewNoopExporter(WithGlobalRegistration(true))
+	provider, err := exporter.ExportPipeline(context.TODO())
+	assert.Nil(t, err)
+	_, span := provider.Tracer("sample").Start(context.TODO(), "sample span")
+	assert.True(t, span.SpanContext().IsSampled())
+	span.End()
+
+	meterProvider, err := ExportMetricPipeline(context.TODO(), exporter)
+	assert.Nil(t, err)
+	loggerProvider, err := ExportLogPipeline(context.TODO(), exporter)
+	assert.Nil(t, err)
+
+	assert.Equal(t, global, otel.GetTracerProvider())
+	assert.Nil(t, provider.Shutdown(context.TODO()))
+	assert.Nil(t, meterProvider.Shutdown(context.TODO()))
+	assert.Nil(t, loggerProvider.Shutdown(context.TODO()))
+}
@@ -0,0 +1,81 @@
+package otel
+
+import (
+	"context"
+
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	"go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+)
+
+// watchConn calls Config.OnConnect when conn becomes ready and
+// Config.OnDisconnect when it leaves the ready state, failing, going idle
+// or closing, until conn is closed.
+func (c *Config) watchConn(conn *grpc.ClientConn) {
+	connected := false
+	for {
+		state := conn.GetState()
+		switch ready := state == connectivity.Ready; {
+		case ready && !connected && c.OnConnect != nil:
+			c.OnConnect()
+		case !ready && connected && c.OnDisconnect != nil:
+			c.OnDisconnect()
+		}
+		connected = state == connectivity.Ready
+		if state == connectivity.Shutdown {
+			return
+		}
+		conn.WaitForStateChange(context.Background(), state)
+	}
+}
+
+// observedSpanExporter reports the failed exports to Config.OnExportError.
+type observedSpanExporter struct {
+	trace.SpanExporter
+	onError func(error)
+}
+
+// ExportSpans implements the trace.SpanExporter interface.
+func (e observedSpanExporter) ExportSpans(ctx context.Context, spans []trace.ReadOnlySpan) error {
+	err := e.SpanExporter.ExportSpans(ctx, spans)
+	if err != nil {
+		e.onError(err)
+	}
+
+	return err
+}
+
+// observedMetricExporter reports the failed exports to Config.OnExportError.
+type observedMetricExporter struct {
+	metric.Exporter
+	onError func(error)
+}
+
+// Export implements the metric.Exporter interface.
+func (e observedMetricExporter) Export(ctx context.Context, rm *metricdata.ResourceMetrics) error {
+	err := e.Exporter.Export(ctx, rm)
+	if err != nil {
+		e.onError(err)
+	}
+
+	return err
+}
+
+// observedLogExporter reports the failed exports to Config.OnExportError.
+type observedLogExporter struct {
+	sdklog.Exporter
+	onError func(error)
+}
+
+// Export implements the sdklog.Exporter interface.
+func (e observedLogExporter) Export(ctx context.Context, records []sdklog.Record) error {
+	err := e.Exporter.Export(ctx, records)
+	if err != nil {
+		e.onError(err)
+	}
+
+	return err
+}
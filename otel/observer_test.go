@@ -0,0 +1,70 @@
+package otel
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// unavailableCollector rejects every export.
+type unavailableCollector struct {
+	coltracepb.UnimplementedTraceServiceServer
+}
+
+func (unavailableCollector) Export(context.Context, *coltracepb.ExportTraceServiceRequest) (*coltracepb.ExportTraceServiceResponse, error) {
+	return nil, status.Error(codes.InvalidArgument, "rejected")
+}
+
+func receive(t *testing.T, events <-chan string) string {
+	select {
+	case event := <-events:
+		return event
+	case <-time.After(5 * time.Second):
+		t.Fatal("no connection event")
+		return ""
+	}
+}
+
+func TestConfig_ConnectionCallbacks(t *testing.T) {
+	defer otel.SetErrorHandler(otel.GetErrorHandler())
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	server := grpc.NewServer()
+	coltracepb.RegisterTraceServiceServer(server, unavailableCollector{})
+	go server.Serve(lis)
+	defer server.Stop()
+
+	events := make(chan string, 10)
+	exportErrors := make(chan error, 10)
+	exporter := NewExporter(GRPC, &Config{
+		URL:           lis.Addr().String(),
+		Insecure:      true,
+		SyncExport:    true,
+		ErrorHandler:  func(error) {},
+		OnConnect:     func() { events <- "connect" },
+		OnDisconnect:  func() { events <- "disconnect" },
+		OnExportError: func(err error) { exportErrors <- err },
+	}, WithGlobalRegistration(false))
+
+	pipeline, err := exporter.ExportPipeline(context.TODO())
+	assert.Nil(t, err)
+	defer pipeline.Shutdown(context.TODO())
+
+	_, span := pipeline.Tracer("sample").Start(context.TODO(), "sample span")
+	span.End()
+
+	assert.Equal(t, "connect", receive(t, events))
+	assert.ErrorContains(t, <-exportErrors, "rejected")
+
+	server.Stop()
+	assert.Equal(t, "disconnect", receive(t, events))
+}
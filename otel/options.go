@@ -0,0 +1,99 @@
+package otel
+
+import (
+	"io"
+	"os"
+)
+
+// Option configures the pipeline built by an Exporter.
+type Option func(*options)
+
+type options struct {
+	globalRegistration bool
+	runtimeMetrics     bool
+	hostMetrics        bool
+	spanMetrics        bool
+	fallback           io.Writer
+	zpages             bool
+	traceViewer        bool
+}
+
+func newOptions(opts []Option) options {
+	o := options{
+		globalRegistration: true,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return o
+}
+
+// WithGlobalRegistration controls whether ExportPipeline registers the
+// provider, the propagator and Config.ErrorHandler as the otel global ones,
+// enabled by default.
+//
+// Disable it when the application manages the otel globals itself
+// and only needs the returned provider.
+func WithGlobalRegistration(enabled bool) Option {
+	return func(o *options) {
+		o.globalRegistration = enabled
+	}
+}
+
+// WithRuntimeMetrics starts the Go runtime instrumentation, heap, GC pauses
+// and goroutine count among others, on the provider built by ExportMetricPipeline.
+func WithRuntimeMetrics() Option {
+	return func(o *options) {
+		o.runtimeMetrics = true
+	}
+}
+
+// WithHostMetrics records the CPU, memory, disk and network usage of the host
+// as system.* metrics on the provider built by ExportMetricPipeline,
+// meant for deployments without a node agent collecting them.
+func WithHostMetrics() Option {
+	return func(o *options) {
+		o.hostMetrics = true
+	}
+}
+
+// WithSpanMetrics derives request rate, error rate and duration metrics from
+// the server and consumer spans on the provider built by ExportMetricPipeline,
+// see REDProcessor. Spans not sampled are recorded to be measured, they're
+// still not exported.
+func WithSpanMetrics() Option {
+	return func(o *options) {
+		o.spanMetrics = true
+	}
+}
+
+// WithFallback writes the spans of the GRPC output to w, like the IO output
+// does, when its endpoint can't be reached at startup or after consecutive
+// failed exports, instead of dropping them. A warning is reported to the
+// ErrorHandler when falling back, spans go to the endpoint again once it's
+// reachable. A nil w is stdout.
+func WithFallback(w io.Writer) Option {
+	return func(o *options) {
+		if w == nil {
+			w = os.Stdout
+		}
+		o.fallback = w
+	}
+}
+
+// WithZPages collects the spans of the pipeline in memory for the tracez
+// page served by ZPagesHandler.
+func WithZPages() Option {
+	return func(o *options) {
+		o.zpages = true
+	}
+}
+
+// WithTraceViewer keeps the latest traces of the pipeline in memory for the
+// waterfall page served by TraceViewerHandler, meant for development.
+func WithTraceViewer() Option {
+	return func(o *options) {
+		o.traceViewer = true
+	}
+}
@@ -0,0 +1,39 @@
+package otel
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+)
+
+func TestOptions_WithoutGlobalRegistration(t *testing.T) {
+	setEnv()
+	defer unsetEnv()
+
+	global := otel.GetTracerProvider()
+	c := NewENVConfig()
+	c.Writer = io.Discard
+
+	pipeline, err := NewExporter(IO, c, WithGlobalRegistration(false)).ExportPipeline(context.TODO())
+	assert.Nil(t, err)
+	defer pipeline.Shutdown(context.TODO())
+
+	assert.Equal(t, global, otel.GetTracerProvider())
+}
+
+func TestOptions_GlobalRegistrationByDefault(t *testing.T) {
+	setEnv()
+	defer unsetEnv()
+
+	c := NewENVConfig()
+	c.Writer = io.Discard
+
+	pipeline, err := NewExporter(IO, c).ExportPipeline(context.TODO())
+	assert.Nil(t, err)
+	defer pipeline.Shutdown(context.TODO())
+
+	assert.Equal(t, pipeline, otel.GetTracerProvider())
+}
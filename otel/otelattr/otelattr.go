@@ -0,0 +1,111 @@
+// Package otelattr provides typed attributes shared by our services and a
+// builder enforcing the attribute key naming conventions.
+package otelattr
+
+import (
+	"fmt"
+	"regexp"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+)
+
+// Keys of the typed attributes.
+const (
+	UserIDKey    = semconv.EnduserIDKey
+	HTTPRouteKey = semconv.HTTPRouteKey
+	TenantKey    = attribute.Key("tenant.id")
+	RequestIDKey = attribute.Key("request.id")
+)
+
+// UserID returns the enduser.id attribute.
+func UserID(id string) attribute.KeyValue {
+	return UserIDKey.String(id)
+}
+
+// HTTPRoute returns the http.route attribute, the route template like
+// "/users/{id}" rather than the requested path.
+func HTTPRoute(route string) attribute.KeyValue {
+	return HTTPRouteKey.String(route)
+}
+
+// Tenant returns the tenant.id attribute.
+func Tenant(id string) attribute.KeyValue {
+	return TenantKey.String(id)
+}
+
+// RequestID returns the request.id attribute.
+func RequestID(id string) attribute.KeyValue {
+	return RequestIDKey.String(id)
+}
+
+// keyPattern matches lowercase dot separated namespaces of snake case
+// words, e.g. "order.line_count".
+var keyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]*(\.[a-z][a-z0-9_]*)*$`)
+
+// ValidKey reports whether key follows the naming conventions, lowercase
+// snake case words separated by dots like "order.line_count".
+func ValidKey(key attribute.Key) bool {
+	return keyPattern.MatchString(string(key))
+}
+
+// Builder accumulates attributes, attributes with an invalid key are dropped
+// and reported to the otel error handler:
+//
+//	attrs := otelattr.New().
+//		Add(otelattr.Tenant(tenant)).
+//		String("order.id", id).
+//		Int("order.line_count", len(lines)).
+//		Build()
+type Builder struct {
+	attrs []attribute.KeyValue
+}
+
+// New creates an empty builder.
+func New() *Builder {
+	return &Builder{}
+}
+
+// Add adds attrs to the builder.
+func (b *Builder) Add(attrs ...attribute.KeyValue) *Builder {
+	for _, attr := range attrs {
+		if !ValidKey(attr.Key) {
+			otel.Handle(fmt.Errorf("attribute key %q dropped: keys must be lowercase snake case words separated by dots", attr.Key))
+			continue
+		}
+		b.attrs = append(b.attrs, attr)
+	}
+
+	return b
+}
+
+// String adds a string attribute.
+func (b *Builder) String(key, value string) *Builder {
+	return b.Add(attribute.String(key, value))
+}
+
+// Int adds an int attribute.
+func (b *Builder) Int(key string, value int) *Builder {
+	return b.Add(attribute.Int(key, value))
+}
+
+// Int64 adds an int64 attribute.
+func (b *Builder) Int64(key string, value int64) *Builder {
+	return b.Add(attribute.Int64(key, value))
+}
+
+// Float64 adds a float64 attribute.
+func (b *Builder) Float64(key string, value float64) *Builder {
+	return b.Add(attribute.Float64(key, value))
+}
+
+// Bool adds a bool attribute.
+func (b *Builder) Bool(key string, value bool) *Builder {
+	return b.Add(attribute.Bool(key, value))
+}
+
+// Build returns the accumulated attributes, the builder can still be used.
+func (b *Builder) Build() []attribute.KeyValue {
+	return append([]attribute.KeyValue(nil), b.attrs...)
+}
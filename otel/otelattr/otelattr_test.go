@@ -0,0 +1,44 @@
+package otelattr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+func TestBuilder_DropsInvalidKeys(t *testing.T) {
+	defer otel.SetErrorHandler(otel.GetErrorHandler())
+
+	var reported []error
+	otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) {
+		reported = append(reported, err)
+	}))
+
+	attrs := New().
+		Add(Tenant("acme"), UserID("u-1"), HTTPRoute("/users/{id}")).
+		String("order.id", "42").
+		Int("order.line_count", 3).
+		Bool("orderPaid", true).
+		Float64("Order.Total", 9.5).
+		Build()
+
+	assert.Equal(t, []attribute.KeyValue{
+		attribute.String("tenant.id", "acme"),
+		attribute.String("enduser.id", "u-1"),
+		attribute.String("http.route", "/users/{id}"),
+		attribute.String("order.id", "42"),
+		attribute.Int("order.line_count", 3),
+	}, attrs)
+	assert.Len(t, reported, 2)
+}
+
+func TestValidKey(t *testing.T) {
+	assert.True(t, ValidKey("order.line_count"))
+	assert.True(t, ValidKey("http2.route"))
+	assert.False(t, ValidKey("order..id"))
+	assert.False(t, ValidKey("order-id"))
+	assert.False(t, ValidKey(".order"))
+	assert.False(t, ValidKey(""))
+}
@@ -0,0 +1,116 @@
+package otelaws
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sns"
+	snstypes "github.com/aws/aws-sdk-go-v2/service/sns/types"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	sqstypes "github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// maxMessageAttributes is the number of message attributes SQS and SNS accept.
+const maxMessageAttributes = 10
+
+// SQSCarrier adapts SQS message attributes to a propagation.TextMapCarrier.
+type SQSCarrier map[string]sqstypes.MessageAttributeValue
+
+var _ propagation.TextMapCarrier = SQSCarrier{}
+
+// Get implements the propagation.TextMapCarrier interface.
+func (c SQSCarrier) Get(key string) string {
+	return aws.ToString(c[key].StringValue)
+}
+
+// Set implements the propagation.TextMapCarrier interface.
+func (c SQSCarrier) Set(key, value string) {
+	c[key] = sqstypes.MessageAttributeValue{DataType: aws.String("String"), StringValue: aws.String(value)}
+}
+
+// Keys implements the propagation.TextMapCarrier interface.
+func (c SQSCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+
+	return keys
+}
+
+// SNSCarrier adapts SNS message attributes to a propagation.TextMapCarrier.
+type SNSCarrier map[string]snstypes.MessageAttributeValue
+
+var _ propagation.TextMapCarrier = SNSCarrier{}
+
+// Get implements the propagation.TextMapCarrier interface.
+func (c SNSCarrier) Get(key string) string {
+	return aws.ToString(c[key].StringValue)
+}
+
+// Set implements the propagation.TextMapCarrier interface.
+func (c SNSCarrier) Set(key, value string) {
+	c[key] = snstypes.MessageAttributeValue{DataType: aws.String("String"), StringValue: aws.String(value)}
+}
+
+// Keys implements the propagation.TextMapCarrier interface.
+func (c SNSCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+
+	return keys
+}
+
+// ExtractSQS returns ctx with the context extracted from the attributes of a
+// received message. Request them with MessageAttributeNames, e.g. "All", and
+// enable raw message delivery on SNS subscriptions.
+func ExtractSQS(ctx context.Context, msg sqstypes.Message, opts ...Option) context.Context {
+	return newConfig(opts).propagator.Extract(ctx, SQSCarrier(msg.MessageAttributes))
+}
+
+// injectMessages injects the span context of ctx in the messages sent by
+// the call, unless it would exceed the message attributes limit.
+func (m middlewares) injectMessages(ctx context.Context, params interface{}) {
+	switch in := params.(type) {
+	case *sqs.SendMessageInput:
+		in.MessageAttributes = injectSQS(ctx, m.propagator, in.MessageAttributes)
+	case *sqs.SendMessageBatchInput:
+		for i := range in.Entries {
+			in.Entries[i].MessageAttributes = injectSQS(ctx, m.propagator, in.Entries[i].MessageAttributes)
+		}
+	case *sns.PublishInput:
+		in.MessageAttributes = injectSNS(ctx, m.propagator, in.MessageAttributes)
+	case *sns.PublishBatchInput:
+		for i := range in.PublishBatchRequestEntries {
+			entry := &in.PublishBatchRequestEntries[i]
+			entry.MessageAttributes = injectSNS(ctx, m.propagator, entry.MessageAttributes)
+		}
+	}
+}
+
+func injectSQS(ctx context.Context, propagator propagation.TextMapPropagator, attrs map[string]sqstypes.MessageAttributeValue) map[string]sqstypes.MessageAttributeValue {
+	if len(attrs)+len(propagator.Fields()) > maxMessageAttributes {
+		return attrs
+	}
+	if attrs == nil {
+		attrs = make(map[string]sqstypes.MessageAttributeValue)
+	}
+	propagator.Inject(ctx, SQSCarrier(attrs))
+
+	return attrs
+}
+
+func injectSNS(ctx context.Context, propagator propagation.TextMapPropagator, attrs map[string]snstypes.MessageAttributeValue) map[string]snstypes.MessageAttributeValue {
+	if len(attrs)+len(propagator.Fields()) > maxMessageAttributes {
+		return attrs
+	}
+	if attrs == nil {
+		attrs = make(map[string]snstypes.MessageAttributeValue)
+	}
+	propagator.Inject(ctx, SNSCarrier(attrs))
+
+	return attrs
+}
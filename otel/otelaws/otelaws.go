@@ -0,0 +1,129 @@
+// Package otelaws instruments aws-sdk-go-v2 clients with the tracer provider
+// and propagators set up by the otel package.
+package otelaws
+
+import (
+	"context"
+
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/smithy-go/middleware"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/rezazadehramin/opentelemetry-go/otel/otelaws"
+
+// AWS attributes recorded on the client spans.
+const (
+	RegionKey    = attribute.Key("aws.region")
+	RequestIDKey = attribute.Key("aws.request_id")
+)
+
+// Option configures the middlewares.
+type Option func(*config)
+
+type config struct {
+	provider   trace.TracerProvider
+	propagator propagation.TextMapPropagator
+}
+
+// WithTracerProvider sets the provider spans are created with,
+// the global one registered by the otel pipeline by default.
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(c *config) {
+		c.provider = provider
+	}
+}
+
+// WithPropagators sets the propagators the context is injected in messages
+// with, the global ones registered by the otel pipeline by default.
+func WithPropagators(propagator propagation.TextMapPropagator) Option {
+	return func(c *config) {
+		c.propagator = propagator
+	}
+}
+
+func newConfig(opts []Option) config {
+	c := config{
+		provider:   otel.GetTracerProvider(),
+		propagator: otel.GetTextMapPropagator(),
+	}
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	return c
+}
+
+// AppendMiddlewares adds the middlewares recording a client span for every
+// call, named after the service and operation, e.g. S3.GetObject, to the API
+// options of an aws.Config or of a single client:
+//
+//	cfg, err := config.LoadDefaultConfig(ctx)
+//	otelaws.AppendMiddlewares(&cfg.APIOptions)
+//
+// The span context is also injected in the message attributes of the
+// SQS SendMessage, SendMessageBatch and SNS Publish, PublishBatch calls,
+// see ExtractSQS for the consumer side.
+func AppendMiddlewares(apiOptions *[]func(*middleware.Stack) error, opts ...Option) {
+	m := middlewares{config: newConfig(opts)}
+	m.tracer = m.provider.Tracer(instrumentationName, trace.WithSchemaURL(semconv.SchemaURL))
+
+	*apiOptions = append(*apiOptions, m.register)
+}
+
+type middlewares struct {
+	config
+	tracer trace.Tracer
+}
+
+func (m middlewares) register(stack *middleware.Stack) error {
+	if err := stack.Initialize.Add(middleware.InitializeMiddlewareFunc("OTelInitialize", m.initialize), middleware.Before); err != nil {
+		return err
+	}
+
+	return stack.Deserialize.Add(middleware.DeserializeMiddlewareFunc("OTelDeserialize", m.deserialize), middleware.Before)
+}
+
+// initialize starts the span of the call and injects it in the messages.
+func (m middlewares) initialize(ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler) (middleware.InitializeOutput, middleware.Metadata, error) {
+	service, operation := awsmiddleware.GetServiceID(ctx), awsmiddleware.GetOperationName(ctx)
+	ctx, span := m.tracer.Start(ctx, service+"."+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.RPCSystemKey.String("aws-api"),
+			semconv.RPCServiceKey.String(service),
+			semconv.RPCMethodKey.String(operation),
+			RegionKey.String(awsmiddleware.GetRegion(ctx)),
+		),
+	)
+	defer span.End()
+
+	m.injectMessages(ctx, in.Parameters)
+
+	out, metadata, err := next.HandleInitialize(ctx, in)
+	if requestID, ok := awsmiddleware.GetRequestIDMetadata(metadata); ok {
+		span.SetAttributes(RequestIDKey.String(requestID))
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	return out, metadata, err
+}
+
+// deserialize records the HTTP status code of the response.
+func (m middlewares) deserialize(ctx context.Context, in middleware.DeserializeInput, next middleware.DeserializeHandler) (middleware.DeserializeOutput, middleware.Metadata, error) {
+	out, metadata, err := next.HandleDeserialize(ctx, in)
+	if resp, ok := out.RawResponse.(*smithyhttp.Response); ok {
+		trace.SpanFromContext(ctx).SetAttributes(semconv.HTTPStatusCodeKey.Int(resp.StatusCode))
+	}
+
+	return out, metadata, err
+}
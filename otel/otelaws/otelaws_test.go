@@ -0,0 +1,102 @@
+package otelaws
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	sqstypes "github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+func newTestClient(t *testing.T, handler http.HandlerFunc) (*sqs.Client, *tracetest.SpanRecorder) {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	recorder := tracetest.NewSpanRecorder()
+	cfg := aws.Config{
+		Region:       "eu-west-1",
+		Credentials:  aws.AnonymousCredentials{},
+		BaseEndpoint: aws.String(server.URL),
+	}
+	AppendMiddlewares(&cfg.APIOptions,
+		WithTracerProvider(trace.NewTracerProvider(trace.WithSpanProcessor(recorder))),
+		WithPropagators(propagation.TraceContext{}))
+
+	return sqs.NewFromConfig(cfg, func(o *sqs.Options) {
+		o.DisableMessageChecksumValidation = true
+		o.RetryMaxAttempts = 1
+	}), recorder
+}
+
+func TestAppendMiddlewares_RecordsCallsAndInjectsMessages(t *testing.T) {
+	var sent struct {
+		MessageAttributes map[string]struct{ StringValue string }
+	}
+	client, recorder := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		assert.Nil(t, json.NewDecoder(r.Body).Decode(&sent))
+		w.Header().Set("x-amzn-RequestId", "req-1")
+		w.Write([]byte(`{"MessageId":"m-1"}`))
+	})
+
+	_, err := client.SendMessage(context.TODO(), &sqs.SendMessageInput{
+		QueueUrl:    aws.String("https://sqs.eu-west-1.amazonaws.com/1/orders"),
+		MessageBody: aws.String("hello"),
+	})
+	assert.Nil(t, err)
+
+	span := recorder.Ended()[0]
+	assert.Equal(t, "SQS.SendMessage", span.Name())
+	assert.Equal(t, oteltrace.SpanKindClient, span.SpanKind())
+	assert.Contains(t, span.Attributes(), RegionKey.String("eu-west-1"))
+	assert.Contains(t, span.Attributes(), RequestIDKey.String("req-1"))
+	assert.Contains(t, span.Attributes(), attribute.Int("http.status_code", http.StatusOK))
+
+	// the consumer span continues the producer call
+	msg := sqstypes.Message{MessageAttributes: map[string]sqstypes.MessageAttributeValue{}}
+	for key, value := range sent.MessageAttributes {
+		SQSCarrier(msg.MessageAttributes).Set(key, value.StringValue)
+	}
+	sc := oteltrace.SpanContextFromContext(ExtractSQS(context.TODO(), msg, WithPropagators(propagation.TraceContext{})))
+	assert.Equal(t, span.SpanContext().SpanID(), sc.SpanID())
+}
+
+func TestAppendMiddlewares_RecordsErrors(t *testing.T) {
+	client, recorder := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("x-amzn-query-error", "AWS.SimpleQueueService.NonExistentQueue;Sender")
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"__type":"com.amazonaws.sqs#QueueDoesNotExist","message":"missing"}`))
+	})
+
+	_, err := client.GetQueueUrl(context.TODO(), &sqs.GetQueueUrlInput{QueueName: aws.String("missing")})
+	assert.NotNil(t, err)
+
+	span := recorder.Ended()[0]
+	assert.Equal(t, "SQS.GetQueueUrl", span.Name())
+	assert.Equal(t, codes.Error, span.Status().Code)
+	assert.Contains(t, span.Attributes(), attribute.Int("http.status_code", http.StatusBadRequest))
+}
+
+func TestInjectSQS_KeepsAttributeLimit(t *testing.T) {
+	attrs := map[string]sqstypes.MessageAttributeValue{}
+	for _, key := range []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j"} {
+		SQSCarrier(attrs).Set(key, "value")
+	}
+
+	sc := oteltrace.NewSpanContext(oteltrace.SpanContextConfig{TraceID: oteltrace.TraceID{1}, SpanID: oteltrace.SpanID{1}})
+	ctx := oteltrace.ContextWithSpanContext(context.TODO(), sc)
+	assert.Len(t, injectSQS(ctx, propagation.TraceContext{}, attrs), 10)
+	assert.Len(t, injectSQS(ctx, propagation.TraceContext{}, nil), 1)
+}
@@ -0,0 +1,88 @@
+// Package otelchi instruments chi routers with the tracer provider
+// and propagators set up by the otel package.
+package otelchi
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/rezazadehramin/opentelemetry-go/otel/internal/httpconv"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/rezazadehramin/opentelemetry-go/otel/otelchi"
+
+// Option configures the middleware.
+type Option func(*config)
+
+type config struct {
+	provider   trace.TracerProvider
+	propagator propagation.TextMapPropagator
+}
+
+// WithTracerProvider sets the provider spans are created with,
+// the global one registered by the otel pipeline by default.
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(c *config) {
+		c.provider = provider
+	}
+}
+
+// WithPropagators sets the propagators the remote context is extracted with,
+// the global ones registered by the otel pipeline by default.
+func WithPropagators(propagator propagation.TextMapPropagator) Option {
+	return func(c *config) {
+		c.propagator = propagator
+	}
+}
+
+// Middleware starts a server span for every request named after the route
+// template, e.g. "GET /users/{id}", with the semconv HTTP attributes and the
+// response status code. Install it with Router.Use.
+//
+// Panics are recorded on the span, with a 500 status, before being
+// propagated to the recoverer middleware.
+func Middleware(opts ...Option) func(http.Handler) http.Handler {
+	cfg := config{
+		provider:   otel.GetTracerProvider(),
+		propagator: otel.GetTextMapPropagator(),
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	server := httpconv.Server{
+		Tracer:     cfg.provider.Tracer(instrumentationName, trace.WithSchemaURL(httpconv.SchemaURL)),
+		Propagator: cfg.propagator,
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, span := server.Start(r, "")
+			rw := httpconv.NewResponseWriter(w)
+			r = r.WithContext(ctx)
+
+			// the route is only known once the router has matched the request
+			defer func() {
+				if recovered := recover(); recovered != nil {
+					server.EndPanic(span, r.Method, routePattern(r), recovered)
+					panic(recovered)
+				}
+			}()
+
+			next.ServeHTTP(rw, r)
+
+			server.End(span, r.Method, routePattern(r), rw.Status)
+		})
+	}
+}
+
+func routePattern(r *http.Request) string {
+	if rctx := chi.RouteContext(r.Context()); rctx != nil {
+		return rctx.RoutePattern()
+	}
+
+	return ""
+}
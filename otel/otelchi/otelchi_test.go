@@ -0,0 +1,62 @@
+package otelchi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+func newTestRouter() (chi.Router, *tracetest.SpanRecorder) {
+	recorder := tracetest.NewSpanRecorder()
+
+	r := chi.NewRouter()
+	r.Use(middleware.Recoverer, Middleware(
+		WithTracerProvider(trace.NewTracerProvider(trace.WithSpanProcessor(recorder))),
+		WithPropagators(propagation.TraceContext{}),
+	))
+
+	return r, recorder
+}
+
+func TestMiddleware_NamesSpanByRoute(t *testing.T) {
+	router, recorder := newTestRouter()
+	router.Get("/users/{id}", func(w http.ResponseWriter, r *http.Request) {
+		assert.True(t, oteltrace.SpanFromContext(r.Context()).IsRecording())
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	r := httptest.NewRequest(http.MethodGet, "/users/42", nil)
+	r.Header.Set("traceparent", "00-5759e988bd862e3fe1be46a994272793-53995c3f42cd8ad8-01")
+	router.ServeHTTP(httptest.NewRecorder(), r)
+
+	span := recorder.Ended()[0]
+	assert.Equal(t, "GET /users/{id}", span.Name())
+	assert.Equal(t, oteltrace.SpanKindServer, span.SpanKind())
+	assert.Equal(t, "5759e988bd862e3fe1be46a994272793", span.Parent().TraceID().String())
+	assert.Contains(t, span.Attributes(), attribute.String("http.route", "/users/{id}"))
+	assert.Contains(t, span.Attributes(), attribute.Int("http.status_code", http.StatusNotFound))
+}
+
+func TestMiddleware_RecordsPanics(t *testing.T) {
+	router, recorder := newTestRouter()
+	router.Post("/checkout", func(http.ResponseWriter, *http.Request) { panic("out of stock") })
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/checkout", nil))
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+	span := recorder.Ended()[0]
+	assert.Equal(t, "POST /checkout", span.Name())
+	assert.Equal(t, codes.Error, span.Status().Code)
+	assert.Contains(t, span.Events()[0].Attributes, attribute.String("exception.message", "out of stock"))
+}
@@ -0,0 +1,99 @@
+// Package otelecho instruments echo servers with the tracer provider
+// and propagators set up by the otel package.
+package otelecho
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rezazadehramin/opentelemetry-go/otel/internal/httpconv"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/rezazadehramin/opentelemetry-go/otel/otelecho"
+
+// Option configures the middleware.
+type Option func(*config)
+
+type config struct {
+	provider   trace.TracerProvider
+	propagator propagation.TextMapPropagator
+}
+
+// WithTracerProvider sets the provider spans are created with,
+// the global one registered by the otel pipeline by default.
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(c *config) {
+		c.provider = provider
+	}
+}
+
+// WithPropagators sets the propagators the remote context is extracted with,
+// the global ones registered by the otel pipeline by default.
+func WithPropagators(propagator propagation.TextMapPropagator) Option {
+	return func(c *config) {
+		c.propagator = propagator
+	}
+}
+
+// Middleware starts a server span for every request named after the route
+// template, e.g. "GET /users/:id", with the semconv HTTP attributes and the
+// response status code.
+//
+// Errors returned by handlers are recorded on the span, with the status of
+// an *echo.HTTPError or 500, and so are panics, with a 500 status, before
+// being propagated to the recover middleware.
+func Middleware(opts ...Option) echo.MiddlewareFunc {
+	cfg := config{
+		provider:   otel.GetTracerProvider(),
+		propagator: otel.GetTextMapPropagator(),
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	server := httpconv.Server{
+		Tracer:     cfg.provider.Tracer(instrumentationName, trace.WithSchemaURL(httpconv.SchemaURL)),
+		Propagator: cfg.propagator,
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			r := c.Request()
+			route := c.Path()
+			ctx, span := server.Start(r, route)
+			c.SetRequest(r.WithContext(ctx))
+
+			defer func() {
+				if recovered := recover(); recovered != nil {
+					server.EndPanic(span, r.Method, route, recovered)
+					panic(recovered)
+				}
+			}()
+
+			err := next(c)
+
+			status := c.Response().Status
+			if err != nil {
+				span.RecordError(err)
+				status = errorStatus(err)
+			}
+			server.End(span, r.Method, route, status)
+
+			return err
+		}
+	}
+}
+
+// errorStatus returns the status echo responds with for err.
+func errorStatus(err error) int {
+	var httpErr *echo.HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.Code
+	}
+
+	return http.StatusInternalServerError
+}
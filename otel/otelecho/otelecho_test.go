@@ -0,0 +1,65 @@
+package otelecho
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+func newTestServer() (*echo.Echo, *tracetest.SpanRecorder) {
+	recorder := tracetest.NewSpanRecorder()
+
+	e := echo.New()
+	e.Use(middleware.Recover(), Middleware(
+		WithTracerProvider(trace.NewTracerProvider(trace.WithSpanProcessor(recorder))),
+		WithPropagators(propagation.TraceContext{}),
+	))
+
+	return e, recorder
+}
+
+func TestMiddleware_NamesSpanByRoute(t *testing.T) {
+	e, recorder := newTestServer()
+	e.GET("/users/:id", func(c echo.Context) error {
+		assert.True(t, oteltrace.SpanFromContext(c.Request().Context()).IsRecording())
+		return echo.NewHTTPError(http.StatusNotFound, "user not found")
+	})
+
+	r := httptest.NewRequest(http.MethodGet, "/users/42", nil)
+	r.Header.Set("traceparent", "00-5759e988bd862e3fe1be46a994272793-53995c3f42cd8ad8-01")
+	e.ServeHTTP(httptest.NewRecorder(), r)
+
+	span := recorder.Ended()[0]
+	assert.Equal(t, "GET /users/:id", span.Name())
+	assert.Equal(t, oteltrace.SpanKindServer, span.SpanKind())
+	assert.Equal(t, "5759e988bd862e3fe1be46a994272793", span.Parent().TraceID().String())
+	assert.Contains(t, span.Attributes(), attribute.String("http.route", "/users/:id"))
+	assert.Contains(t, span.Attributes(), attribute.Int("http.status_code", http.StatusNotFound))
+	assert.Equal(t, "exception", span.Events()[0].Name)
+}
+
+func TestMiddleware_RecordsErrorsAndPanics(t *testing.T) {
+	e, recorder := newTestServer()
+	e.POST("/checkout", func(c echo.Context) error { return errors.New("payment failed") })
+	e.POST("/refund", func(c echo.Context) error { panic("out of stock") })
+
+	e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/checkout", nil))
+	e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/refund", nil))
+
+	spans := recorder.Ended()
+	assert.Contains(t, spans[0].Attributes(), attribute.Int("http.status_code", http.StatusInternalServerError))
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	assert.Equal(t, codes.Error, spans[1].Status().Code)
+	assert.Contains(t, spans[1].Events()[0].Attributes, attribute.String("exception.message", "out of stock"))
+}
@@ -0,0 +1,78 @@
+// Package otelgin instruments gin engines with the tracer provider
+// and propagators set up by the otel package.
+package otelgin
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/rezazadehramin/opentelemetry-go/otel/internal/httpconv"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/rezazadehramin/opentelemetry-go/otel/otelgin"
+
+// Option configures the middleware.
+type Option func(*config)
+
+type config struct {
+	provider   trace.TracerProvider
+	propagator propagation.TextMapPropagator
+}
+
+// WithTracerProvider sets the provider spans are created with,
+// the global one registered by the otel pipeline by default.
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(c *config) {
+		c.provider = provider
+	}
+}
+
+// WithPropagators sets the propagators the remote context is extracted with,
+// the global ones registered by the otel pipeline by default.
+func WithPropagators(propagator propagation.TextMapPropagator) Option {
+	return func(c *config) {
+		c.propagator = propagator
+	}
+}
+
+// Middleware starts a server span for every request named after the route
+// template, e.g. "GET /users/:id", with the semconv HTTP attributes and the
+// response status code.
+//
+// Errors attached to the gin context are recorded on the span, and so are
+// panics, with a 500 status, before being propagated to the recovery middleware.
+func Middleware(opts ...Option) gin.HandlerFunc {
+	cfg := config{
+		provider:   otel.GetTracerProvider(),
+		propagator: otel.GetTextMapPropagator(),
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	server := httpconv.Server{
+		Tracer:     cfg.provider.Tracer(instrumentationName, trace.WithSchemaURL(httpconv.SchemaURL)),
+		Propagator: cfg.propagator,
+	}
+
+	return func(c *gin.Context) {
+		route := c.FullPath()
+		ctx, span := server.Start(c.Request, route)
+		c.Request = c.Request.WithContext(ctx)
+
+		defer func() {
+			if r := recover(); r != nil {
+				server.EndPanic(span, c.Request.Method, route, r)
+				panic(r)
+			}
+		}()
+
+		c.Next()
+
+		for _, err := range c.Errors {
+			span.RecordError(err.Err)
+		}
+		server.End(span, c.Request.Method, route, c.Writer.Status())
+	}
+}
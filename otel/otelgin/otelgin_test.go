@@ -0,0 +1,64 @@
+package otelgin
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+func newTestEngine() (*gin.Engine, *tracetest.SpanRecorder) {
+	gin.SetMode(gin.TestMode)
+	recorder := tracetest.NewSpanRecorder()
+
+	engine := gin.New()
+	engine.Use(gin.Recovery(), Middleware(
+		WithTracerProvider(trace.NewTracerProvider(trace.WithSpanProcessor(recorder))),
+		WithPropagators(propagation.TraceContext{}),
+	))
+
+	return engine, recorder
+}
+
+func TestMiddleware_NamesSpanByRoute(t *testing.T) {
+	engine, recorder := newTestEngine()
+	engine.GET("/users/:id", func(c *gin.Context) {
+		assert.True(t, oteltrace.SpanFromContext(c.Request.Context()).IsRecording())
+		c.Error(errors.New("user not found"))
+		c.Status(http.StatusNotFound)
+	})
+
+	r := httptest.NewRequest(http.MethodGet, "/users/42", nil)
+	r.Header.Set("traceparent", "00-5759e988bd862e3fe1be46a994272793-53995c3f42cd8ad8-01")
+	engine.ServeHTTP(httptest.NewRecorder(), r)
+
+	span := recorder.Ended()[0]
+	assert.Equal(t, "GET /users/:id", span.Name())
+	assert.Equal(t, oteltrace.SpanKindServer, span.SpanKind())
+	assert.Equal(t, "5759e988bd862e3fe1be46a994272793", span.Parent().TraceID().String())
+	assert.Contains(t, span.Attributes(), attribute.String("http.route", "/users/:id"))
+	assert.Contains(t, span.Attributes(), attribute.Int("http.status_code", http.StatusNotFound))
+	assert.Equal(t, "exception", span.Events()[0].Name)
+}
+
+func TestMiddleware_RecordsPanics(t *testing.T) {
+	engine, recorder := newTestEngine()
+	engine.POST("/checkout", func(c *gin.Context) { panic("out of stock") })
+
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/checkout", nil))
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+	span := recorder.Ended()[0]
+	assert.Equal(t, codes.Error, span.Status().Code)
+	assert.Contains(t, span.Events()[0].Attributes, attribute.String("exception.message", "out of stock"))
+}
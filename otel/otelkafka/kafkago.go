@@ -0,0 +1,80 @@
+package otelkafka
+
+import (
+	"context"
+
+	"github.com/segmentio/kafka-go"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// KafkaGoCarrier adapts the headers of a kafka-go message
+// to a propagation.TextMapCarrier.
+type KafkaGoCarrier struct {
+	msg *kafka.Message
+}
+
+var _ propagation.TextMapCarrier = KafkaGoCarrier{}
+
+// NewKafkaGoCarrier returns a carrier of the headers of msg.
+func NewKafkaGoCarrier(msg *kafka.Message) KafkaGoCarrier {
+	return KafkaGoCarrier{msg: msg}
+}
+
+// Get implements the propagation.TextMapCarrier interface.
+func (c KafkaGoCarrier) Get(key string) string {
+	for _, h := range c.msg.Headers {
+		if h.Key == key {
+			return string(h.Value)
+		}
+	}
+
+	return ""
+}
+
+// Set implements the propagation.TextMapCarrier interface.
+func (c KafkaGoCarrier) Set(key, value string) {
+	for i, h := range c.msg.Headers {
+		if h.Key == key {
+			c.msg.Headers[i].Value = []byte(value)
+			return
+		}
+	}
+
+	c.msg.Headers = append(c.msg.Headers, kafka.Header{Key: key, Value: []byte(value)})
+}
+
+// Keys implements the propagation.TextMapCarrier interface.
+func (c KafkaGoCarrier) Keys() []string {
+	keys := make([]string, len(c.msg.Headers))
+	for i, h := range c.msg.Headers {
+		keys[i] = h.Key
+	}
+
+	return keys
+}
+
+// InjectKafkaGo injects the context of ctx in the headers of msg.
+func InjectKafkaGo(ctx context.Context, msg *kafka.Message, opts ...Option) {
+	newConfig(opts).propagator.Inject(ctx, NewKafkaGoCarrier(msg))
+}
+
+// ExtractKafkaGo returns ctx with the context extracted from the headers of msg.
+func ExtractKafkaGo(ctx context.Context, msg *kafka.Message, opts ...Option) context.Context {
+	return newConfig(opts).propagator.Extract(ctx, NewKafkaGoCarrier(msg))
+}
+
+// StartKafkaGoProducerSpan starts a producer span for msg and injects it in
+// its headers, end the span once the message is written. The topic is the
+// one of msg, set it when it's only configured on the writer.
+func StartKafkaGoProducerSpan(ctx context.Context, msg *kafka.Message, opts ...Option) (context.Context, trace.Span) {
+	return newConfig(opts).startProducer(ctx, msg.Topic, NewKafkaGoCarrier(msg),
+		messageAttributes(msg.Topic, -1, msg.Key))
+}
+
+// StartKafkaGoConsumerSpan starts a consumer span processing msg
+// linked to the span that produced it.
+func StartKafkaGoConsumerSpan(ctx context.Context, msg *kafka.Message, opts ...Option) (context.Context, trace.Span) {
+	return newConfig(opts).startConsumer(ctx, msg.Topic, NewKafkaGoCarrier(msg),
+		messageAttributes(msg.Topic, int64(msg.Partition), msg.Key))
+}
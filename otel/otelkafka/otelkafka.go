@@ -0,0 +1,103 @@
+// Package otelkafka propagates the trace context through Kafka message headers
+// of the sarama and segmentio/kafka-go clients with the tracer provider and
+// propagators set up by the otel package.
+//
+// Producers start a span per message with StartSaramaProducerSpan or
+// StartKafkaGoProducerSpan, injecting its context in the message headers.
+// Consumers start a span per message with StartSaramaConsumerSpan or
+// StartKafkaGoConsumerSpan, linked to the span that produced the message.
+package otelkafka
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/rezazadehramin/opentelemetry-go/otel/otelkafka"
+
+// Option configures the spans started by the helpers.
+type Option func(*config)
+
+type config struct {
+	provider   trace.TracerProvider
+	propagator propagation.TextMapPropagator
+}
+
+// WithTracerProvider sets the provider spans are created with,
+// the global one registered by the otel pipeline by default.
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(c *config) {
+		c.provider = provider
+	}
+}
+
+// WithPropagators sets the propagators the context is injected and extracted
+// with, the global ones registered by the otel pipeline by default.
+func WithPropagators(propagator propagation.TextMapPropagator) Option {
+	return func(c *config) {
+		c.propagator = propagator
+	}
+}
+
+func newConfig(opts []Option) config {
+	c := config{
+		provider:   otel.GetTracerProvider(),
+		propagator: otel.GetTextMapPropagator(),
+	}
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	return c
+}
+
+func messageAttributes(topic string, partition int64, key []byte) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{
+		semconv.MessagingSystemKey.String("kafka"),
+		semconv.MessagingDestinationKindTopic,
+		semconv.MessagingDestinationKey.String(topic),
+	}
+	if partition >= 0 {
+		attrs = append(attrs, semconv.MessagingKafkaPartitionKey.Int64(partition))
+	}
+	if len(key) > 0 {
+		attrs = append(attrs, semconv.MessagingKafkaMessageKeyKey.String(string(key)))
+	}
+
+	return attrs
+}
+
+// startProducer starts a producer span injected in carrier.
+func (c config) startProducer(ctx context.Context, topic string, carrier propagation.TextMapCarrier, attrs []attribute.KeyValue) (context.Context, trace.Span) {
+	ctx, span := c.provider.Tracer(instrumentationName, trace.WithSchemaURL(semconv.SchemaURL)).Start(ctx, topic+" send",
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(attrs...),
+	)
+	c.propagator.Inject(ctx, carrier)
+
+	return ctx, span
+}
+
+// startConsumer starts a consumer span linked to the producer span extracted
+// from carrier, continuing the producer trace when ctx holds no span.
+func (c config) startConsumer(ctx context.Context, topic string, carrier propagation.TextMapCarrier, attrs []attribute.KeyValue) (context.Context, trace.Span) {
+	opts := []trace.SpanStartOption{
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(append(attrs, semconv.MessagingOperationProcess)...),
+	}
+
+	producer := trace.SpanContextFromContext(c.propagator.Extract(context.Background(), carrier))
+	if producer.IsValid() {
+		opts = append(opts, trace.WithLinks(trace.Link{SpanContext: producer}))
+	}
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		ctx = c.propagator.Extract(ctx, carrier)
+	}
+
+	return c.provider.Tracer(instrumentationName, trace.WithSchemaURL(semconv.SchemaURL)).Start(ctx, topic+" process", opts...)
+}
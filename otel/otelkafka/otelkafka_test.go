@@ -0,0 +1,86 @@
+package otelkafka
+
+import (
+	"context"
+	"testing"
+
+	"github.com/IBM/sarama"
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+func testOptions() ([]Option, *tracetest.SpanRecorder) {
+	recorder := tracetest.NewSpanRecorder()
+	return []Option{
+		WithTracerProvider(trace.NewTracerProvider(trace.WithSpanProcessor(recorder))),
+		WithPropagators(propagation.TraceContext{}),
+	}, recorder
+}
+
+func TestSarama_ProducerToConsumer(t *testing.T) {
+	opts, recorder := testOptions()
+
+	produced := &sarama.ProducerMessage{Topic: "orders", Key: sarama.StringEncoder("42")}
+	_, producer := StartSaramaProducerSpan(context.TODO(), produced, opts...)
+	producer.End()
+
+	// headers as received by a consumer
+	consumed := &sarama.ConsumerMessage{Topic: "orders", Partition: 3}
+	for _, h := range produced.Headers {
+		consumed.Headers = append(consumed.Headers, &sarama.RecordHeader{Key: h.Key, Value: h.Value})
+	}
+	_, consumer := StartSaramaConsumerSpan(context.TODO(), consumed, opts...)
+	consumer.End()
+
+	spans := recorder.Ended()
+	assert.Equal(t, "orders send", spans[0].Name())
+	assert.Equal(t, oteltrace.SpanKindProducer, spans[0].SpanKind())
+	assert.Contains(t, spans[0].Attributes(), attribute.String("messaging.kafka.message_key", "42"))
+
+	assert.Equal(t, "orders process", spans[1].Name())
+	assert.Equal(t, oteltrace.SpanKindConsumer, spans[1].SpanKind())
+	assert.Equal(t, spans[0].SpanContext().SpanID(), spans[1].Parent().SpanID())
+	assert.Equal(t, spans[0].SpanContext().SpanID(), spans[1].Links()[0].SpanContext.SpanID())
+	assert.Contains(t, spans[1].Attributes(), attribute.Int64("messaging.kafka.partition", 3))
+}
+
+func TestKafkaGo_ConsumerKeepsItsParent(t *testing.T) {
+	opts, recorder := testOptions()
+
+	msg := &kafka.Message{Topic: "orders"}
+	_, producer := StartKafkaGoProducerSpan(context.TODO(), msg, opts...)
+	producer.End()
+	assert.Equal(t, "traceparent", msg.Headers[0].Key)
+
+	tp := trace.NewTracerProvider(trace.WithSpanProcessor(recorder))
+	ctx, batch := tp.Tracer("test").Start(context.TODO(), "batch")
+	_, consumer := StartKafkaGoConsumerSpan(ctx, msg, opts...)
+	consumer.End()
+	batch.End()
+
+	spans := recorder.Ended()
+	assert.Equal(t, batch.SpanContext().SpanID(), spans[1].Parent().SpanID())
+	assert.Equal(t, producer.SpanContext().SpanID(), spans[1].Links()[0].SpanContext.SpanID())
+}
+
+func TestKafkaGo_InjectExtract(t *testing.T) {
+	opts, _ := testOptions()
+
+	sc := oteltrace.NewSpanContext(oteltrace.SpanContextConfig{
+		TraceID:    oteltrace.TraceID{1},
+		SpanID:     oteltrace.SpanID{2},
+		TraceFlags: oteltrace.FlagsSampled,
+	})
+	msg := &kafka.Message{Headers: []kafka.Header{{Key: "traceparent", Value: []byte("stale")}}}
+	InjectKafkaGo(oteltrace.ContextWithSpanContext(context.TODO(), sc), msg, opts...)
+	assert.Len(t, msg.Headers, 1)
+
+	extracted := oteltrace.SpanContextFromContext(ExtractKafkaGo(context.TODO(), msg, opts...))
+	assert.Equal(t, sc.TraceID(), extracted.TraceID())
+	assert.True(t, extracted.IsRemote())
+}
@@ -0,0 +1,132 @@
+package otelkafka
+
+import (
+	"context"
+
+	"github.com/IBM/sarama"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// SaramaProducerCarrier adapts the headers of a sarama producer message
+// to a propagation.TextMapCarrier.
+type SaramaProducerCarrier struct {
+	msg *sarama.ProducerMessage
+}
+
+var _ propagation.TextMapCarrier = SaramaProducerCarrier{}
+
+// NewSaramaProducerCarrier returns a carrier of the headers of msg.
+func NewSaramaProducerCarrier(msg *sarama.ProducerMessage) SaramaProducerCarrier {
+	return SaramaProducerCarrier{msg: msg}
+}
+
+// Get implements the propagation.TextMapCarrier interface.
+func (c SaramaProducerCarrier) Get(key string) string {
+	for _, h := range c.msg.Headers {
+		if string(h.Key) == key {
+			return string(h.Value)
+		}
+	}
+
+	return ""
+}
+
+// Set implements the propagation.TextMapCarrier interface.
+func (c SaramaProducerCarrier) Set(key, value string) {
+	for i, h := range c.msg.Headers {
+		if string(h.Key) == key {
+			c.msg.Headers[i].Value = []byte(value)
+			return
+		}
+	}
+
+	c.msg.Headers = append(c.msg.Headers, sarama.RecordHeader{Key: []byte(key), Value: []byte(value)})
+}
+
+// Keys implements the propagation.TextMapCarrier interface.
+func (c SaramaProducerCarrier) Keys() []string {
+	keys := make([]string, len(c.msg.Headers))
+	for i, h := range c.msg.Headers {
+		keys[i] = string(h.Key)
+	}
+
+	return keys
+}
+
+// SaramaConsumerCarrier adapts the headers of a sarama consumer message
+// to a propagation.TextMapCarrier.
+type SaramaConsumerCarrier struct {
+	msg *sarama.ConsumerMessage
+}
+
+var _ propagation.TextMapCarrier = SaramaConsumerCarrier{}
+
+// NewSaramaConsumerCarrier returns a carrier of the headers of msg.
+func NewSaramaConsumerCarrier(msg *sarama.ConsumerMessage) SaramaConsumerCarrier {
+	return SaramaConsumerCarrier{msg: msg}
+}
+
+// Get implements the propagation.TextMapCarrier interface.
+func (c SaramaConsumerCarrier) Get(key string) string {
+	for _, h := range c.msg.Headers {
+		if h != nil && string(h.Key) == key {
+			return string(h.Value)
+		}
+	}
+
+	return ""
+}
+
+// Set implements the propagation.TextMapCarrier interface.
+func (c SaramaConsumerCarrier) Set(key, value string) {
+	for _, h := range c.msg.Headers {
+		if h != nil && string(h.Key) == key {
+			h.Value = []byte(value)
+			return
+		}
+	}
+
+	c.msg.Headers = append(c.msg.Headers, &sarama.RecordHeader{Key: []byte(key), Value: []byte(value)})
+}
+
+// Keys implements the propagation.TextMapCarrier interface.
+func (c SaramaConsumerCarrier) Keys() []string {
+	keys := make([]string, 0, len(c.msg.Headers))
+	for _, h := range c.msg.Headers {
+		if h != nil {
+			keys = append(keys, string(h.Key))
+		}
+	}
+
+	return keys
+}
+
+// InjectSarama injects the context of ctx in the headers of msg.
+func InjectSarama(ctx context.Context, msg *sarama.ProducerMessage, opts ...Option) {
+	newConfig(opts).propagator.Inject(ctx, NewSaramaProducerCarrier(msg))
+}
+
+// ExtractSarama returns ctx with the context extracted from the headers of msg.
+func ExtractSarama(ctx context.Context, msg *sarama.ConsumerMessage, opts ...Option) context.Context {
+	return newConfig(opts).propagator.Extract(ctx, NewSaramaConsumerCarrier(msg))
+}
+
+// StartSaramaProducerSpan starts a producer span for msg and injects it in
+// its headers, end the span once the message is acknowledged.
+func StartSaramaProducerSpan(ctx context.Context, msg *sarama.ProducerMessage, opts ...Option) (context.Context, trace.Span) {
+	var key []byte
+	if msg.Key != nil {
+		key, _ = msg.Key.Encode()
+	}
+
+	return newConfig(opts).startProducer(ctx, msg.Topic, NewSaramaProducerCarrier(msg),
+		messageAttributes(msg.Topic, -1, key))
+}
+
+// StartSaramaConsumerSpan starts a consumer span processing msg
+// linked to the span that produced it.
+func StartSaramaConsumerSpan(ctx context.Context, msg *sarama.ConsumerMessage, opts ...Option) (context.Context, trace.Span) {
+	return newConfig(opts).startConsumer(ctx, msg.Topic, NewSaramaConsumerCarrier(msg),
+		messageAttributes(msg.Topic, int64(msg.Partition), msg.Key))
+}
@@ -0,0 +1,76 @@
+// Package otelmiddleware instruments net/http servers with the tracer provider
+// and propagators set up by the otel package.
+package otelmiddleware
+
+import (
+	"net/http"
+
+	"github.com/rezazadehramin/opentelemetry-go/otel/internal/httpconv"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/rezazadehramin/opentelemetry-go/otel/otelmiddleware"
+
+// Option configures the middleware.
+type Option func(*config)
+
+type config struct {
+	provider   trace.TracerProvider
+	propagator propagation.TextMapPropagator
+}
+
+// WithTracerProvider sets the provider spans are created with,
+// the global one registered by the otel pipeline by default.
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(c *config) {
+		c.provider = provider
+	}
+}
+
+// WithPropagators sets the propagators the remote context is extracted with,
+// the global ones registered by the otel pipeline by default.
+func WithPropagators(propagator propagation.TextMapPropagator) Option {
+	return func(c *config) {
+		c.propagator = propagator
+	}
+}
+
+// HTTPHandler wraps next starting a server span for every request with the
+// semconv HTTP attributes and the response status code.
+//
+// The span is named after the route when next is a ServeMux matching
+// the request, after the method only otherwise. Panics are recorded on the
+// span, with a 500 status, before being propagated.
+func HTTPHandler(next http.Handler, opts ...Option) http.Handler {
+	c := config{
+		provider:   otel.GetTracerProvider(),
+		propagator: otel.GetTextMapPropagator(),
+	}
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	server := httpconv.Server{
+		Tracer:     c.provider.Tracer(instrumentationName, trace.WithSchemaURL(httpconv.SchemaURL)),
+		Propagator: c.propagator,
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, span := server.Start(r, "")
+		rw := httpconv.NewResponseWriter(w)
+		r = r.WithContext(ctx)
+
+		defer func() {
+			if recovered := recover(); recovered != nil {
+				server.EndPanic(span, r.Method, httpconv.PatternRoute(r.Pattern), recovered)
+				panic(recovered)
+			}
+		}()
+
+		next.ServeHTTP(rw, r)
+
+		server.End(span, r.Method, httpconv.PatternRoute(r.Pattern), rw.Status)
+	})
+}
@@ -0,0 +1,135 @@
+// Package otelmongo instruments the official MongoDB driver with the tracer
+// provider set up by the otel package.
+package otelmongo
+
+import (
+	"context"
+	"sync"
+
+	rotel "github.com/rezazadehramin/opentelemetry-go/otel"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/event"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/rezazadehramin/opentelemetry-go/otel/otelmongo"
+
+// CommandDurationKey is the command duration measured by the driver.
+const CommandDurationKey = attribute.Key("db.mongodb.duration")
+
+// Option configures the monitor.
+type Option func(*config)
+
+type config struct {
+	provider trace.TracerProvider
+}
+
+// WithTracerProvider sets the provider spans are created with,
+// the global one registered by the otel pipeline by default.
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(c *config) {
+		c.provider = provider
+	}
+}
+
+type spanKey struct {
+	connectionID string
+	requestID    int64
+}
+
+type monitor struct {
+	tracer trace.Tracer
+
+	mu    sync.Mutex
+	spans map[spanKey]trace.Span
+}
+
+// NewMonitor returns a command monitor recording a client span for every
+// command, named after the collection and command, e.g. users.find, with the
+// db.system, db.name, db.operation, db.mongodb.collection and duration
+// attributes. Commands are not recorded as they may hold sensitive data.
+//
+//	client, err := mongo.Connect(options.Client().ApplyURI(uri).SetMonitor(otelmongo.NewMonitor()))
+func NewMonitor(opts ...Option) *event.CommandMonitor {
+	c := config{provider: otel.GetTracerProvider()}
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	m := &monitor{
+		tracer: c.provider.Tracer(instrumentationName, trace.WithSchemaURL(semconv.SchemaURL)),
+		spans:  make(map[spanKey]trace.Span),
+	}
+
+	return &event.CommandMonitor{
+		Started:   m.started,
+		Succeeded: m.succeeded,
+		Failed:    m.failed,
+	}
+}
+
+func (m *monitor) started(ctx context.Context, e *event.CommandStartedEvent) {
+	attrs := []attribute.KeyValue{
+		semconv.DBSystemMongoDB,
+		semconv.DBNameKey.String(e.DatabaseName),
+		semconv.DBOperationKey.String(e.CommandName),
+	}
+
+	name := e.CommandName
+	if collection, ok := collectionName(e.Command, e.CommandName); ok {
+		attrs = append(attrs, semconv.DBMongoDBCollectionKey.String(collection))
+		name = collection + "." + e.CommandName
+	}
+
+	_, span := m.tracer.Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...),
+	)
+
+	m.mu.Lock()
+	m.spans[spanKey{e.ConnectionID, e.RequestID}] = span
+	m.mu.Unlock()
+}
+
+func (m *monitor) succeeded(_ context.Context, e *event.CommandSucceededEvent) {
+	m.end(e.CommandFinishedEvent, nil)
+}
+
+func (m *monitor) failed(_ context.Context, e *event.CommandFailedEvent) {
+	m.end(e.CommandFinishedEvent, e.Failure)
+}
+
+func (m *monitor) end(e event.CommandFinishedEvent, err error) {
+	key := spanKey{e.ConnectionID, e.RequestID}
+
+	m.mu.Lock()
+	span, ok := m.spans[key]
+	delete(m.spans, key)
+	m.mu.Unlock()
+
+	if !ok {
+		return
+	}
+
+	rotel.SetDuration(span, CommandDurationKey, e.Duration)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// collectionName returns the collection of commands like {find: "users"},
+// where the value of the command is the collection.
+func collectionName(command bson.Raw, name string) (string, bool) {
+	value, err := command.LookupErr(name)
+	if err != nil {
+		return "", false
+	}
+
+	return value.StringValueOK()
+}
@@ -0,0 +1,65 @@
+package otelmongo
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/v2/bson"
+	"go.mongodb.org/mongo-driver/v2/event"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+func started(t *testing.T, m *event.CommandMonitor, name string, command bson.D, requestID int64) {
+	t.Helper()
+
+	raw, err := bson.Marshal(command)
+	assert.Nil(t, err)
+	m.Started(context.TODO(), &event.CommandStartedEvent{
+		Command:      raw,
+		DatabaseName: "shop",
+		CommandName:  name,
+		RequestID:    requestID,
+		ConnectionID: "mongo:27017[-1]",
+	})
+}
+
+func finished(name string, requestID int64) event.CommandFinishedEvent {
+	return event.CommandFinishedEvent{
+		Duration:     25 * time.Millisecond,
+		CommandName:  name,
+		DatabaseName: "shop",
+		RequestID:    requestID,
+		ConnectionID: "mongo:27017[-1]",
+	}
+}
+
+func TestMonitor_RecordsCommands(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	m := NewMonitor(WithTracerProvider(trace.NewTracerProvider(trace.WithSpanProcessor(recorder))))
+
+	started(t, m, "find", bson.D{{Key: "find", Value: "users"}, {Key: "filter", Value: bson.D{}}}, 1)
+	started(t, m, "ping", bson.D{{Key: "ping", Value: 1}}, 2)
+	m.Failed(context.TODO(), &event.CommandFailedEvent{CommandFinishedEvent: finished("ping", 2), Failure: errors.New("timeout")})
+	m.Succeeded(context.TODO(), &event.CommandSucceededEvent{CommandFinishedEvent: finished("find", 1)})
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 2)
+
+	assert.Equal(t, "ping", spans[0].Name())
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+
+	assert.Equal(t, "users.find", spans[1].Name())
+	assert.Equal(t, oteltrace.SpanKindClient, spans[1].SpanKind())
+	assert.Contains(t, spans[1].Attributes(), attribute.String("db.system", "mongodb"))
+	assert.Contains(t, spans[1].Attributes(), attribute.String("db.name", "shop"))
+	assert.Contains(t, spans[1].Attributes(), attribute.String("db.operation", "find"))
+	assert.Contains(t, spans[1].Attributes(), attribute.String("db.mongodb.collection", "users"))
+	assert.Contains(t, spans[1].Attributes(), CommandDurationKey.Float64(25))
+}
@@ -0,0 +1,286 @@
+// Package otelopamp connects a pipeline to an OpAMP server, applying the
+// sampling, drop and batch settings it sends and reporting the pipeline
+// health back, so a control plane can manage the telemetry of a fleet:
+//
+//	client, err := otelopamp.Start(ctx, exporter, otelopamp.Config{ServerURL: "wss://opamp.example.com/v1/opamp"})
+//	if err != nil {
+//		...
+//	}
+//	defer client.Stop(ctx)
+package otelopamp
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/open-telemetry/opamp-go/client"
+	"github.com/open-telemetry/opamp-go/client/types"
+	"github.com/open-telemetry/opamp-go/protobufs"
+	rotel "github.com/rezazadehramin/opentelemetry-go/otel"
+	"go.opentelemetry.io/otel"
+	"go.yaml.in/yaml/v3"
+)
+
+// ConfigName is the name of the remote config file holding the
+// rotel.ReloadableConfig, a config map with a single file is also accepted.
+const ConfigName = "otel.yaml"
+
+// defaultHealthInterval is how often the health is reported by default.
+const defaultHealthInterval = 30 * time.Second
+
+// Config configures the OpAMP client.
+//
+// ServerURL is the OpAMP endpoint, ws:// and wss:// URLs connect with a
+// WebSocket and others poll it over HTTP. InstanceUID identifies the agent,
+// the service.instance.id of the pipeline resource when it's a UUID and a
+// random one otherwise. HealthInterval is how often the pipeline health is
+// reported, 30s when <= 0.
+type Config struct {
+	ServerURL      string
+	Header         http.Header
+	TLSConfig      *tls.Config
+	InstanceUID    string
+	HealthInterval time.Duration
+}
+
+// Client is an OpAMP client started by Start.
+type Client struct {
+	exporter rotel.Exporter
+	opamp    client.OpAMPClient
+	cancel   context.CancelFunc
+	done     chan struct{}
+
+	mu      sync.Mutex
+	applied *protobufs.AgentConfigMap
+}
+
+// Start connects to the OpAMP server of c on behalf of the pipeline built by e,
+// once ExportPipeline succeeded. Remote configs are applied with
+// rotel.ApplyConfig and their status reported, applied or failed.
+func Start(ctx context.Context, e rotel.Exporter, c Config) (*Client, error) {
+	snapshot, ok := rotel.Snapshot(e)
+	if !ok {
+		return nil, errors.New("unsupported exporter")
+	}
+	if !snapshot.Started {
+		return nil, errors.New("pipeline not started")
+	}
+	if c.ServerURL == "" {
+		return nil, errors.New("missing OpAMP server URL")
+	}
+	if c.HealthInterval <= 0 {
+		c.HealthInterval = defaultHealthInterval
+	}
+
+	uid, err := instanceUID(c.InstanceUID, snapshot.Resource)
+	if err != nil {
+		return nil, err
+	}
+
+	cl := &Client{exporter: e, done: make(chan struct{})}
+	if strings.HasPrefix(c.ServerURL, "ws://") || strings.HasPrefix(c.ServerURL, "wss://") {
+		cl.opamp = client.NewWebSocket(nil)
+	} else {
+		cl.opamp = client.NewHTTP(nil)
+	}
+
+	if err := cl.opamp.SetAgentDescription(agentDescription(snapshot.Resource)); err != nil {
+		return nil, fmt.Errorf("could not set agent description: %w", err)
+	}
+	if err := cl.opamp.SetHealth(cl.health()); err != nil {
+		return nil, fmt.Errorf("could not set health: %w", err)
+	}
+	capabilities := protobufs.AgentCapabilities_AgentCapabilities_ReportsStatus |
+		protobufs.AgentCapabilities_AgentCapabilities_AcceptsRemoteConfig |
+		protobufs.AgentCapabilities_AgentCapabilities_ReportsRemoteConfig |
+		protobufs.AgentCapabilities_AgentCapabilities_ReportsEffectiveConfig |
+		protobufs.AgentCapabilities_AgentCapabilities_ReportsHealth
+	if err := cl.opamp.SetCapabilities(&capabilities); err != nil {
+		return nil, fmt.Errorf("could not set capabilities: %w", err)
+	}
+
+	err = cl.opamp.Start(ctx, types.StartSettings{
+		OpAMPServerURL: c.ServerURL,
+		Header:         c.Header,
+		TLSConfig:      c.TLSConfig,
+		InstanceUid:    uid,
+		Callbacks: types.Callbacks{
+			OnConnectFailed: func(_ context.Context, err error) {
+				otel.Handle(fmt.Errorf("could not connect to OpAMP server %s: %w", c.ServerURL, err))
+			},
+			OnMessage:          cl.onMessage,
+			GetEffectiveConfig: cl.effectiveConfig,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not start OpAMP client: %w", err)
+	}
+
+	healthCtx, cancel := context.WithCancel(context.Background())
+	cl.cancel = cancel
+	go cl.reportHealth(healthCtx, c.HealthInterval)
+
+	return cl, nil
+}
+
+// Stop stops reporting the health and disconnects from the OpAMP server,
+// the last applied config stays in effect.
+func (c *Client) Stop(ctx context.Context) error {
+	c.cancel()
+	<-c.done
+
+	return c.opamp.Stop(ctx)
+}
+
+func (c *Client) onMessage(ctx context.Context, msg *types.MessageData) {
+	remote := msg.RemoteConfig
+	if remote == nil {
+		return
+	}
+
+	status := &protobufs.RemoteConfigStatus{
+		LastRemoteConfigHash: remote.ConfigHash,
+		Status:               protobufs.RemoteConfigStatuses_RemoteConfigStatuses_APPLIED,
+	}
+	if err := c.apply(ctx, remote.Config); err != nil {
+		otel.Handle(fmt.Errorf("could not apply OpAMP remote config: %w", err))
+		status.Status = protobufs.RemoteConfigStatuses_RemoteConfigStatuses_FAILED
+		status.ErrorMessage = err.Error()
+	}
+	if err := c.opamp.SetRemoteConfigStatus(status); err != nil {
+		otel.Handle(fmt.Errorf("could not report OpAMP remote config status: %w", err))
+	}
+	if status.Status == protobufs.RemoteConfigStatuses_RemoteConfigStatuses_APPLIED {
+		if err := c.opamp.UpdateEffectiveConfig(ctx); err != nil {
+			otel.Handle(fmt.Errorf("could not report OpAMP effective config: %w", err))
+		}
+	}
+	_ = c.opamp.SetHealth(c.health())
+}
+
+// apply applies the rotel.ReloadableConfig of the config map, an empty one
+// restoring the defaults.
+func (c *Client) apply(ctx context.Context, configMap *protobufs.AgentConfigMap) error {
+	var body []byte
+	if configMap != nil {
+		file, ok := configMap.ConfigMap[ConfigName]
+		if !ok && len(configMap.ConfigMap) == 1 {
+			for _, f := range configMap.ConfigMap {
+				file, ok = f, true
+			}
+		}
+		if !ok && len(configMap.ConfigMap) > 0 {
+			return fmt.Errorf("missing %s config file", ConfigName)
+		}
+		if file != nil {
+			body = file.Body
+		}
+	}
+
+	var config rotel.ReloadableConfig
+	if err := yaml.Unmarshal(body, &config); err != nil {
+		return fmt.Errorf("could not parse config: %w", err)
+	}
+	if err := rotel.ApplyConfig(ctx, c.exporter, config); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	c.applied = configMap
+	c.mu.Unlock()
+
+	return nil
+}
+
+func (c *Client) effectiveConfig(context.Context) (*protobufs.EffectiveConfig, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	configMap := c.applied
+	if configMap == nil {
+		configMap = &protobufs.AgentConfigMap{}
+	}
+
+	return &protobufs.EffectiveConfig{ConfigMap: configMap}, nil
+}
+
+func (c *Client) reportHealth(ctx context.Context, interval time.Duration) {
+	defer close(c.done)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if err := c.opamp.SetHealth(c.health()); err != nil {
+			otel.Handle(fmt.Errorf("could not report OpAMP health: %w", err))
+		}
+	}
+}
+
+// health converts the rotel.PipelineHealth of the pipeline, degraded and
+// not started pipelines being unhealthy.
+func (c *Client) health() *protobufs.ComponentHealth {
+	h, _ := rotel.Health(c.exporter)
+
+	return &protobufs.ComponentHealth{
+		Healthy:            h.Status == "ok" || h.Status == "disabled",
+		Status:             h.Status,
+		LastError:          h.LastError,
+		StatusTimeUnixNano: uint64(time.Now().UnixNano()),
+	}
+}
+
+// agentDescription identifies the agent with the service attributes of the
+// resource, the other ones describing where it runs.
+func agentDescription(resource map[string]string) *protobufs.AgentDescription {
+	description := &protobufs.AgentDescription{}
+	for _, key := range slices.Sorted(maps.Keys(resource)) {
+		kv := &protobufs.KeyValue{
+			Key:   key,
+			Value: &protobufs.AnyValue{Value: &protobufs.AnyValue_StringValue{StringValue: resource[key]}},
+		}
+		if strings.HasPrefix(key, "service.") {
+			description.IdentifyingAttributes = append(description.IdentifyingAttributes, kv)
+		} else {
+			description.NonIdentifyingAttributes = append(description.NonIdentifyingAttributes, kv)
+		}
+	}
+	if len(description.IdentifyingAttributes) == 0 {
+		description.IdentifyingAttributes = append(description.IdentifyingAttributes, &protobufs.KeyValue{
+			Key:   "service.name",
+			Value: &protobufs.AnyValue{Value: &protobufs.AnyValue_StringValue{StringValue: "unknown_service"}},
+		})
+	}
+
+	return description
+}
+
+func instanceUID(configured string, resource map[string]string) (types.InstanceUid, error) {
+	if configured != "" {
+		id, err := uuid.Parse(configured)
+		if err != nil {
+			return types.InstanceUid{}, fmt.Errorf("invalid instance UID %q: %w", configured, err)
+		}
+		return types.InstanceUid(id), nil
+	}
+	if id, err := uuid.Parse(resource["service.instance.id"]); err == nil {
+		return types.InstanceUid(id), nil
+	}
+
+	return types.InstanceUid(uuid.New()), nil
+}
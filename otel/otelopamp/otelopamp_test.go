@@ -0,0 +1,136 @@
+package otelopamp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/open-telemetry/opamp-go/protobufs"
+	"github.com/open-telemetry/opamp-go/server"
+	"github.com/open-telemetry/opamp-go/server/types"
+	rotel "github.com/rezazadehramin/opentelemetry-go/otel"
+	"github.com/stretchr/testify/assert"
+)
+
+// opampServer sends body as remote config to the agents connecting to it
+// and keeps the last messages they sent.
+type opampServer struct {
+	mu   sync.Mutex
+	last *protobufs.AgentToServer
+}
+
+func (s *opampServer) start(t *testing.T, body string) string {
+	handler, connContext, err := server.New(nil).Attach(server.Settings{
+		Callbacks: types.Callbacks{
+			OnConnecting: func(*http.Request) types.ConnectionResponse {
+				return types.ConnectionResponse{Accept: true, ConnectionCallbacks: types.ConnectionCallbacks{
+					OnMessage: func(_ context.Context, _ types.Connection, msg *protobufs.AgentToServer) *protobufs.ServerToAgent {
+						s.mu.Lock()
+						defer s.mu.Unlock()
+						sent := s.last != nil
+						s.last = msg
+						if sent {
+							return &protobufs.ServerToAgent{InstanceUid: msg.InstanceUid}
+						}
+
+						return &protobufs.ServerToAgent{
+							InstanceUid: msg.InstanceUid,
+							RemoteConfig: &protobufs.AgentRemoteConfig{
+								Config: &protobufs.AgentConfigMap{ConfigMap: map[string]*protobufs.AgentConfigFile{
+									ConfigName: {Body: []byte(body), ContentType: "text/yaml"},
+								}},
+								ConfigHash: []byte("v1"),
+							},
+						}
+					},
+				}}
+			},
+		},
+	})
+	assert.Nil(t, err)
+
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(handler))
+	srv.Config.ConnContext = connContext
+	srv.Start()
+	t.Cleanup(srv.Close)
+
+	return "ws" + strings.TrimPrefix(srv.URL, "http")
+}
+
+func (s *opampServer) remoteConfigStatus() *protobufs.RemoteConfigStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.last == nil {
+		return nil
+	}
+
+	return s.last.RemoteConfigStatus
+}
+
+func startPipeline(t *testing.T) rotel.Exporter {
+	exporter := rotel.NewExporter(rotel.Test, &rotel.Config{}, rotel.WithGlobalRegistration(false))
+	pipeline, err := exporter.ExportPipeline(context.TODO())
+	assert.Nil(t, err)
+	t.Cleanup(func() { _ = pipeline.Shutdown(context.TODO()) })
+
+	return exporter
+}
+
+func TestStart_AppliesRemoteConfig(t *testing.T) {
+	exporter := startPipeline(t)
+	s := &opampServer{}
+	url := s.start(t, "sampling_ratio: 0.25")
+
+	client, err := Start(context.TODO(), exporter, Config{ServerURL: url})
+	assert.Nil(t, err)
+	defer client.Stop(context.TODO())
+
+	assert.Eventually(t, func() bool {
+		status := s.remoteConfigStatus()
+		return status != nil && status.Status == protobufs.RemoteConfigStatuses_RemoteConfigStatuses_APPLIED
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(t, []byte("v1"), s.remoteConfigStatus().LastRemoteConfigHash)
+
+	snapshot, _ := rotel.Snapshot(exporter)
+	assert.Contains(t, snapshot.Sampler, "0.25")
+	config, _ := client.effectiveConfig(context.TODO())
+	assert.Equal(t, []byte("sampling_ratio: 0.25"), config.ConfigMap.ConfigMap[ConfigName].Body)
+}
+
+func TestStart_ReportsFailedRemoteConfig(t *testing.T) {
+	exporter := startPipeline(t)
+	s := &opampServer{}
+	url := s.start(t, "sampling_ratio: 2")
+
+	client, err := Start(context.TODO(), exporter, Config{ServerURL: url})
+	assert.Nil(t, err)
+	defer client.Stop(context.TODO())
+
+	assert.Eventually(t, func() bool {
+		status := s.remoteConfigStatus()
+		return status != nil && status.Status == protobufs.RemoteConfigStatuses_RemoteConfigStatuses_FAILED
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(t, "sampling_ratio must be between 0 and 1, got 2", s.remoteConfigStatus().ErrorMessage)
+}
+
+func TestStart_RequiresStartedPipeline(t *testing.T) {
+	exporter := rotel.NewExporter(rotel.Test, &rotel.Config{}, rotel.WithGlobalRegistration(false))
+
+	_, err := Start(context.TODO(), exporter, Config{ServerURL: "ws://localhost:4320/v1/opamp"})
+	assert.EqualError(t, err, "pipeline not started")
+}
+
+func TestInstanceUID_UsesServiceInstanceID(t *testing.T) {
+	id := "0191c7e4-7bd5-7a52-9c5e-3b1c4c1f8e2a"
+	uid, err := instanceUID("", map[string]string{"service.instance.id": id})
+	assert.Nil(t, err)
+	assert.Equal(t, id, uuid.UUID(uid).String())
+
+	_, err = instanceUID("not-a-uuid", nil)
+	assert.ErrorContains(t, err, `invalid instance UID "not-a-uuid"`)
+}
@@ -0,0 +1,251 @@
+// Package otelredis instruments go-redis clients with the tracer provider
+// set up by the otel package.
+package otelredis
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/rezazadehramin/opentelemetry-go/otel/otelredis"
+
+// NumCommandsKey is the number of commands sent in a pipeline.
+const NumCommandsKey = attribute.Key("db.redis.num_cmd")
+
+// Redaction controls the command arguments recorded in db.statement. The
+// arguments of the commands carrying credentials, AUTH, HELLO and MIGRATE
+// with AUTH, are never recorded.
+type Redaction int
+
+const (
+	// RedactValues records the command and its key, e.g. "set user:42 ?".
+	RedactValues Redaction = iota
+	// RedactArgs records the command only, e.g. "set".
+	RedactArgs
+	// RedactNone records the command and all its arguments.
+	RedactNone
+)
+
+// Option configures the hook.
+type Option func(*config)
+
+type config struct {
+	provider  trace.TracerProvider
+	redaction Redaction
+	attrs     []attribute.KeyValue
+}
+
+// WithTracerProvider sets the provider spans are created with,
+// the global one registered by the otel pipeline by default.
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(c *config) {
+		c.provider = provider
+	}
+}
+
+// WithRedaction sets the arguments recorded in db.statement, RedactValues by default.
+func WithRedaction(redaction Redaction) Option {
+	return func(c *config) {
+		c.redaction = redaction
+	}
+}
+
+// WithAttributes adds attributes to every span.
+func WithAttributes(attrs ...attribute.KeyValue) Option {
+	return func(c *config) {
+		c.attrs = append(c.attrs, attrs...)
+	}
+}
+
+// Instrument adds the hook to client, recording the server address and
+// database index of single node clients.
+func Instrument(client redis.UniversalClient, opts ...Option) {
+	if c, ok := client.(*redis.Client); ok {
+		opts = append([]Option{WithAttributes(clientAttributes(c.Options())...)}, opts...)
+	}
+
+	client.AddHook(NewHook(opts...))
+}
+
+func clientAttributes(o *redis.Options) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{semconv.DBRedisDBIndexKey.Int(o.DB)}
+
+	host, port, err := net.SplitHostPort(o.Addr)
+	if err != nil {
+		return attrs
+	}
+	attrs = append(attrs, semconv.NetPeerNameKey.String(host))
+	if p, err := strconv.Atoi(port); err == nil {
+		attrs = append(attrs, semconv.NetPeerPortKey.Int(p))
+	}
+
+	return attrs
+}
+
+// hook records a client span for every command or pipeline.
+type hook struct {
+	config
+	tracer trace.Tracer
+}
+
+var _ redis.Hook = (*hook)(nil)
+
+// NewHook returns a hook recording a client span for every command, named
+// after the command, and every pipeline with the db.system, db.operation and
+// redacted db.statement attributes. Add it with client.AddHook or Instrument.
+func NewHook(opts ...Option) redis.Hook {
+	c := config{provider: otel.GetTracerProvider()}
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	return &hook{config: c, tracer: c.provider.Tracer(instrumentationName, trace.WithSchemaURL(semconv.SchemaURL))}
+}
+
+// DialHook implements the redis.Hook interface.
+func (h *hook) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+// ProcessHook implements the redis.Hook interface.
+func (h *hook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		ctx, span := h.start(ctx, cmd.FullName(), h.statement(cmd))
+		err := next(ctx, cmd)
+		h.end(span, err)
+
+		return err
+	}
+}
+
+// ProcessPipelineHook implements the redis.Hook interface.
+func (h *hook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		statements := make([]string, len(cmds))
+		for i, cmd := range cmds {
+			statements[i] = h.statement(cmd)
+		}
+
+		ctx, span := h.start(ctx, "pipeline", strings.Join(statements, "\n"), NumCommandsKey.Int(len(cmds)))
+		err := next(ctx, cmds)
+		h.end(span, err)
+
+		return err
+	}
+}
+
+func (h *hook) start(ctx context.Context, operation, statement string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	attrs = append(attrs,
+		semconv.DBSystemRedis,
+		semconv.DBOperationKey.String(operation),
+		semconv.DBStatementKey.String(statement),
+	)
+
+	return h.tracer.Start(ctx, operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(append(attrs, h.attrs...)...),
+	)
+}
+
+func (h *hook) end(span trace.Span, err error) {
+	if err != nil && err != redis.Nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// statement returns the command with its arguments redacted.
+func (h *hook) statement(cmd redis.Cmder) string {
+	args := cmd.Args()
+	name := cmd.FullName()
+	// skip the command name, made of one or two arguments
+	prefix := len(strings.Fields(name))
+	if prefix > len(args) {
+		prefix = len(args)
+	}
+	args = args[prefix:]
+
+	switch {
+	case len(args) == 0:
+		return name
+	case authenticates(cmd):
+		return name + strings.Repeat(" ?", len(args))
+	case h.redaction == RedactArgs:
+		return name
+	case h.redaction == RedactNone:
+		return name + " " + join(args)
+	}
+
+	statement := name + " " + fmt.Sprint(args[0])
+	if len(args) > 1 {
+		statement += strings.Repeat(" ?", len(args)-1)
+	}
+
+	return statement
+}
+
+// authenticates reports whether the arguments of cmd carry credentials.
+func authenticates(cmd redis.Cmder) bool {
+	switch strings.ToLower(cmd.Name()) {
+	case "auth", "hello":
+		return true
+	case "migrate":
+		for _, arg := range cmd.Args() {
+			if s, ok := arg.(string); ok && (strings.EqualFold(s, "auth") || strings.EqualFold(s, "auth2")) {
+				return true
+			}
+		}
+	case "acl":
+		// ACL SETUSER rules adding or removing passwords and hashes
+		if !strings.EqualFold(subcommand(cmd), "setuser") {
+			return false
+		}
+		for _, arg := range cmd.Args() {
+			if s, ok := arg.(string); ok && s != "" && strings.ContainsRune("><#!", rune(s[0])) {
+				return true
+			}
+		}
+	case "config":
+		if !strings.EqualFold(subcommand(cmd), "set") {
+			return false
+		}
+		for _, arg := range cmd.Args() {
+			if s, ok := arg.(string); ok && (strings.EqualFold(s, "requirepass") || strings.EqualFold(s, "masterauth")) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// subcommand returns the second argument of cmd, empty when it's missing.
+func subcommand(cmd redis.Cmder) string {
+	if args := cmd.Args(); len(args) > 1 {
+		if s, ok := args[1].(string); ok {
+			return s
+		}
+	}
+
+	return ""
+}
+
+func join(args []interface{}) string {
+	parts := make([]string, len(args))
+	for i, arg := range args {
+		parts[i] = fmt.Sprint(arg)
+	}
+
+	return strings.Join(parts, " ")
+}
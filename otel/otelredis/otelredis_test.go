@@ -0,0 +1,112 @@
+package otelredis
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+func newTestHook(opts ...Option) (redis.Hook, *tracetest.SpanRecorder) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := trace.NewTracerProvider(trace.WithSpanProcessor(recorder))
+
+	return NewHook(append(opts, WithTracerProvider(tp))...), recorder
+}
+
+func process(h redis.Hook, cmd redis.Cmder, err error) error {
+	return h.ProcessHook(func(context.Context, redis.Cmder) error { return err })(context.TODO(), cmd)
+}
+
+func TestHook_RecordsCommands(t *testing.T) {
+	h, recorder := newTestHook()
+
+	assert.Nil(t, process(h, redis.NewStatusCmd(context.TODO(), "set", "user:42", "secret", "ex", 10), nil))
+	assert.Equal(t, redis.Nil, process(h, redis.NewStringCmd(context.TODO(), "get", "user:43"), redis.Nil))
+	assert.NotNil(t, process(h, redis.NewStringCmd(context.TODO(), "get", "user:44"), errors.New("connection reset")))
+
+	spans := recorder.Ended()
+	assert.Equal(t, "set", spans[0].Name())
+	assert.Equal(t, oteltrace.SpanKindClient, spans[0].SpanKind())
+	assert.Contains(t, spans[0].Attributes(), attribute.String("db.system", "redis"))
+	assert.Contains(t, spans[0].Attributes(), attribute.String("db.statement", "set user:42 ? ? ?"))
+	assert.Equal(t, codes.Unset, spans[1].Status().Code)
+	assert.Equal(t, codes.Error, spans[2].Status().Code)
+}
+
+func TestHook_Redaction(t *testing.T) {
+	cmd := redis.NewStringCmd(context.TODO(), "config", "get", "maxmemory")
+
+	h, recorder := newTestHook(WithRedaction(RedactNone))
+	process(h, cmd, nil)
+	assert.Contains(t, recorder.Ended()[0].Attributes(), attribute.String("db.statement", "config get maxmemory"))
+
+	h, recorder = newTestHook(WithRedaction(RedactArgs))
+	process(h, redis.NewStatusCmd(context.TODO(), "set", "user:42", "secret"), nil)
+	assert.Contains(t, recorder.Ended()[0].Attributes(), attribute.String("db.statement", "set"))
+}
+
+func TestHook_RedactsCredentials(t *testing.T) {
+	tests := []struct {
+		cmd       redis.Cmder
+		statement string
+	}{
+		{redis.NewStatusCmd(context.TODO(), "auth", "admin", "secret"), "auth ? ?"},
+		{redis.NewMapStringInterfaceCmd(context.TODO(), "hello", 3, "auth", "admin", "secret"), "hello ? ? ? ?"},
+		{redis.NewStatusCmd(context.TODO(), "migrate", "replica", 6379, "user:42", 0, 5000, "auth", "secret"), "migrate ? ? ? ? ? ? ?"},
+		{redis.NewStatusCmd(context.TODO(), "acl", "setuser", "admin", "on", ">secret", "~*"), "acl ? ? ? ? ?"},
+		{redis.NewStatusCmd(context.TODO(), "acl", "setuser", "admin", "#5e884898da28047151d0e56f8dc6292773603d0d6aabbdd62a11ef721d1542d8"), "acl ? ? ?"},
+		{redis.NewStatusCmd(context.TODO(), "config", "set", "requirepass", "secret"), "config ? ? ?"},
+		{redis.NewStatusCmd(context.TODO(), "CONFIG", "SET", "maxmemory", "1gb", "masterauth", "secret"), "config ? ? ? ? ?"},
+	}
+
+	for _, redaction := range []Redaction{RedactValues, RedactArgs, RedactNone} {
+		for _, test := range tests {
+			h, recorder := newTestHook(WithRedaction(redaction))
+			process(h, test.cmd, nil)
+			assert.Contains(t, recorder.Ended()[0].Attributes(), attribute.String("db.statement", test.statement))
+		}
+	}
+}
+
+func TestHook_KeepsNonCredentialArgs(t *testing.T) {
+	h, recorder := newTestHook(WithRedaction(RedactNone))
+	process(h, redis.NewStatusCmd(context.TODO(), "config", "set", "maxmemory", "1gb"), nil)
+	process(h, redis.NewStatusCmd(context.TODO(), "acl", "setuser", "reader", "on", "~cache:*", "+get"), nil)
+
+	spans := recorder.Ended()
+	assert.Contains(t, spans[0].Attributes(), attribute.String("db.statement", "config set maxmemory 1gb"))
+	assert.Contains(t, spans[1].Attributes(), attribute.String("db.statement", "acl setuser reader on ~cache:* +get"))
+}
+
+func TestHook_RecordsPipelines(t *testing.T) {
+	h, recorder := newTestHook()
+
+	cmds := []redis.Cmder{
+		redis.NewStatusCmd(context.TODO(), "set", "a", "1"),
+		redis.NewIntCmd(context.TODO(), "incr", "b"),
+	}
+	err := h.ProcessPipelineHook(func(context.Context, []redis.Cmder) error { return nil })(context.TODO(), cmds)
+	assert.Nil(t, err)
+
+	span := recorder.Ended()[0]
+	assert.Equal(t, "pipeline", span.Name())
+	assert.Contains(t, span.Attributes(), NumCommandsKey.Int(2))
+	assert.Contains(t, span.Attributes(), attribute.String("db.statement", "set a ?\nincr b"))
+}
+
+func TestInstrument_RecordsClientAttributes(t *testing.T) {
+	client := redis.NewClient(&redis.Options{Addr: "cache:6380", DB: 2})
+	defer client.Close()
+
+	assert.Contains(t, clientAttributes(client.Options()), attribute.String("net.peer.name", "cache"))
+	assert.Contains(t, clientAttributes(client.Options()), attribute.Int("db.redis.database_index", 2))
+	Instrument(client)
+}
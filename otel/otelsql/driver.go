@@ -0,0 +1,302 @@
+package otelsql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// dsnConnector opens connections of drivers not implementing driver.DriverContext.
+type dsnConnector struct {
+	driver driver.Driver
+	dsn    string
+}
+
+func (c dsnConnector) Connect(context.Context) (driver.Conn, error) {
+	return c.driver.Open(c.dsn)
+}
+
+func (c dsnConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+func newConnector(d driver.Driver, dsn string) (driver.Connector, error) {
+	if dc, ok := d.(driver.DriverContext); ok {
+		return dc.OpenConnector(dsn)
+	}
+
+	return dsnConnector{driver: d, dsn: dsn}, nil
+}
+
+// otelConnector wraps the connections of a connector.
+type otelConnector struct {
+	driver.Connector
+	driver driver.Driver
+	tracer *tracer
+}
+
+func (c *otelConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &otelConn{Conn: conn, tracer: c.tracer}, nil
+}
+
+func (c *otelConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+// otelConn records the statements executed on a connection, falling back
+// to the prepared statements when the driver doesn't support direct execution.
+//
+// The span of a direct execution skipped by the driver is handed over to the
+// statement prepared next by database/sql, recording the statement once.
+type otelConn struct {
+	driver.Conn
+	tracer *tracer
+
+	skippedQuery string
+	skipped      trace.Span
+}
+
+var (
+	_ driver.ExecerContext      = (*otelConn)(nil)
+	_ driver.QueryerContext     = (*otelConn)(nil)
+	_ driver.ConnPrepareContext = (*otelConn)(nil)
+	_ driver.ConnBeginTx        = (*otelConn)(nil)
+	_ driver.Pinger             = (*otelConn)(nil)
+	_ driver.SessionResetter    = (*otelConn)(nil)
+	_ driver.NamedValueChecker  = (*otelConn)(nil)
+	_ driver.Validator          = (*otelConn)(nil)
+)
+
+func (c *otelConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	execer, ok := c.Conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+
+	ctx, span := c.tracer.start(ctx, query)
+	result, err := execer.ExecContext(ctx, c.tracer.comment(ctx, query), args)
+	if err == driver.ErrSkip {
+		c.skip(query, span)
+		return nil, err
+	}
+	if err == nil {
+		if rows, rerr := result.RowsAffected(); rerr == nil {
+			span.SetAttributes(RowsAffectedKey.Int64(rows))
+		}
+	}
+	c.tracer.end(span, err)
+
+	return result, err
+}
+
+func (c *otelConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	queryer, ok := c.Conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+
+	ctx, span := c.tracer.start(ctx, query)
+	rows, err := queryer.QueryContext(ctx, c.tracer.comment(ctx, query), args)
+	if err == driver.ErrSkip {
+		c.skip(query, span)
+		return nil, err
+	}
+	c.tracer.end(span, err)
+
+	return rows, err
+}
+
+// skip keeps the span of the query skipped by the driver, ending the one
+// kept before that was never prepared.
+func (c *otelConn) skip(query string, span trace.Span) {
+	if c.skipped != nil {
+		c.skipped.End()
+	}
+	c.skippedQuery, c.skipped = query, span
+}
+
+func (c *otelConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	span := c.skipped
+	if span != nil && c.skippedQuery != query {
+		span.End()
+		span = nil
+	}
+	c.skippedQuery, c.skipped = "", nil
+
+	var stmt driver.Stmt
+	var err error
+	if preparer, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		stmt, err = preparer.PrepareContext(ctx, query)
+	} else {
+		stmt, err = c.Conn.Prepare(query)
+	}
+	if err != nil {
+		if span != nil {
+			c.tracer.end(span, err)
+		}
+		return nil, err
+	}
+
+	return &otelStmt{Stmt: stmt, query: query, tracer: c.tracer, span: span}, nil
+}
+
+func (c *otelConn) Prepare(query string) (driver.Stmt, error) {
+	return c.PrepareContext(context.Background(), query)
+}
+
+func (c *otelConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if beginner, ok := c.Conn.(driver.ConnBeginTx); ok {
+		return beginner.BeginTx(ctx, opts)
+	}
+
+	// Same as database/sql does for drivers without ConnBeginTx.
+	if opts.Isolation != driver.IsolationLevel(sql.LevelDefault) {
+		return nil, errors.New("sql: driver does not support non-default isolation level")
+	}
+	if opts.ReadOnly {
+		return nil, errors.New("sql: driver does not support read-only transactions")
+	}
+
+	return c.Conn.Begin()
+}
+
+func (c *otelConn) Ping(ctx context.Context) error {
+	if pinger, ok := c.Conn.(driver.Pinger); ok {
+		return pinger.Ping(ctx)
+	}
+
+	return nil
+}
+
+func (c *otelConn) ResetSession(ctx context.Context) error {
+	if resetter, ok := c.Conn.(driver.SessionResetter); ok {
+		return resetter.ResetSession(ctx)
+	}
+
+	return nil
+}
+
+func (c *otelConn) IsValid() bool {
+	if validator, ok := c.Conn.(driver.Validator); ok {
+		return validator.IsValid()
+	}
+
+	return true
+}
+
+func (c *otelConn) CheckNamedValue(nv *driver.NamedValue) error {
+	if checker, ok := c.Conn.(driver.NamedValueChecker); ok {
+		return checker.CheckNamedValue(nv)
+	}
+
+	return driver.ErrSkip
+}
+
+// otelStmt records the executions of a prepared statement, the first one
+// with the span of the skipped direct execution when set.
+type otelStmt struct {
+	driver.Stmt
+	query  string
+	tracer *tracer
+	span   trace.Span
+}
+
+var (
+	_ driver.StmtExecContext   = (*otelStmt)(nil)
+	_ driver.StmtQueryContext  = (*otelStmt)(nil)
+	_ driver.NamedValueChecker = (*otelStmt)(nil)
+)
+
+func (s *otelStmt) start(ctx context.Context) (context.Context, trace.Span) {
+	if span := s.span; span != nil {
+		s.span = nil
+		return trace.ContextWithSpan(ctx, span), span
+	}
+
+	return s.tracer.start(ctx, s.query)
+}
+
+func (s *otelStmt) Close() error {
+	if s.span != nil {
+		s.span.End()
+	}
+
+	return s.Stmt.Close()
+}
+
+func (s *otelStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	ctx, span := s.start(ctx)
+
+	var result driver.Result
+	var err error
+	if execer, ok := s.Stmt.(driver.StmtExecContext); ok {
+		result, err = execer.ExecContext(ctx, args)
+	} else {
+		var values []driver.Value
+		if values, err = namedValues(args); err == nil {
+			result, err = s.Stmt.Exec(values)
+		}
+	}
+	if err == nil {
+		if rows, rerr := result.RowsAffected(); rerr == nil {
+			span.SetAttributes(RowsAffectedKey.Int64(rows))
+		}
+	}
+	s.tracer.end(span, err)
+
+	return result, err
+}
+
+func (s *otelStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	ctx, span := s.start(ctx)
+
+	var rows driver.Rows
+	var err error
+	if queryer, ok := s.Stmt.(driver.StmtQueryContext); ok {
+		rows, err = queryer.QueryContext(ctx, args)
+	} else {
+		var values []driver.Value
+		if values, err = namedValues(args); err == nil {
+			rows, err = s.Stmt.Query(values)
+		}
+	}
+	s.tracer.end(span, err)
+
+	return rows, err
+}
+
+func (s *otelStmt) CheckNamedValue(nv *driver.NamedValue) error {
+	switch stmt := s.Stmt.(type) {
+	case driver.NamedValueChecker:
+		return stmt.CheckNamedValue(nv)
+	case driver.ColumnConverter:
+		if s.NumInput() < 0 || nv.Ordinal > s.NumInput() {
+			return driver.ErrSkip
+		}
+		var err error
+		nv.Value, err = stmt.ColumnConverter(nv.Ordinal - 1).ConvertValue(nv.Value)
+		return err
+	}
+
+	return driver.ErrSkip
+}
+
+func namedValues(args []driver.NamedValue) ([]driver.Value, error) {
+	values := make([]driver.Value, len(args))
+	for i, arg := range args {
+		if arg.Name != "" {
+			return nil, errors.New("driver does not support named parameters")
+		}
+		values[i] = arg.Value
+	}
+
+	return values, nil
+}
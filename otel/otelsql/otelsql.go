@@ -0,0 +1,198 @@
+// Package otelsql instruments database/sql drivers with the tracer provider
+// set up by the otel package.
+package otelsql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"unicode"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	instrumentationName = "github.com/rezazadehramin/opentelemetry-go/otel/otelsql"
+
+	// defaultMaxStatementLength is the db.statement length statements
+	// are truncated to by default.
+	defaultMaxStatementLength = 1024
+)
+
+// RowsAffectedKey is the number of rows affected by an exec statement.
+const RowsAffectedKey = attribute.Key("db.sql.rows_affected")
+
+// Option configures the instrumented driver.
+type Option func(*config)
+
+type config struct {
+	provider           trace.TracerProvider
+	system             string
+	maxStatementLength int
+	traceComments      bool
+}
+
+// WithTracerProvider sets the provider spans are created with,
+// the global one registered by the otel pipeline by default.
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(c *config) {
+		c.provider = provider
+	}
+}
+
+// WithDBSystem sets the db.system attribute, the driver name by default.
+func WithDBSystem(system string) Option {
+	return func(c *config) {
+		c.system = system
+	}
+}
+
+// WithMaxStatementLength sets the length db.statement is truncated to,
+// 1024 by default, a negative length omits the statement.
+func WithMaxStatementLength(length int) Option {
+	return func(c *config) {
+		c.maxStatementLength = length
+	}
+}
+
+// WithTraceComments appends the W3C trace context of the statement span as an
+// sqlcommenter comment, e.g. /*traceparent='00-...-01'*/, so database logs
+// can be correlated with traces.
+//
+// Only statements executed directly are commented, prepared statements
+// are left as is since they outlive the trace they were prepared in, and
+// so are statements already holding a comment.
+func WithTraceComments(enabled bool) Option {
+	return func(c *config) {
+		c.traceComments = enabled
+	}
+}
+
+// Open opens a database like sql.Open with its driver wrapped to start
+// a client span for every statement executed.
+//
+// Spans are named after the statement operation, e.g. SELECT, carry the
+// db.system, db.operation and truncated db.statement attributes, and the
+// rows affected by exec statements. Query spans end when the query returns,
+// scanning rows is not included.
+func Open(driverName, dsn string, opts ...Option) (*sql.DB, error) {
+	db, err := sql.Open(driverName, dsn)
+	if err != nil {
+		return nil, err
+	}
+	d := db.Driver()
+	if err := db.Close(); err != nil {
+		return nil, err
+	}
+
+	c := config{
+		system:             driverName,
+		maxStatementLength: defaultMaxStatementLength,
+	}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	if c.provider == nil {
+		c.provider = otel.GetTracerProvider()
+	}
+
+	connector, err := newConnector(d, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("could not create connector: %w", err)
+	}
+
+	return sql.OpenDB(&otelConnector{
+		Connector: connector,
+		driver:    d,
+		tracer:    &tracer{config: c, tracer: c.provider.Tracer(instrumentationName, trace.WithSchemaURL(semconv.SchemaURL))},
+	}), nil
+}
+
+// tracer starts and ends the spans of the statements.
+type tracer struct {
+	config
+	tracer trace.Tracer
+}
+
+func (t *tracer) start(ctx context.Context, query string) (context.Context, trace.Span) {
+	var operation string
+	if fields := strings.Fields(query); len(fields) > 0 {
+		operation = strings.ToUpper(fields[0])
+	}
+	name := operation
+	if name == "" {
+		name = "sql"
+	}
+
+	attrs := []attribute.KeyValue{
+		semconv.DBSystemKey.String(t.system),
+	}
+	if operation != "" {
+		attrs = append(attrs, semconv.DBOperationKey.String(operation))
+	}
+	if t.maxStatementLength >= 0 {
+		attrs = append(attrs, semconv.DBStatementKey.String(truncate(query, t.maxStatementLength)))
+	}
+
+	return t.tracer.Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...),
+	)
+}
+
+func (t *tracer) end(span trace.Span, err error) {
+	if err != nil && err != driver.ErrSkip {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// comment appends the trace context of ctx to query when enabled.
+func (t *tracer) comment(ctx context.Context, query string) string {
+	if !t.traceComments || strings.Contains(query, "/*") {
+		return query
+	}
+
+	carrier := propagation.MapCarrier{}
+	propagation.TraceContext{}.Inject(ctx, carrier)
+	if len(carrier) == 0 {
+		return query
+	}
+
+	keys := carrier.Keys()
+	sort.Strings(keys)
+	pairs := make([]string, len(keys))
+	for i, key := range keys {
+		pairs[i] = fmt.Sprintf("%s='%s'", url.QueryEscape(key), url.QueryEscape(carrier[key]))
+	}
+
+	// the comment goes before the statement terminator, as sqlcommenter does
+	statement := strings.TrimRightFunc(query, unicode.IsSpace)
+	if trimmed, ok := strings.CutSuffix(statement, ";"); ok {
+		return fmt.Sprintf("%s /*%s*/;", trimmed, strings.Join(pairs, ","))
+	}
+
+	return fmt.Sprintf("%s /*%s*/", query, strings.Join(pairs, ","))
+}
+
+func truncate(s string, length int) string {
+	if len(s) <= length {
+		return s
+	}
+
+	// avoid cutting a multi-byte character in half
+	for length > 0 && length < len(s) && s[length]&0xC0 == 0x80 {
+		length--
+	}
+	return s[:length]
+}
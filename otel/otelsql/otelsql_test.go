@@ -0,0 +1,180 @@
+package otelsql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+// fakeDriver executes statements directly, except the ones starting with
+// "prepared" that only support the prepared statement path.
+type fakeDriver struct{}
+
+func (fakeDriver) Open(string) (driver.Conn, error) {
+	return fakeConn{}, nil
+}
+
+type fakeConn struct{}
+
+func (fakeConn) Prepare(query string) (driver.Stmt, error) { return fakeStmt{}, nil }
+func (fakeConn) Close() error                              { return nil }
+func (fakeConn) Begin() (driver.Tx, error)                 { return nil, errors.New("unsupported") }
+
+func (fakeConn) ExecContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Result, error) {
+	if strings.HasPrefix(query, "prepared") {
+		return nil, driver.ErrSkip
+	}
+	if strings.Contains(query, "missing") {
+		return nil, errors.New("no such table: missing")
+	}
+	return driver.RowsAffected(3), nil
+}
+
+// lastQuery is the last statement queried on a fakeConn.
+var lastQuery string
+
+func (fakeConn) QueryContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Rows, error) {
+	lastQuery = query
+	return fakeRows{}, nil
+}
+
+type fakeStmt struct{}
+
+func (fakeStmt) Close() error                               { return nil }
+func (fakeStmt) NumInput() int                              { return -1 }
+func (fakeStmt) Exec([]driver.Value) (driver.Result, error) { return driver.RowsAffected(1), nil }
+func (fakeStmt) Query([]driver.Value) (driver.Rows, error)  { return fakeRows{}, nil }
+
+type fakeRows struct{}
+
+func (fakeRows) Columns() []string         { return []string{"id"} }
+func (fakeRows) Close() error              { return nil }
+func (fakeRows) Next([]driver.Value) error { return io.EOF }
+
+func init() {
+	sql.Register("otelsql-fake", fakeDriver{})
+}
+
+func open(t *testing.T, opts ...Option) (*sql.DB, *tracetest.SpanRecorder) {
+	t.Helper()
+
+	recorder := tracetest.NewSpanRecorder()
+	db, err := Open("otelsql-fake", "", append(opts,
+		WithTracerProvider(trace.NewTracerProvider(trace.WithSpanProcessor(recorder))))...)
+	assert.Nil(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	return db, recorder
+}
+
+func TestOpen_RecordsStatements(t *testing.T) {
+	db, recorder := open(t, WithDBSystem("sqlite"))
+
+	_, err := db.ExecContext(context.TODO(), "update users set active = 1")
+	assert.Nil(t, err)
+	rows, err := db.QueryContext(context.TODO(), "SELECT id FROM users")
+	assert.Nil(t, err)
+	rows.Close()
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 2)
+	assert.Equal(t, "UPDATE", spans[0].Name())
+	assert.Equal(t, oteltrace.SpanKindClient, spans[0].SpanKind())
+	assert.Contains(t, spans[0].Attributes(), attribute.String("db.system", "sqlite"))
+	assert.Contains(t, spans[0].Attributes(), attribute.String("db.statement", "update users set active = 1"))
+	assert.Contains(t, spans[0].Attributes(), RowsAffectedKey.Int64(3))
+	assert.Equal(t, "SELECT", spans[1].Name())
+}
+
+func TestOpen_FallsBackToPreparedStatements(t *testing.T) {
+	db, recorder := open(t)
+
+	_, err := db.Exec("prepared insert", 1)
+	assert.Nil(t, err)
+
+	// the skipped direct execution and the prepared one are a single span
+	spans := recorder.Ended()
+	assert.Len(t, spans, 1)
+	assert.Equal(t, codes.Unset, spans[0].Status().Code)
+	assert.Equal(t, "PREPARED", spans[0].Name())
+	assert.Contains(t, spans[0].Attributes(), attribute.String("db.system", "otelsql-fake"))
+	assert.Contains(t, spans[0].Attributes(), RowsAffectedKey.Int64(1))
+}
+
+func TestOpen_TruncatesStatementsAndRecordsErrors(t *testing.T) {
+	db, recorder := open(t, WithMaxStatementLength(11))
+
+	_, err := db.Exec("DELETE FROM missing")
+	assert.NotNil(t, err)
+
+	spans := recorder.Ended()
+	assert.Contains(t, spans[0].Attributes(), attribute.String("db.statement", "DELETE FROM"))
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+}
+
+func TestOpen_TraceComments(t *testing.T) {
+	db, recorder := open(t, WithTraceComments(true))
+
+	rows, err := db.QueryContext(context.TODO(), "SELECT id FROM users")
+	assert.Nil(t, err)
+	rows.Close()
+
+	span := recorder.Ended()[0]
+	traceparent := fmt.Sprintf("00-%s-%s-01", span.SpanContext().TraceID(), span.SpanContext().SpanID())
+	assert.Equal(t, "SELECT id FROM users /*traceparent='"+traceparent+"'*/", lastQuery)
+	assert.Contains(t, span.Attributes(), attribute.String("db.statement", "SELECT id FROM users"))
+
+	rows, err = db.QueryContext(context.TODO(), "SELECT /* hint */ id FROM users")
+	assert.Nil(t, err)
+	rows.Close()
+	assert.Equal(t, "SELECT /* hint */ id FROM users", lastQuery)
+
+	rows, err = db.QueryContext(context.TODO(), "SELECT id FROM users; ")
+	assert.Nil(t, err)
+	rows.Close()
+	assert.Regexp(t, `^SELECT id FROM users /\*traceparent='[^']+'\*/;$`, lastQuery)
+}
+
+func TestOpen_NamesMultilineStatements(t *testing.T) {
+	db, recorder := open(t)
+
+	_, err := db.Exec("\n\tupdate\tusers set active = 1")
+	assert.Nil(t, err)
+
+	assert.Equal(t, "UPDATE", recorder.Ended()[0].Name())
+}
+
+func TestOpen_RejectsTxOptionsWithoutBeginTx(t *testing.T) {
+	db, _ := open(t)
+
+	_, err := db.BeginTx(context.TODO(), &sql.TxOptions{ReadOnly: true})
+	assert.EqualError(t, err, "sql: driver does not support read-only transactions")
+	_, err = db.BeginTx(context.TODO(), &sql.TxOptions{Isolation: sql.LevelSerializable})
+	assert.EqualError(t, err, "sql: driver does not support non-default isolation level")
+	_, err = db.BeginTx(context.TODO(), nil)
+	assert.EqualError(t, err, "unsupported")
+}
+
+// invalidConn is a fakeConn reporting itself as not reusable.
+type invalidConn struct {
+	fakeConn
+}
+
+func (invalidConn) IsValid() bool { return false }
+
+func TestOtelConn_ForwardsIsValid(t *testing.T) {
+	assert.True(t, (&otelConn{Conn: fakeConn{}}).IsValid())
+	assert.False(t, (&otelConn{Conn: invalidConn{}}).IsValid())
+}
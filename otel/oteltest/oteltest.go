@@ -0,0 +1,188 @@
+// Package oteltest provides assertions on the spans recorded by the Test
+// output of the otel package.
+package oteltest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rezazadehramin/opentelemetry-go/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+// DefaultTimeout is how long RequireSpan waits for a matching span by default.
+const DefaultTimeout = time.Second
+
+// pollInterval is how often the recorded spans are checked while waiting.
+const pollInterval = 10 * time.Millisecond
+
+// Option adds a condition on the spans to match, or changes how long to wait for them.
+type Option func(*query)
+
+type condition struct {
+	desc  string
+	match func(tracetest.SpanStub) bool
+}
+
+type query struct {
+	conditions []condition
+	timeout    time.Duration
+}
+
+func (q *query) where(desc string, match func(tracetest.SpanStub) bool) {
+	q.conditions = append(q.conditions, condition{desc: desc, match: match})
+}
+
+func (q *query) match(s tracetest.SpanStub) bool {
+	for _, c := range q.conditions {
+		if !c.match(s) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (q *query) String() string {
+	if len(q.conditions) == 0 {
+		return "any span"
+	}
+	desc := make([]string, len(q.conditions))
+	for i, c := range q.conditions {
+		desc[i] = c.desc
+	}
+
+	return strings.Join(desc, ", ")
+}
+
+func newQuery(opts []Option) *query {
+	q := &query{timeout: DefaultTimeout}
+	for _, opt := range opts {
+		opt(q)
+	}
+
+	return q
+}
+
+// WithName matches the spans of this name.
+func WithName(name string) Option {
+	return func(q *query) {
+		q.where(fmt.Sprintf("name %q", name), func(s tracetest.SpanStub) bool {
+			return s.Name == name
+		})
+	}
+}
+
+// WithAttribute matches the spans having the attribute kv.
+func WithAttribute(kv attribute.KeyValue) Option {
+	return func(q *query) {
+		q.where(fmt.Sprintf("attribute %s=%s", kv.Key, kv.Value.Emit()), func(s tracetest.SpanStub) bool {
+			for _, attr := range s.Attributes {
+				if attr.Key == kv.Key && attr.Value == kv.Value {
+					return true
+				}
+			}
+			return false
+		})
+	}
+}
+
+// WithStatus matches the spans of this status code.
+func WithStatus(code codes.Code) Option {
+	return func(q *query) {
+		q.where("status "+code.String(), func(s tracetest.SpanStub) bool {
+			return s.Status.Code == code
+		})
+	}
+}
+
+// WithKind matches the spans of this kind.
+func WithKind(kind oteltrace.SpanKind) Option {
+	return func(q *query) {
+		q.where("kind "+kind.String(), func(s tracetest.SpanStub) bool {
+			return s.SpanKind == kind
+		})
+	}
+}
+
+// WithParent matches the children of the span parent.
+func WithParent(parent tracetest.SpanStub) Option {
+	return func(q *query) {
+		id := parent.SpanContext.SpanID()
+		q.where("parent "+id.String(), func(s tracetest.SpanStub) bool {
+			return s.Parent.SpanID() == id
+		})
+	}
+}
+
+// WithTimeout sets how long RequireSpan waits for spans ended asynchronously,
+// 0 checks the spans recorded so far only.
+func WithTimeout(d time.Duration) Option {
+	return func(q *query) {
+		q.timeout = d
+	}
+}
+
+// FindSpans returns the spans recorded so far matching every option, in their end order.
+func FindSpans(recorder *otel.SpanRecorder, opts ...Option) tracetest.SpanStubs {
+	return newQuery(opts).find(recorder)
+}
+
+func (q *query) find(recorder *otel.SpanRecorder) tracetest.SpanStubs {
+	var found tracetest.SpanStubs
+	for _, s := range recorder.Spans() {
+		if q.match(s) {
+			found = append(found, s)
+		}
+	}
+
+	return found
+}
+
+// RequireSpan returns the first span recorded matching every option, waiting
+// for it up to DefaultTimeout or WithTimeout, and fails the test otherwise:
+//
+//	span := oteltest.RequireSpan(t, recorder,
+//		oteltest.WithName("GET /orders"),
+//		oteltest.WithAttribute(semconv.HTTPStatusCodeKey.Int(500)),
+//		oteltest.WithStatus(codes.Error))
+func RequireSpan(t testing.TB, recorder *otel.SpanRecorder, opts ...Option) tracetest.SpanStub {
+	t.Helper()
+
+	q := newQuery(opts)
+	deadline := time.Now().Add(q.timeout)
+	for {
+		if found := q.find(recorder); len(found) > 0 {
+			return found[0]
+		}
+		if !time.Now().Before(deadline) {
+			break
+		}
+		time.Sleep(pollInterval)
+	}
+
+	t.Fatalf("no span with %s in %s, recorded spans:%s", q, q.timeout, describe(recorder.Spans()))
+	return tracetest.SpanStub{}
+}
+
+// describe lists the spans in the failure messages of RequireSpan.
+func describe(spans tracetest.SpanStubs) string {
+	if len(spans) == 0 {
+		return " none"
+	}
+
+	var b strings.Builder
+	for _, s := range spans {
+		fmt.Fprintf(&b, "\n\t%q %s %s", s.Name, s.SpanKind, s.Status.Code)
+		for _, kv := range s.Attributes {
+			fmt.Fprintf(&b, " %s=%s", kv.Key, kv.Value.Emit())
+		}
+	}
+
+	return b.String()
+}
@@ -0,0 +1,85 @@
+package oteltest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/rezazadehramin/opentelemetry-go/otel"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+// fakeT records the failure of RequireSpan instead of stopping the test.
+type fakeT struct {
+	testing.TB
+	failure string
+}
+
+func (t *fakeT) Helper() {}
+
+func (t *fakeT) Fatalf(format string, args ...interface{}) {
+	t.failure = fmt.Sprintf(format, args...)
+}
+
+func newRecorder(t *testing.T) (*otel.SpanRecorder, oteltrace.Tracer) {
+	exporter := otel.NewExporter(otel.Test, &otel.Config{ServiceName: "orders"}, otel.WithGlobalRegistration(false))
+	recorder, _ := otel.Recorder(exporter)
+	provider, err := exporter.ExportPipeline(context.TODO())
+	assert.Nil(t, err)
+	t.Cleanup(func() { _ = provider.Shutdown(context.TODO()) })
+
+	return recorder, provider.Tracer("sample")
+}
+
+func TestRequireSpan_MatchesEveryOption(t *testing.T) {
+	recorder, tracer := newRecorder(t)
+
+	ctx, parent := tracer.Start(context.TODO(), "GET /orders", oteltrace.WithSpanKind(oteltrace.SpanKindServer))
+	_, child := tracer.Start(ctx, "SELECT orders", oteltrace.WithAttributes(attribute.String("db.system", "postgresql")))
+	child.SetStatus(codes.Error, "timeout")
+	child.End()
+	_, other := tracer.Start(ctx, "SELECT orders")
+	other.End()
+	parent.End()
+
+	server := RequireSpan(t, recorder, WithName("GET /orders"), WithKind(oteltrace.SpanKindServer))
+	span := RequireSpan(t, recorder,
+		WithName("SELECT orders"),
+		WithAttribute(attribute.String("db.system", "postgresql")),
+		WithStatus(codes.Error),
+		WithParent(server))
+	assert.Equal(t, "timeout", span.Status.Description)
+	assert.Len(t, FindSpans(recorder, WithName("SELECT orders")), 2)
+	assert.Len(t, FindSpans(recorder, WithParent(server), WithStatus(codes.Unset)), 1)
+}
+
+func TestRequireSpan_WaitsForAsyncSpans(t *testing.T) {
+	recorder, tracer := newRecorder(t)
+
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		_, span := tracer.Start(context.TODO(), "consume order")
+		span.End()
+	}()
+
+	span := RequireSpan(t, recorder, WithName("consume order"))
+	assert.Equal(t, "consume order", span.Name)
+}
+
+func TestRequireSpan_FailsListingRecordedSpans(t *testing.T) {
+	recorder, tracer := newRecorder(t)
+	_, span := tracer.Start(context.TODO(), "GET /orders", oteltrace.WithAttributes(attribute.Int("http.status_code", 200)))
+	span.End()
+
+	ft := &fakeT{}
+	start := time.Now()
+	RequireSpan(ft, recorder, WithName("GET /orders"), WithStatus(codes.Error), WithTimeout(20*time.Millisecond))
+
+	assert.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond)
+	assert.Contains(t, ft.failure, `no span with name "GET /orders", status Error in 20ms`)
+	assert.Contains(t, ft.failure, `"GET /orders" internal Unset http.status_code=200`)
+}
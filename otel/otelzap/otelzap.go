@@ -0,0 +1,126 @@
+// Package otelzap correlates zap log entries with the traces of the otel
+// package, adding the same trace_id and span_id fields as otel.NewSlogHandler,
+// and forwards them to the logs pipeline with NewBridgeCore.
+package otelzap
+
+import (
+	"context"
+
+	rotel "github.com/rezazadehramin/opentelemetry-go/otel"
+	zapbridge "go.opentelemetry.io/contrib/bridges/otelzap"
+	"go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// contextKey is the key of the field carrying a context.
+const contextKey = "otelzap.context"
+
+const instrumentationName = "github.com/rezazadehramin/opentelemetry-go/otel/otelzap"
+
+// Context returns a field carrying ctx, replaced by the trace_id and span_id
+// of its span by the cores created with NewCore:
+//
+//	logger := zap.New(otelzap.NewCore(core))
+//	logger.Info("order shipped", otelzap.Context(ctx))
+//
+// The field is skipped by encoders of other cores.
+func Context(ctx context.Context) zap.Field {
+	return zap.Field{Key: contextKey, Type: zapcore.SkipType, Interface: ctx}
+}
+
+// Fields returns the trace_id and span_id fields of the span found in ctx,
+// none when ctx holds no valid span.
+func Fields(ctx context.Context) []zap.Field {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return nil
+	}
+
+	return []zap.Field{
+		zap.String(rotel.LogTraceIDKey, sc.TraceID().String()),
+		zap.String(rotel.LogSpanIDKey, sc.SpanID().String()),
+	}
+}
+
+type core struct {
+	zapcore.Core
+}
+
+// NewCore wraps next to replace the Context fields of entries
+// by the trace_id and span_id of their span.
+func NewCore(next zapcore.Core) zapcore.Core {
+	return &core{Core: next}
+}
+
+// With implements the zapcore.Core interface.
+func (c *core) With(fields []zapcore.Field) zapcore.Core {
+	return &core{Core: c.Core.With(traceFields(fields))}
+}
+
+// Check implements the zapcore.Core interface, next deciding whether entry
+// is written so its sampling and the levels of the cores of a tee apply.
+func (c *core) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	accepted := c.Core.Check(entry, nil)
+	if accepted == nil {
+		return checked
+	}
+
+	return checked.AddCore(entry, checkedCore{core: c, accepted: accepted})
+}
+
+// checkedCore writes an entry to the cores of next which accepted it.
+type checkedCore struct {
+	*core
+	accepted *zapcore.CheckedEntry
+}
+
+// Write implements the zapcore.Core interface.
+func (c checkedCore) Write(_ zapcore.Entry, fields []zapcore.Field) error {
+	c.accepted.Write(traceFields(fields)...)
+	return nil
+}
+
+// Write implements the zapcore.Core interface.
+func (c *core) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	return c.Core.Write(entry, traceFields(fields))
+}
+
+func traceFields(fields []zapcore.Field) []zapcore.Field {
+	var replaced []zapcore.Field
+	for i, f := range fields {
+		ctx, ok := f.Interface.(context.Context)
+		if f.Key != contextKey || f.Type != zapcore.SkipType || !ok {
+			if replaced != nil {
+				replaced = append(replaced, f)
+			}
+			continue
+		}
+
+		if replaced == nil {
+			replaced = append(make([]zapcore.Field, 0, len(fields)+1), fields[:i]...)
+		}
+		replaced = append(replaced, Fields(ctx)...)
+	}
+	if replaced == nil {
+		return fields
+	}
+
+	return replaced
+}
+
+// NewBridgeCore returns a core forwarding entries as log signals through
+// provider, the global one registered by otel.ExportLogPipeline when nil.
+// Entries logged with a Context field carry the trace and span of its span,
+// tee it with the application core rather than wrapping it with NewCore:
+//
+//	logger := zap.New(zapcore.NewTee(otelzap.NewCore(core), otelzap.NewBridgeCore(nil)))
+func NewBridgeCore(provider log.LoggerProvider) zapcore.Core {
+	var opts []zapbridge.Option
+	if provider != nil {
+		opts = append(opts, zapbridge.WithLoggerProvider(provider))
+	}
+
+	return zapbridge.NewCore(instrumentationName, opts...)
+}
@@ -0,0 +1,85 @@
+package otelzap
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	rotel "github.com/rezazadehramin/opentelemetry-go/otel"
+	"github.com/stretchr/testify/assert"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	"go.opentelemetry.io/otel/sdk/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestCore_ReplacesContextField(t *testing.T) {
+	observed, logs := observer.New(zap.InfoLevel)
+	logger := zap.New(NewCore(observed)).With(zap.String("service", "orders"))
+
+	ctx, span := trace.NewTracerProvider().Tracer("sample").Start(context.TODO(), "sample span")
+	defer span.End()
+	logger.Info("order shipped", Context(ctx), zap.Int("lines", 3))
+	logger.Info("order shipped", Context(context.TODO()))
+
+	entries := logs.All()
+	assert.Len(t, entries, 2)
+	fields := entries[0].ContextMap()
+	assert.Equal(t, "orders", fields["service"])
+	assert.Equal(t, int64(3), fields["lines"])
+	assert.Equal(t, span.SpanContext().TraceID().String(), fields[rotel.LogTraceIDKey])
+	assert.Equal(t, span.SpanContext().SpanID().String(), fields[rotel.LogSpanIDKey])
+	assert.Equal(t, map[string]interface{}{"service": "orders"}, entries[1].ContextMap())
+}
+
+func TestCore_KeepsNextSampling(t *testing.T) {
+	observed, logs := observer.New(zap.InfoLevel)
+	logger := zap.New(NewCore(zapcore.NewSamplerWithOptions(observed, time.Minute, 2, 0)))
+
+	for range 5 {
+		logger.Info("order shipped", Context(context.TODO()))
+	}
+
+	assert.Equal(t, 2, logs.Len())
+}
+
+func TestCore_KeepsTeeLevels(t *testing.T) {
+	info, infoLogs := observer.New(zap.InfoLevel)
+	errs, errorLogs := observer.New(zap.ErrorLevel)
+	logger := zap.New(NewCore(zapcore.NewTee(info, errs)))
+
+	logger.Info("order shipped")
+	logger.Error("order lost")
+
+	assert.Equal(t, 2, infoLogs.Len())
+	assert.Equal(t, 1, errorLogs.Len())
+	assert.Equal(t, "order lost", errorLogs.All()[0].Message)
+}
+
+type recordingExporter struct {
+	records []sdklog.Record
+}
+
+func (e *recordingExporter) Export(_ context.Context, records []sdklog.Record) error {
+	for _, r := range records {
+		e.records = append(e.records, r.Clone())
+	}
+	return nil
+}
+
+func (e *recordingExporter) Shutdown(context.Context) error   { return nil }
+func (e *recordingExporter) ForceFlush(context.Context) error { return nil }
+
+func TestBridgeCore_ForwardsEntries(t *testing.T) {
+	exporter := &recordingExporter{}
+	provider := sdklog.NewLoggerProvider(sdklog.WithProcessor(sdklog.NewSimpleProcessor(exporter)))
+
+	ctx, span := trace.NewTracerProvider().Tracer("sample").Start(context.TODO(), "sample span")
+	defer span.End()
+	zap.New(NewBridgeCore(provider)).Info("order shipped", Context(ctx))
+
+	assert.Len(t, exporter.records, 1)
+	assert.Equal(t, "order shipped", exporter.records[0].Body().AsString())
+	assert.Equal(t, span.SpanContext().TraceID(), exporter.records[0].TraceID())
+}
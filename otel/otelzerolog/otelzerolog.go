@@ -0,0 +1,27 @@
+// Package otelzerolog correlates zerolog events with the traces of the otel
+// package, adding the same trace_id and span_id fields as otel.NewSlogHandler.
+package otelzerolog
+
+import (
+	rotel "github.com/rezazadehramin/opentelemetry-go/otel"
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Hook adds the trace_id and span_id of the span found in the context of
+// events, set with Event.Ctx or Context.Ctx:
+//
+//	logger := zerolog.New(os.Stdout).Hook(otelzerolog.Hook{})
+//	logger.Info().Ctx(ctx).Msg("order shipped")
+type Hook struct{}
+
+// Run implements the zerolog.Hook interface.
+func (Hook) Run(e *zerolog.Event, _ zerolog.Level, _ string) {
+	sc := trace.SpanContextFromContext(e.GetCtx())
+	if !sc.IsValid() {
+		return
+	}
+
+	e.Str(rotel.LogTraceIDKey, sc.TraceID().String()).
+		Str(rotel.LogSpanIDKey, sc.SpanID().String())
+}
@@ -0,0 +1,33 @@
+package otelzerolog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	rotel "github.com/rezazadehramin/opentelemetry-go/otel"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/sdk/trace"
+)
+
+func TestHook_AddsTraceContext(t *testing.T) {
+	var out bytes.Buffer
+	logger := zerolog.New(&out).Hook(Hook{})
+
+	ctx, span := trace.NewTracerProvider().Tracer("sample").Start(context.TODO(), "sample span")
+	defer span.End()
+	logger.Info().Ctx(ctx).Msg("order shipped")
+
+	var record map[string]interface{}
+	assert.Nil(t, json.Unmarshal(out.Bytes(), &record))
+	assert.Equal(t, span.SpanContext().TraceID().String(), record[rotel.LogTraceIDKey])
+	assert.Equal(t, span.SpanContext().SpanID().String(), record[rotel.LogSpanIDKey])
+
+	out.Reset()
+	logger.Info().Msg("order shipped")
+	record = nil
+	assert.Nil(t, json.Unmarshal(out.Bytes(), &record))
+	assert.NotContains(t, record, rotel.LogTraceIDKey)
+}
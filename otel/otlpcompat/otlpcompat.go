@@ -0,0 +1,209 @@
+// Package otlpcompat guards the OTLP payloads emitted by the otel package
+// against changes of the underlying exporter and proto versions.
+//
+// The reference trace is a fixed set of spans covering the span fields, it's
+// exported by a GRPC pipeline of the otel package and the bytes received by
+// the collector are compared with a golden protobuf file, so downstream
+// consumers parsing raw OTLP are not broken by an upgrade:
+//
+//	if err := otlpcompat.CheckGolden("testdata/reference_trace.pb", *update); err != nil {
+//		t.Fatal(err)
+//	}
+package otlpcompat
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	rotel "github.com/rezazadehramin/opentelemetry-go/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	collectorpb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+)
+
+// SchemaVersion is the version of the reference trace, bump it along with
+// the golden file whenever the reference spans change on purpose.
+const SchemaVersion = 1
+
+const instrumentationName = "github.com/rezazadehramin/opentelemetry-go/otel/otlpcompat"
+
+// Fixed IDs of the reference trace.
+var (
+	referenceTraceID = trace.TraceID{0x57, 0x59, 0xe9, 0x88, 0xbd, 0x86, 0x2e, 0x3f, 0xe1, 0xbe, 0x46, 0xa9, 0x94, 0x27, 0x27, 0x93}
+	serverSpanID     = trace.SpanID{0x53, 0x99, 0x5c, 0x3f, 0x42, 0xcd, 0x8a, 0xd8}
+	clientSpanID     = trace.SpanID{0x1a, 0x2b, 0x3c, 0x4d, 0x5e, 0x6f, 0x70, 0x81}
+)
+
+// referenceIDs generates the IDs of the reference trace, the server span
+// being its root and the client span its child.
+type referenceIDs struct{}
+
+func (referenceIDs) NewIDs(context.Context) (trace.TraceID, trace.SpanID) {
+	return referenceTraceID, serverSpanID
+}
+
+func (referenceIDs) NewSpanID(context.Context, trace.TraceID) trace.SpanID {
+	return clientSpanID
+}
+
+// ReferenceConfig returns the configuration of the pipeline exporting the
+// reference trace, with a fixed resource and IDs.
+func ReferenceConfig() *rotel.Config {
+	return &rotel.Config{
+		ServiceName:       "reference",
+		ServiceVersion:    "v1.0.0",
+		ServiceInstanceID: "reference",
+		ResourceAttributes: []attribute.KeyValue{
+			attribute.Int("otlpcompat.schema_version", SchemaVersion),
+		},
+		IDGenerator: referenceIDs{},
+	}
+}
+
+// ReferenceTrace records the spans of the reference trace on provider, a
+// server span with a client child. Every value is fixed, including the
+// timestamps, the IDs being the ones of ReferenceConfig.
+func ReferenceTrace(provider trace.TracerProvider) {
+	start := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	tracer := provider.Tracer(instrumentationName, trace.WithInstrumentationVersion("v1"))
+	linked := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: trace.TraceID{0x01},
+		SpanID:  trace.SpanID{0x02},
+		Remote:  true,
+	})
+
+	ctx, server := tracer.Start(context.Background(), "GET /orders/{id}",
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithTimestamp(start),
+		trace.WithAttributes(
+			attribute.String("http.method", "GET"),
+			attribute.String("http.route", "/orders/{id}"),
+			attribute.Int("http.status_code", 500),
+			attribute.Bool("retried", false),
+			attribute.Float64("load", 0.75),
+			attribute.StringSlice("tags", []string{"a", "b"}),
+		),
+		trace.WithLinks(trace.Link{SpanContext: linked, Attributes: []attribute.KeyValue{attribute.String("link", "batch")}}),
+	)
+	_, client := tracer.Start(ctx, "SELECT",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithTimestamp(start.Add(10*time.Millisecond)),
+		trace.WithAttributes(
+			attribute.String("db.system", "postgresql"),
+			attribute.Int64("db.sql.rows_affected", 3),
+		),
+	)
+	client.End(trace.WithTimestamp(start.Add(40 * time.Millisecond)))
+
+	server.AddEvent("exception",
+		trace.WithTimestamp(start.Add(110*time.Millisecond)),
+		trace.WithAttributes(attribute.String("exception.message", "boom")),
+	)
+	server.SetStatus(codes.Error, "boom")
+	server.End(trace.WithTimestamp(start.Add(120 * time.Millisecond)))
+}
+
+// rawCodec keeps the messages received by the collector as their bytes.
+type rawCodec struct{}
+
+func (rawCodec) Marshal(v any) ([]byte, error) {
+	return *v.(*[]byte), nil
+}
+
+func (rawCodec) Unmarshal(data []byte, v any) error {
+	*v.(*[]byte) = append([]byte(nil), data...)
+	return nil
+}
+
+func (rawCodec) Name() string {
+	return "proto"
+}
+
+// rawCollector captures the bytes of the export requests as received.
+type rawCollector struct {
+	mu       sync.Mutex
+	requests [][]byte
+}
+
+func (c *rawCollector) handle(_ any, stream grpc.ServerStream) error {
+	var req []byte
+	if err := stream.RecvMsg(&req); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	c.requests = append(c.requests, req)
+	c.mu.Unlock()
+
+	resp, err := proto.Marshal(&collectorpb.ExportTraceServiceResponse{})
+	if err != nil {
+		return err
+	}
+	return stream.SendMsg(&resp)
+}
+
+// Encode returns the bytes of the export request received by a collector
+// from the GRPC pipeline built from c, spans being recorded on its provider
+// by record.
+func Encode(ctx context.Context, c *rotel.Config, record func(trace.TracerProvider)) ([]byte, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("could not listen: %w", err)
+	}
+	collector := &rawCollector{}
+	server := grpc.NewServer(grpc.ForceServerCodec(rawCodec{}), grpc.UnknownServiceHandler(collector.handle))
+	go server.Serve(listener)
+	defer server.Stop()
+
+	c.URL = listener.Addr().String()
+	c.Insecure = true
+	provider, err := rotel.NewExporter(rotel.GRPC, c, rotel.WithGlobalRegistration(false)).ExportPipeline(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("could not build pipeline: %w", err)
+	}
+	defer provider.Shutdown(ctx)
+
+	record(provider)
+	if err := provider.ForceFlush(ctx); err != nil {
+		return nil, fmt.Errorf("could not export spans: %w", err)
+	}
+
+	collector.mu.Lock()
+	defer collector.mu.Unlock()
+	if len(collector.requests) != 1 {
+		return nil, fmt.Errorf("expected 1 export request, got %d", len(collector.requests))
+	}
+
+	return collector.requests[0], nil
+}
+
+// CheckGolden compares the encoding of the reference trace with the golden
+// file at path, the file is written instead when update is set.
+func CheckGolden(path string, update bool) error {
+	got, err := Encode(context.Background(), ReferenceConfig(), ReferenceTrace)
+	if err != nil {
+		return err
+	}
+
+	if update {
+		return os.WriteFile(path, got, 0o644)
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("could not read golden file: %w", err)
+	}
+	if !bytes.Equal(got, want) {
+		return errors.New("OTLP encoding of the reference trace changed, review the change and update the golden file")
+	}
+
+	return nil
+}
@@ -0,0 +1,33 @@
+package otlpcompat
+
+import (
+	"context"
+	"flag"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	collectorpb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+var update = flag.Bool("update", false, "update the golden files")
+
+func TestReferenceTrace_MatchesGolden(t *testing.T) {
+	assert.Nil(t, CheckGolden("testdata/reference_trace.pb", *update))
+}
+
+func TestEncode_ReferenceTrace(t *testing.T) {
+	encoded, err := Encode(context.TODO(), ReferenceConfig(), ReferenceTrace)
+	assert.Nil(t, err)
+
+	var req collectorpb.ExportTraceServiceRequest
+	assert.Nil(t, proto.Unmarshal(encoded, &req))
+
+	assert.Equal(t, "https://opentelemetry.io/schemas/1.43.0", req.ResourceSpans[0].SchemaUrl)
+	spans := req.ResourceSpans[0].ScopeSpans[0].Spans
+	assert.Len(t, spans, 2)
+	assert.Equal(t, "SELECT", spans[0].Name)
+	assert.Equal(t, "GET /orders/{id}", spans[1].Name)
+	assert.Equal(t, spans[1].SpanId, spans[0].ParentSpanId)
+	assert.Equal(t, uint64(1704164645120000000), spans[1].EndTimeUnixNano)
+}
@@ -0,0 +1,89 @@
+package otel
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/sdk/trace"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"google.golang.org/grpc"
+)
+
+// SpansRejectedName is the name of the self-metric counting the spans
+// rejected by partial successes of the endpoint.
+const SpansRejectedName = "otel.pipeline.span.rejected"
+
+// PartialSuccessError is the error of the exports accepted by the endpoint
+// except for Rejected of their spans, it's reported to the error handler
+// and the rejected spans are counted as dropped by the PipelineHealth.
+type PartialSuccessError struct {
+	Rejected int64
+	Message  string
+}
+
+// Error implements the error interface.
+func (e *PartialSuccessError) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("OTLP partial success: %d spans rejected", e.Rejected)
+	}
+
+	return fmt.Sprintf("OTLP partial success: %d spans rejected: %s", e.Rejected, e.Message)
+}
+
+// partialSuccessKey carries the *PartialSuccessError filled by
+// partialSuccessInterceptor during an export.
+type partialSuccessKey struct{}
+
+// partialSuccessInterceptor records the partial success of the trace
+// export responses in the context of partialSuccessExporter.
+func partialSuccessInterceptor(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	err := invoker(ctx, method, req, reply, cc, opts...)
+	if partial, ok := ctx.Value(partialSuccessKey{}).(*PartialSuccessError); ok {
+		*partial = PartialSuccessError{}
+		if resp, ok := reply.(*coltracepb.ExportTraceServiceResponse); ok && err == nil {
+			partial.Rejected = resp.GetPartialSuccess().GetRejectedSpans()
+			partial.Message = resp.GetPartialSuccess().GetErrorMessage()
+		}
+	}
+
+	return err
+}
+
+// partialSuccessExporter returns a PartialSuccessError for the exports the
+// endpoint partially accepted, instead of the opaque error of the OTLP
+// exporter.
+type partialSuccessExporter struct {
+	trace.SpanExporter
+}
+
+// ExportSpans implements the trace.SpanExporter interface.
+func (e partialSuccessExporter) ExportSpans(ctx context.Context, spans []trace.ReadOnlySpan) error {
+	partial := &PartialSuccessError{}
+	err := e.SpanExporter.ExportSpans(context.WithValue(ctx, partialSuccessKey{}, partial), spans)
+	if err == nil || (partial.Rejected == 0 && partial.Message == "") {
+		return err
+	}
+
+	return partial
+}
+
+// registerRejected records the spans rejected by the endpoint on the meter
+// provider.
+func (h *exportHealth) registerRejected(mp metric.MeterProvider) error {
+	meter := mp.Meter(instrumentationName)
+
+	rejected, err := meter.Int64ObservableCounter(SpansRejectedName,
+		metric.WithDescription("Number of spans rejected by partial successes of the endpoint."),
+		metric.WithUnit("{span}"))
+	if err != nil {
+		return err
+	}
+
+	_, err = meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		o.ObserveInt64(rejected, h.rejected.Load())
+		return nil
+	}, rejected)
+
+	return err
+}
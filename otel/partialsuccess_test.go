@@ -0,0 +1,72 @@
+package otel
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"google.golang.org/grpc"
+)
+
+// partialCollector rejects one span of every export.
+type partialCollector struct {
+	coltracepb.UnimplementedTraceServiceServer
+}
+
+func (partialCollector) Export(context.Context, *coltracepb.ExportTraceServiceRequest) (*coltracepb.ExportTraceServiceResponse, error) {
+	return &coltracepb.ExportTraceServiceResponse{PartialSuccess: &coltracepb.ExportTracePartialSuccess{
+		RejectedSpans: 1,
+		ErrorMessage:  "span too old",
+	}}, nil
+}
+
+func TestGRPCOutput_ReportsPartialSuccess(t *testing.T) {
+	defer otel.SetErrorHandler(otel.GetErrorHandler())
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	server := grpc.NewServer()
+	coltracepb.RegisterTraceServiceServer(server, partialCollector{})
+	go server.Serve(lis)
+	defer server.Stop()
+
+	var handled []error
+	exporter := NewExporter(GRPC, &Config{
+		URL:          lis.Addr().String(),
+		Insecure:     true,
+		SyncExport:   true,
+		ErrorHandler: func(err error) { handled = append(handled, err) },
+	})
+	pipeline, err := exporter.ExportPipeline(context.TODO())
+	assert.Nil(t, err)
+	defer pipeline.Shutdown(context.TODO())
+
+	_, span := pipeline.Tracer("sample").Start(context.TODO(), "sample span")
+	span.End()
+
+	assert.Len(t, handled, 1)
+	assert.EqualError(t, handled[0], "OTLP partial success: 1 spans rejected: span too old")
+	health, _ := Health(exporter)
+	assert.Equal(t, "ok", health.Status)
+	assert.Equal(t, int64(1), health.SpansRejected)
+	assert.Equal(t, int64(1), health.SpansDropped)
+	assert.Equal(t, "OTLP partial success: 1 spans rejected: span too old", health.LastError)
+}
+
+func TestExportHealth_RegistersRejectedSpans(t *testing.T) {
+	h := &exportHealth{}
+	h.rejected.Add(3)
+
+	reader := metric.NewManualReader()
+	assert.Nil(t, h.registerRejected(metric.NewMeterProvider(metric.WithReader(reader))))
+	var rm metricdata.ResourceMetrics
+	assert.Nil(t, reader.Collect(context.TODO(), &rm))
+	rejected := rm.ScopeMetrics[0].Metrics[0]
+	assert.Equal(t, SpansRejectedName, rejected.Name)
+	assert.Equal(t, int64(3), rejected.Data.(metricdata.Sum[int64]).DataPoints[0].Value)
+}
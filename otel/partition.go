@@ -0,0 +1,76 @@
+package otel
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"go.opentelemetry.io/otel/sdk/trace"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// defaultMaxExportBatchBytes is the default export request size limit of
+// the GRPC output, the default max_recv_msg_size_mib of the collector.
+const defaultMaxExportBatchBytes = 4 << 20
+
+// partitionExporter splits the batches whose estimated size exceeds limit
+// into several export requests. Requests rejected as too large by the
+// endpoint are split in halves and sent again, unlike the ones throttled.
+type partitionExporter struct {
+	trace.SpanExporter
+	limit int64
+}
+
+// partitionExporter wraps exp with the Config.MaxExportBatchBytes limit.
+func (c *Config) partitionExporter(exp trace.SpanExporter) trace.SpanExporter {
+	limit := int64(c.MaxExportBatchBytes)
+	switch {
+	case limit < 0:
+		return exp
+	case limit == 0:
+		limit = defaultMaxExportBatchBytes
+	}
+
+	return &partitionExporter{SpanExporter: exp, limit: limit}
+}
+
+// ExportSpans implements the trace.SpanExporter interface.
+func (e *partitionExporter) ExportSpans(ctx context.Context, spans []trace.ReadOnlySpan) error {
+	var errs []error
+	start, size := 0, int64(0)
+	for i, s := range spans {
+		spanSize := spanSize(s)
+		if i > start && size+spanSize > e.limit {
+			errs = append(errs, e.export(ctx, spans[start:i]))
+			start, size = i, 0
+		}
+		size += spanSize
+	}
+	if start < len(spans) {
+		errs = append(errs, e.export(ctx, spans[start:]))
+	}
+
+	return errors.Join(errs...)
+}
+
+func (e *partitionExporter) export(ctx context.Context, spans []trace.ReadOnlySpan) error {
+	err := e.SpanExporter.ExportSpans(ctx, spans)
+	if len(spans) < 2 || !tooLarge(err) {
+		return err
+	}
+
+	half := len(spans) / 2
+	return errors.Join(e.export(ctx, spans[:half]), e.export(ctx, spans[half:]))
+}
+
+// tooLarge reports whether err rejects the request for its size, the
+// ResourceExhausted errors asking to retry later being throttling.
+func tooLarge(err error) bool {
+	if _, ok := retryDelay(err); ok {
+		return false
+	}
+	s, _ := status.FromError(err)
+
+	return s.Code() == codes.ResourceExhausted && strings.Contains(s.Message(), "message larger than max")
+}
@@ -0,0 +1,86 @@
+package otel
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// batchExporter records the sizes of the batches exported, rejecting the
+// ones over max spans as too large.
+type batchExporter struct {
+	trace.SpanExporter
+	max     int
+	batches []int
+}
+
+func (e *batchExporter) ExportSpans(_ context.Context, spans []trace.ReadOnlySpan) error {
+	if e.max > 0 && len(spans) > e.max {
+		return status.Error(codes.ResourceExhausted, "grpc: received message larger than max")
+	}
+	e.batches = append(e.batches, len(spans))
+	return nil
+}
+
+func spans(n int) []trace.ReadOnlySpan {
+	spans := make([]trace.ReadOnlySpan, n)
+	for i := range spans {
+		spans[i] = sampledSpan("sample span", otelcodes.Unset)
+	}
+	return spans
+}
+
+func TestPartitionExporter_SplitsOversizedBatches(t *testing.T) {
+	size := spanSize(sampledSpan("sample span", otelcodes.Unset))
+	exp := &batchExporter{}
+	partitioned := (&Config{MaxExportBatchBytes: int(3 * size)}).partitionExporter(exp)
+
+	assert.Nil(t, partitioned.ExportSpans(context.TODO(), spans(7)))
+	assert.Equal(t, []int{3, 3, 1}, exp.batches)
+}
+
+func TestPartitionExporter_SplitsRejectedBatches(t *testing.T) {
+	exp := &batchExporter{max: 2}
+	partitioned := (&Config{}).partitionExporter(exp)
+
+	assert.Nil(t, partitioned.ExportSpans(context.TODO(), spans(5)))
+	assert.Equal(t, []int{2, 1, 2}, exp.batches)
+
+	exp = &batchExporter{}
+	assert.Equal(t, trace.SpanExporter(exp), (&Config{MaxExportBatchBytes: -1}).partitionExporter(exp))
+}
+
+func TestPartitionExporter_KeepsThrottledBatches(t *testing.T) {
+	throttled, _ := status.New(codes.ResourceExhausted, "rate limited").
+		WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(time.Second)})
+	for _, err := range []error{
+		throttled.Err(),
+		status.Error(codes.ResourceExhausted, "quota exceeded"),
+	} {
+		exp := &rejectingExporter{err: err}
+		partitioned := (&Config{}).partitionExporter(exp)
+
+		assert.ErrorIs(t, partitioned.ExportSpans(context.TODO(), spans(4)), err)
+		assert.Equal(t, 1, exp.calls, "not split")
+	}
+}
+
+// rejectingExporter fails every export with err.
+type rejectingExporter struct {
+	trace.SpanExporter
+	err   error
+	calls int
+}
+
+func (e *rejectingExporter) ExportSpans(context.Context, []trace.ReadOnlySpan) error {
+	e.calls++
+	return e.err
+}
@@ -0,0 +1,140 @@
+package otel
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+
+	"go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// PingSpanName is the name of the synthetic span exported by Ping.
+const PingSpanName = "otel.ping"
+
+// PingStage is the step of Ping which failed.
+type PingStage string
+
+// Steps of Ping, in their order.
+const (
+	PingDNS     PingStage = "dns"
+	PingConnect PingStage = "connect"
+	PingTLS     PingStage = "tls"
+	PingExport  PingStage = "export"
+)
+
+// PingError reports the stage of Ping which failed for the endpoint.
+type PingError struct {
+	Stage    PingStage
+	Endpoint string
+	Err      error
+}
+
+// Error implements the error interface.
+func (e *PingError) Error() string {
+	return fmt.Sprintf("ping %s: %s failed: %v", e.Endpoint, e.Stage, e.Err)
+}
+
+// Unwrap returns the cause of the failure.
+func (e *PingError) Unwrap() error {
+	return e.Err
+}
+
+// Ping verifies the endpoint of the GRPC output built by e receives telemetry:
+// it resolves its host, connects to it with a TLS handshake unless Insecure
+// is set and exports a synthetic PingSpanName span, bounded by ctx. Failures
+// are a *PingError, use it as a readiness gate:
+//
+//	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+//	defer cancel()
+//	if err := otel.Ping(ctx, exporter); err != nil {
+//		var pingErr *otel.PingError
+//		errors.As(err, &pingErr)
+//		...
+//	}
+//
+// Other outputs have nothing to reach, like disabled pipelines, Ping returns nil.
+func Ping(ctx context.Context, e Exporter) error {
+	if _, ok := pipelineOf(e); !ok {
+		return errors.New("unsupported exporter")
+	}
+	g, ok := e.(*grpcOutput)
+	if !ok || g.Config.Disabled {
+		return nil
+	}
+
+	endpoint := g.Config.URL
+	if len(g.Config.Endpoints) > 0 {
+		// The replicas are expected to be reachable alike.
+		endpoint = g.Config.Endpoints[0]
+	}
+	fail := func(stage PingStage, err error) error {
+		return &PingError{Stage: stage, Endpoint: endpoint, Err: err}
+	}
+
+	host, _, err := net.SplitHostPort(endpoint)
+	if err != nil {
+		return fail(PingDNS, err)
+	}
+	proxy, err := g.Config.proxy()
+	if err != nil {
+		return fail(PingConnect, err)
+	}
+
+	var dialer net.Dialer
+	dial := func(ctx context.Context, addr string) (net.Conn, error) {
+		return dialer.DialContext(ctx, "tcp", addr)
+	}
+	if proxy != nil {
+		policy, err := g.Config.tlsPolicy()
+		if err != nil {
+			return fail(PingConnect, err)
+		}
+		// The proxy resolves the endpoint, like for the GRPC connection.
+		dial = proxyDialer(proxy, policy)
+	} else if _, err := net.DefaultResolver.LookupHost(ctx, host); err != nil {
+		return fail(PingDNS, err)
+	}
+	conn, err := dial(ctx, endpoint)
+	if err != nil {
+		return fail(PingConnect, err)
+	}
+	if !g.Config.Insecure {
+		config, err := g.Config.tlsConfig()
+		if err != nil {
+			conn.Close()
+			return fail(PingTLS, err)
+		}
+		config.ServerName, config.NextProtos = host, []string{"h2"}
+		tlsConn := tls.Client(conn, config)
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			conn.Close()
+			return fail(PingTLS, err)
+		}
+	}
+	conn.Close()
+
+	if err := g.ping(ctx); err != nil {
+		return fail(PingExport, err)
+	}
+
+	return nil
+}
+
+// ping exports a synthetic span on the connection shared by the exporters.
+func (g *grpcOutput) ping(ctx context.Context) error {
+	res := g.detectResource(ctx)
+	recorder := tracetest.NewSpanRecorder()
+	provider := trace.NewTracerProvider(trace.WithResource(res), trace.WithSpanProcessor(recorder))
+	_, span := provider.Tracer(instrumentationName).Start(context.Background(), PingSpanName)
+	span.End()
+
+	exp, err := g.newOTLPExporter(ctx)
+	if err != nil {
+		return err
+	}
+
+	return errors.Join(exp.ExportSpans(ctx, recorder.Ended()), exp.Shutdown(ctx))
+}
@@ -0,0 +1,57 @@
+package otel
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPing_ExportsSyntheticSpan(t *testing.T) {
+	addr, collector := startTraceCollector(t)
+	exporter := NewExporter(GRPC, &Config{URL: addr, Insecure: true, ServiceName: "orders"}, WithGlobalRegistration(false))
+
+	assert.Nil(t, Ping(context.TODO(), exporter))
+
+	select {
+	case req := <-collector.requests:
+		assert.Equal(t, PingSpanName, req.ResourceSpans[0].ScopeSpans[0].Spans[0].Name)
+	case <-time.After(5 * time.Second):
+		t.Fatal("no ping span received")
+	}
+}
+
+func TestPing_ReportsFailedStage(t *testing.T) {
+	addr, _ := startTraceCollector(t)
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	closed := lis.Addr().String()
+	lis.Close()
+
+	for _, tt := range []struct {
+		name  string
+		c     *Config
+		stage PingStage
+	}{
+		{"invalid endpoint", &Config{URL: "collector"}, PingDNS},
+		{"connection refused", &Config{URL: closed, Insecure: true}, PingConnect},
+		{"no TLS", &Config{URL: addr}, PingTLS},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Ping(context.TODO(), NewExporter(GRPC, tt.c, WithGlobalRegistration(false)))
+
+			var pingErr *PingError
+			assert.True(t, errors.As(err, &pingErr))
+			assert.Equal(t, tt.stage, pingErr.Stage)
+			assert.Equal(t, tt.c.URL, pingErr.Endpoint)
+		})
+	}
+}
+
+func TestPing_NothingToReach(t *testing.T) {
+	assert.Nil(t, Ping(context.TODO(), NewExporter(IO, &Config{}, WithGlobalRegistration(false))))
+	assert.Nil(t, Ping(context.TODO(), NewExporter(GRPC, &Config{URL: "collector", Disabled: true}, WithGlobalRegistration(false))))
+}
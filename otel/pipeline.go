@@ -0,0 +1,195 @@
+package otel
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"go.opentelemetry.io/contrib/zpages"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	"go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+	"go.opentelemetry.io/otel/sdk/trace"
+)
+
+// pipeline holds the state of an output once its provider is built.
+type pipeline struct {
+	options
+	config *Config
+
+	mu       sync.Mutex
+	provider *trace.TracerProvider
+	dryRun   *dryRunExporter
+
+	// processors are the processors registered on the provider, in their
+	// order, as reported by Snapshot.
+	processors []trace.SpanProcessor
+
+	resMu sync.Mutex
+	res   *resource.Resource
+
+	loggerProvider *sdklog.LoggerProvider
+	meterProvider  *metric.MeterProvider
+
+	sampler *dynamicSampler
+	debug   *debugProcessor
+	counter *spanCounter
+	red     *REDProcessor
+	health  *exportHealth
+	tracez  *zpages.SpanProcessor
+	viewer  *traceBuffer
+	drops   *dropRules
+	batch   *reloadableBatch
+
+	// syncExport exports spans as they end regardless of Config.SyncExport
+	syncExport bool
+}
+
+func newPipeline(c *Config, opts []Option) pipeline {
+	return pipeline{
+		options: newOptions(opts),
+		config:  c,
+		sampler: newDynamicSampler(),
+		debug:   &debugProcessor{},
+		counter: &spanCounter{},
+		red:     &REDProcessor{},
+		health:  &exportHealth{alert: newDropRateMonitor(c), memory: newMemoryLimiter(c)},
+		tracez:  zpages.NewSpanProcessor(),
+		viewer:  newTraceBuffer(),
+	}
+}
+
+func (p *pipeline) state() *pipeline {
+	return p
+}
+
+// detectResource returns the resource of the pipeline, detected once for its
+// traces, metrics and logs.
+func (p *pipeline) detectResource(ctx context.Context) *resource.Resource {
+	p.resMu.Lock()
+	defer p.resMu.Unlock()
+
+	if p.res == nil {
+		p.res, _ = p.config.resource(ctx)
+	}
+
+	return p.res
+}
+
+// pipelineOf returns the pipeline state of an exporter built by NewExporter.
+func pipelineOf(e Exporter) (*pipeline, bool) {
+	s, ok := e.(interface{ state() *pipeline })
+	if !ok {
+		return nil, false
+	}
+
+	return s.state(), true
+}
+
+// build returns the provider built by a previous call or builds it with
+// newProvider, a failed build is retried on the next call.
+func (p *pipeline) build(newProvider func() (*trace.TracerProvider, error)) (*trace.TracerProvider, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.provider != nil {
+		return p.provider, nil
+	}
+	if p.config.Disabled {
+		return p.disabledTracerProvider(), nil
+	}
+
+	propagator, err := p.config.TextMapPropagator()
+	if err != nil {
+		return nil, fmt.Errorf("could not create propagator: %w", err)
+	}
+	if _, err := p.config.detectorOptions(); err != nil {
+		return nil, err
+	}
+	if _, err := p.config.redactionRules(); err != nil {
+		return nil, err
+	}
+
+	p.processors = nil
+	provider, err := newProvider()
+	if err != nil {
+		return nil, err
+	}
+	p.provider = provider
+
+	if p.globalRegistration {
+		otel.SetTracerProvider(provider)
+		otel.SetTextMapPropagator(propagator)
+	}
+
+	return provider, nil
+}
+
+// current returns the provider once built, nil before.
+func (p *pipeline) current() *trace.TracerProvider {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.provider
+}
+
+// register returns the option registering sp on the provider, sp being
+// recorded in p.processors.
+func (p *pipeline) register(sp trace.SpanProcessor) trace.TracerProviderOption {
+	p.processors = append(p.processors, sp)
+	return trace.WithSpanProcessor(sp)
+}
+
+// configOptions returns the provider options shared by all outputs.
+func (p *pipeline) configOptions() []trace.TracerProviderOption {
+	opts := p.config.idGeneratorOptions()
+	for _, sp := range p.config.spanProcessors() {
+		opts = append(opts, p.register(sp))
+	}
+
+	return opts
+}
+
+// simpleProcessor and consoleProcessor are the processors exporting the
+// spans as they end to the output and to Config.ConsoleWriter.
+type (
+	simpleProcessor  struct{ trace.SpanProcessor }
+	consoleProcessor struct{ trace.SpanProcessor }
+)
+
+// exportOptions returns the processors exporting the spans to exp, batched
+// unless SyncExport is set, and to ConsoleWriter when set. Both go through
+// the processors transforming the spans before export.
+func (p *pipeline) exportOptions(exp trace.SpanExporter, batchOpts ...trace.BatchSpanProcessorOption) []trace.TracerProviderOption {
+	c := p.config
+	exp = p.health.exporter(exp)
+	var export trace.SpanProcessor
+	if c.SyncExport || p.syncExport {
+		export = simpleProcessor{trace.NewSimpleSpanProcessor(exp)}
+	} else {
+		if c.MaxQueueSize > 0 {
+			batchOpts = append(batchOpts, trace.WithMaxQueueSize(c.MaxQueueSize))
+		}
+		if c.BatchTimeout > 0 {
+			batchOpts = append(batchOpts, trace.WithBatchTimeout(c.batchTimeout(c.BatchTimeout)))
+		}
+		if c.queued() {
+			batchOpts = append(batchOpts, trace.WithBlocking())
+		}
+		p.batch = newReloadableBatch(c.workerExporter(exp), batchOpts...)
+		export = p.batch
+		if c.queued() {
+			export = c.queueProcessor(export, p.health.drop)
+		}
+	}
+	opts := []trace.TracerProviderOption{p.register(enabledProcessor{c.exportProcessor(c.errorOnlyProcessor(p.health.processor(export), p.health.memory))})}
+
+	if c.ConsoleWriter != nil {
+		console, _ := newConsoleExporter(&syncWriter{w: c.ConsoleWriter}, c.Format, stdouttrace.WithPrettyPrint())
+		opts = append(opts, p.register(consoleProcessor{c.exportProcessor(trace.NewSimpleSpanProcessor(console))}))
+	}
+
+	return opts
+}
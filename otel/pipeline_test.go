@@ -0,0 +1,52 @@
+package otel
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/sdk/trace"
+)
+
+func TestPipeline_ExportPipelineIsIdempotent(t *testing.T) {
+	setEnv()
+	defer unsetEnv()
+
+	c := NewENVConfig()
+	c.Writer = io.Discard
+	exporter := NewExporter(IO, c)
+
+	var wg sync.WaitGroup
+	providers := make([]*trace.TracerProvider, 10)
+	for i := range providers {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			providers[i], _ = exporter.ExportPipeline(context.TODO())
+		}(i)
+	}
+	wg.Wait()
+	defer providers[0].Shutdown(context.TODO())
+
+	for _, provider := range providers {
+		assert.Same(t, providers[0], provider)
+	}
+}
+
+func TestPipeline_RetriesFailedBuild(t *testing.T) {
+	p := newPipeline(&Config{}, nil)
+
+	_, err := p.build(func() (*trace.TracerProvider, error) {
+		return nil, errors.New("unreachable")
+	})
+	assert.NotNil(t, err)
+
+	provider, err := p.build(func() (*trace.TracerProvider, error) {
+		return trace.NewTracerProvider(), nil
+	})
+	assert.Nil(t, err)
+	assert.NotNil(t, provider)
+}
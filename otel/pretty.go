@@ -0,0 +1,165 @@
+package otel
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/sdk/trace"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+// OutputFormat is the format spans are written in by the IO output
+// and to Config.ConsoleWriter.
+type OutputFormat int
+
+// Supported output formats.
+const (
+	// FormatJSON writes a JSON document per span, the stdouttrace format.
+	FormatJSON OutputFormat = iota
+
+	// FormatPretty writes a line per span indented under its parent, with its
+	// duration, kind, status and attributes, colorized on terminals. It's meant
+	// for development, its layout isn't stable.
+	FormatPretty
+)
+
+// ANSI escape sequences of the pretty format.
+const (
+	ansiReset = "\033[0m"
+	ansiBold  = "\033[1m"
+	ansiDim   = "\033[2m"
+	ansiRed   = "\033[31m"
+	ansiGreen = "\033[32m"
+	ansiCyan  = "\033[36m"
+)
+
+// prettyExporter writes the spans of every batch grouped by trace, children
+// indented under their parent when both are in the batch.
+type prettyExporter struct {
+	mu    sync.Mutex
+	w     io.Writer
+	color bool
+}
+
+var _ trace.SpanExporter = (*prettyExporter)(nil)
+
+// newPrettyExporter creates a pretty exporter writing to w, colorized when
+// w is a terminal and NO_COLOR is unset.
+func newPrettyExporter(w io.Writer) *prettyExporter {
+	return &prettyExporter{w: w, color: isTerminal(w) && os.Getenv("NO_COLOR") == ""}
+}
+
+func isTerminal(w io.Writer) bool {
+	if sw, ok := w.(*syncWriter); ok {
+		w = sw.w
+	}
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+func (e *prettyExporter) paint(style, s string) string {
+	if !e.color {
+		return s
+	}
+
+	return style + s + ansiReset
+}
+
+// ExportSpans implements the trace.SpanExporter interface.
+func (e *prettyExporter) ExportSpans(_ context.Context, spans []trace.ReadOnlySpan) error {
+	var traces []oteltrace.TraceID
+	byTrace := make(map[oteltrace.TraceID][]trace.ReadOnlySpan)
+	for _, s := range spans {
+		id := s.SpanContext().TraceID()
+		if _, ok := byTrace[id]; !ok {
+			traces = append(traces, id)
+		}
+		byTrace[id] = append(byTrace[id], s)
+	}
+
+	var b strings.Builder
+	for _, id := range traces {
+		b.WriteString(e.paint(ansiDim, "trace "+id.String()) + "\n")
+
+		roots, children := spanTree(byTrace[id])
+		for _, s := range roots {
+			e.writeSpan(&b, s, children, 1)
+		}
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	_, err := io.WriteString(e.w, b.String())
+
+	return err
+}
+
+func (e *prettyExporter) writeSpan(b *strings.Builder, s trace.ReadOnlySpan, children map[oteltrace.SpanID][]trace.ReadOnlySpan, depth int) {
+	indent := strings.Repeat("  ", depth)
+	status := e.paint(ansiGreen, "ok")
+	if s.Status().Code == codes.Error {
+		status = e.paint(ansiRed, "error")
+		if s.Status().Description != "" {
+			status += e.paint(ansiRed, ": "+s.Status().Description)
+		}
+	}
+
+	fmt.Fprintf(b, "%s%s %s %s %s", indent, e.paint(ansiBold, s.Name()),
+		e.paint(ansiCyan, formatDuration(s.EndTime().Sub(s.StartTime()))), s.SpanKind(), status)
+	for _, kv := range s.Attributes() {
+		fmt.Fprintf(b, " %s", e.paint(ansiDim, string(kv.Key)+"="+kv.Value.Emit()))
+	}
+	b.WriteString("\n")
+
+	for _, event := range s.Events() {
+		fmt.Fprintf(b, "%s  %s %s", indent, e.paint(ansiDim, "•"), event.Name)
+		for _, kv := range event.Attributes {
+			fmt.Fprintf(b, " %s", e.paint(ansiDim, string(kv.Key)+"="+kv.Value.Emit()))
+		}
+		b.WriteString("\n")
+	}
+
+	for _, child := range children[s.SpanContext().SpanID()] {
+		e.writeSpan(b, child, children, depth+1)
+	}
+}
+
+// formatDuration rounds d to 2 decimals of its unit, like 1.25s or 310µs.
+func formatDuration(d time.Duration) string {
+	switch {
+	case d >= time.Second:
+		return d.Round(10 * time.Millisecond).String()
+	case d >= time.Millisecond:
+		return d.Round(10 * time.Microsecond).String()
+	case d >= time.Microsecond:
+		return d.Round(10 * time.Nanosecond).String()
+	}
+
+	return d.String()
+}
+
+// Shutdown implements the trace.SpanExporter interface.
+func (e *prettyExporter) Shutdown(context.Context) error {
+	return nil
+}
+
+// newConsoleExporter returns the exporter writing spans to w in format.
+func newConsoleExporter(w io.Writer, format OutputFormat, opts ...stdouttrace.Option) (trace.SpanExporter, error) {
+	if format == FormatPretty {
+		return newPrettyExporter(w), nil
+	}
+
+	return stdouttrace.New(append(opts, stdouttrace.WithWriter(w))...)
+}
@@ -0,0 +1,39 @@
+package otel
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+func TestPrettyFormat_IndentsChildren(t *testing.T) {
+	var out bytes.Buffer
+	exporter := NewExporter(IO, &Config{Writer: &out, Format: FormatPretty}, WithGlobalRegistration(false))
+
+	pipeline, err := exporter.ExportPipeline(context.TODO())
+	assert.Nil(t, err)
+
+	start := time.Now()
+	tracer := pipeline.Tracer("sample")
+	ctx, request := tracer.Start(context.TODO(), "GET /orders", oteltrace.WithTimestamp(start),
+		oteltrace.WithSpanKind(oteltrace.SpanKindServer), oteltrace.WithAttributes(attribute.String("http.method", "GET")))
+	_, query := tracer.Start(ctx, "SELECT orders", oteltrace.WithTimestamp(start))
+	query.RecordError(errors.New("timeout"))
+	query.SetStatus(codes.Error, "timeout")
+	query.End(oteltrace.WithTimestamp(start.Add(31234 * time.Microsecond)))
+	request.End(oteltrace.WithTimestamp(start.Add(1234567 * time.Microsecond)))
+	assert.Nil(t, pipeline.Shutdown(context.TODO()))
+
+	assert.Equal(t, "trace "+request.SpanContext().TraceID().String()+"\n"+
+		"  GET /orders 1.23s server ok http.method=GET\n"+
+		"    SELECT orders 31.23ms internal error: timeout\n"+
+		"      • exception exception.type=*errors.errorString exception.message=timeout\n",
+		out.String())
+}
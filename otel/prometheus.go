@@ -0,0 +1,51 @@
+package otel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	otelprometheus "go.opentelemetry.io/otel/exporters/prometheus"
+	"go.opentelemetry.io/otel/sdk/metric"
+)
+
+// prometheusOutput serves metrics on a Prometheus scrape endpoint,
+// spans and logs are written to the Writer like the IO output.
+type prometheusOutput struct {
+	ioOutput
+	registry *prometheus.Registry
+}
+
+func (c *prometheusOutput) newMeterProvider(ctx context.Context) (*metric.MeterProvider, error) {
+	// Prometheus is pull based, only cumulative temporality is supported
+	aggregation, _ := c.Config.aggregationSelector()
+	exp, err := otelprometheus.New(
+		otelprometheus.WithRegisterer(c.registry),
+		otelprometheus.WithAggregationSelector(aggregation),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("could not create prometheus exporter: %w", err)
+	}
+
+	resource := c.detectResource(ctx)
+	return metric.NewMeterProvider(c.Config.meterProviderOptions(exp, resource)...), nil
+}
+
+// PrometheusHandler returns the handler serving the metrics of the pipeline
+// built by e for the Prometheus output in the Prometheus exposition format,
+// mount it on an existing mux:
+//
+//	mux.Handle("/metrics", otel.PrometheusHandler(exporter))
+//
+// Metrics are served once ExportMetricPipeline built the meter provider.
+func PrometheusHandler(e Exporter) (http.Handler, error) {
+	output, ok := e.(*prometheusOutput)
+	if !ok {
+		return nil, errors.New("unsupported exporter")
+	}
+
+	return promhttp.HandlerFor(output.registry, promhttp.HandlerOpts{}), nil
+}
@@ -0,0 +1,39 @@
+package otel
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrometheusHandler_ServesMetrics(t *testing.T) {
+	exporter := NewExporter(Prometheus, &Config{ServiceName: "orders", Writer: io.Discard}, WithGlobalRegistration(false))
+	handler, err := PrometheusHandler(exporter)
+	assert.Nil(t, err)
+
+	provider, err := ExportMetricPipeline(context.TODO(), exporter)
+	assert.Nil(t, err)
+	defer provider.Shutdown(context.TODO())
+
+	counter, err := provider.Meter("sample").Int64Counter("orders.placed")
+	assert.Nil(t, err)
+	counter.Add(context.TODO(), 3)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "orders_placed_total")
+	assert.Contains(t, rec.Body.String(), `service_name="orders"`)
+
+	snapshot, _ := Snapshot(exporter)
+	assert.Equal(t, "prometheus", snapshot.Output)
+}
+
+func TestPrometheusHandler_UnsupportedExporter(t *testing.T) {
+	_, err := PrometheusHandler(NewExporter(IO, &Config{}))
+	assert.EqualError(t, err, "unsupported exporter")
+}
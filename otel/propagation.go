@@ -0,0 +1,118 @@
+package otel
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/contrib/propagators/b3"
+	"go.opentelemetry.io/contrib/propagators/jaeger"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// defaultPropagators are used when Config.Propagators is empty.
+var defaultPropagators = []string{"tracecontext", "baggage"}
+
+// propagators are the supported Config.Propagators and OTEL_PROPAGATORS values.
+var propagators = map[string]func() propagation.TextMapPropagator{
+	"tracecontext": func() propagation.TextMapPropagator { return propagation.TraceContext{} },
+	"baggage":      func() propagation.TextMapPropagator { return propagation.Baggage{} },
+	"b3":           func() propagation.TextMapPropagator { return b3.New(b3.WithInjectEncoding(b3.B3SingleHeader)) },
+	"b3multi":      func() propagation.TextMapPropagator { return b3.New(b3.WithInjectEncoding(b3.B3MultipleHeader)) },
+	"jaeger":       func() propagation.TextMapPropagator { return jaeger.Jaeger{} },
+	"xray":         func() propagation.TextMapPropagator { return xrayPropagator{} },
+}
+
+// TextMapPropagator returns the composite propagator built from
+// Config.Propagators, W3C tracecontext and baggage when empty.
+//
+// Supported values are tracecontext, baggage, b3, b3multi, jaeger and xray,
+// none disables them. Config.LegacyExtractors run after them, none included.
+func (c *Config) TextMapPropagator() (propagation.TextMapPropagator, error) {
+	names := c.Propagators
+	if len(names) == 0 {
+		names = defaultPropagators
+	}
+
+	var list []propagation.TextMapPropagator
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "none" {
+			list = nil
+			break
+		}
+
+		newPropagator, ok := propagators[name]
+		if !ok {
+			return nil, fmt.Errorf("unsupported propagator %q", name)
+		}
+		list = append(list, newPropagator())
+	}
+	if len(c.LegacyExtractors) > 0 {
+		list = append(list, legacyPropagator{extractors: c.LegacyExtractors})
+	}
+
+	return propagation.NewCompositeTextMapPropagator(list...), nil
+}
+
+const xrayHeader = "X-Amzn-Trace-Id"
+
+// xrayPropagator propagates the span context in the AWS X-Ray header, e.g.
+// Root=1-5759e988-bd862e3fe1be46a994272793;Parent=53995c3f42cd8ad8;Sampled=1
+type xrayPropagator struct{}
+
+// Inject implements the propagation.TextMapPropagator interface.
+func (xrayPropagator) Inject(ctx context.Context, carrier propagation.TextMapCarrier) {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return
+	}
+
+	sampled := "0"
+	if sc.IsSampled() {
+		sampled = "1"
+	}
+
+	tid := sc.TraceID().String()
+	carrier.Set(xrayHeader, fmt.Sprintf("Root=1-%s-%s;Parent=%s;Sampled=%s", tid[:8], tid[8:], sc.SpanID(), sampled))
+}
+
+// Extract implements the propagation.TextMapPropagator interface.
+func (xrayPropagator) Extract(ctx context.Context, carrier propagation.TextMapCarrier) context.Context {
+	sc, ok := parseXRayHeader(carrier.Get(xrayHeader))
+	if !ok {
+		return ctx
+	}
+
+	return trace.ContextWithRemoteSpanContext(ctx, sc)
+}
+
+// Fields implements the propagation.TextMapPropagator interface.
+func (xrayPropagator) Fields() []string {
+	return []string{xrayHeader}
+}
+
+func parseXRayHeader(header string) (trace.SpanContext, bool) {
+	cfg := trace.SpanContextConfig{Remote: true}
+	for _, part := range strings.Split(header, ";") {
+		key, value, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch key {
+		case "Root":
+			fields := strings.Split(value, "-")
+			if len(fields) != 3 || fields[0] != "1" {
+				return trace.SpanContext{}, false
+			}
+			cfg.TraceID, _ = trace.TraceIDFromHex(fields[1] + fields[2])
+		case "Parent":
+			cfg.SpanID, _ = trace.SpanIDFromHex(value)
+		case "Sampled":
+			if value == "1" {
+				cfg.TraceFlags = trace.FlagsSampled
+			}
+		}
+	}
+
+	sc := trace.NewSpanContext(cfg)
+	return sc, sc.IsValid()
+}
@@ -0,0 +1,130 @@
+package otel
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestPropagation_DefaultPropagators(t *testing.T) {
+	setEnv()
+	defer unsetEnv()
+
+	c := NewENVConfig()
+	c.Writer = io.Discard
+	pipeline, err := NewExporter(IO, c).ExportPipeline(context.TODO())
+	assert.Nil(t, err)
+	defer pipeline.Shutdown(context.TODO())
+
+	assert.ElementsMatch(t, []string{"traceparent", "tracestate", "baggage"}, otel.GetTextMapPropagator().Fields())
+}
+
+func TestPropagation_FromEnv(t *testing.T) {
+	setEnv()
+	defer unsetEnv()
+	os.Setenv("OTEL_PROPAGATORS", "tracecontext, b3multi,xray")
+	defer os.Unsetenv("OTEL_PROPAGATORS")
+
+	propagator, err := NewENVConfig().TextMapPropagator()
+	assert.Nil(t, err)
+	assert.Contains(t, propagator.Fields(), "x-b3-traceid")
+	assert.Contains(t, propagator.Fields(), "X-Amzn-Trace-Id")
+}
+
+func TestPropagation_UnsupportedPropagator(t *testing.T) {
+	_, err := (&Config{Propagators: []string{"ottrace"}}).TextMapPropagator()
+
+	assert.EqualError(t, err, `unsupported propagator "ottrace"`)
+}
+
+func TestPropagation_XRayRoundTrip(t *testing.T) {
+	header := http.Header{}
+	header.Set("X-Amzn-Trace-Id", "Root=1-5759e988-bd862e3fe1be46a994272793;Parent=53995c3f42cd8ad8;Sampled=1")
+	carrier := propagation.HeaderCarrier(header)
+
+	ctx := xrayPropagator{}.Extract(context.TODO(), carrier)
+	sc := trace.SpanContextFromContext(ctx)
+	assert.Equal(t, "5759e988bd862e3fe1be46a994272793", sc.TraceID().String())
+	assert.Equal(t, "53995c3f42cd8ad8", sc.SpanID().String())
+	assert.True(t, sc.IsSampled())
+
+	out := propagation.HeaderCarrier(http.Header{})
+	xrayPropagator{}.Inject(ctx, out)
+	assert.Equal(t, header.Get("X-Amzn-Trace-Id"), out.Get("X-Amzn-Trace-Id"))
+}
+
+func TestPropagation_LegacyExtractors(t *testing.T) {
+	c := &Config{LegacyExtractors: []LegacyExtractor{
+		NewXRayExtractor(),
+		NewCorrelationIDExtractor("X-Correlation-Id"),
+	}}
+	propagator, err := c.TextMapPropagator()
+	assert.Nil(t, err)
+
+	header := http.Header{}
+	header.Set("X-Correlation-Id", "5759e988-bd86-2e3f-e1be-46a994272793")
+	sc := trace.SpanContextFromContext(propagator.Extract(context.TODO(), propagation.HeaderCarrier(header)))
+	assert.Equal(t, "5759e988bd862e3fe1be46a994272793", sc.TraceID().String())
+	assert.True(t, sc.IsSampled())
+
+	// standard and earlier headers take precedence
+	header.Set("X-Amzn-Trace-Id", "Root=1-11111111-bd862e3fe1be46a994272793;Parent=53995c3f42cd8ad8")
+	sc = trace.SpanContextFromContext(propagator.Extract(context.TODO(), propagation.HeaderCarrier(header)))
+	assert.Equal(t, "11111111bd862e3fe1be46a994272793", sc.TraceID().String())
+
+	header.Set("traceparent", "00-22222222bd862e3fe1be46a994272793-53995c3f42cd8ad8-01")
+	sc = trace.SpanContextFromContext(propagator.Extract(context.TODO(), propagation.HeaderCarrier(header)))
+	assert.Equal(t, "22222222bd862e3fe1be46a994272793", sc.TraceID().String())
+}
+
+func TestPropagation_LegacyExtractorsWithoutPropagators(t *testing.T) {
+	c := &Config{
+		Propagators:      []string{"none"},
+		LegacyExtractors: []LegacyExtractor{NewCorrelationIDExtractor("X-Correlation-Id")},
+	}
+	propagator, err := c.TextMapPropagator()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"X-Correlation-Id"}, propagator.Fields())
+
+	header := http.Header{}
+	header.Set("traceparent", "00-22222222bd862e3fe1be46a994272793-53995c3f42cd8ad8-01")
+	header.Set("X-Correlation-Id", "5759e988-bd86-2e3f-e1be-46a994272793")
+	sc := trace.SpanContextFromContext(propagator.Extract(context.TODO(), propagation.HeaderCarrier(header)))
+	assert.Equal(t, "5759e988bd862e3fe1be46a994272793", sc.TraceID().String())
+}
+
+func TestPropagation_LegacyExtractorLinks(t *testing.T) {
+	c := &Config{LegacyExtractors: []LegacyExtractor{
+		{Header: "X-Correlation-Id", Parse: parseCorrelationID, Link: true},
+	}}
+	propagator, err := c.TextMapPropagator()
+	assert.Nil(t, err)
+
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(c.spanProcessors()[0]), sdktrace.WithSpanProcessor(recorder))
+
+	header := http.Header{}
+	header.Set("X-Correlation-Id", "order-42")
+	ctx := propagator.Extract(context.TODO(), propagation.HeaderCarrier(header))
+	assert.False(t, trace.SpanContextFromContext(ctx).IsValid())
+
+	ctx, server := tp.Tracer("sample").Start(ctx, "server")
+	_, child := tp.Tracer("sample").Start(ctx, "child")
+	child.End()
+	server.End()
+
+	spans := recorder.Ended()
+	assert.Empty(t, spans[0].Links())
+	assert.Len(t, spans[1].Links(), 1)
+	linked, _ := parseCorrelationID("order-42")
+	assert.Equal(t, linked.TraceID(), spans[1].Links()[0].SpanContext.TraceID())
+}
@@ -0,0 +1,108 @@
+package otel
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// proxy parses Config.ProxyURL, nil when unset.
+func (c *Config) proxy() (*url.URL, error) {
+	if c.ProxyURL == "" {
+		return nil, nil
+	}
+	proxy, err := url.Parse(c.ProxyURL)
+	if err != nil || (proxy.Scheme != "http" && proxy.Scheme != "https") || proxy.Host == "" {
+		return nil, fmt.Errorf("invalid proxy URL %q, expected http://[user:password@]host:port", c.ProxyURL)
+	}
+
+	return proxy, nil
+}
+
+// proxyDialer returns a dialer tunneling the connections through the HTTP
+// proxy with the CONNECT method, authenticated with the user of its URL. The
+// connections to an HTTPS proxy are restricted by the policy of tlsPolicy.
+func proxyDialer(proxy *url.URL, policy *tls.Config) func(ctx context.Context, addr string) (net.Conn, error) {
+	proxyAddr := proxy.Host
+	if proxy.Port() == "" {
+		proxyAddr = net.JoinHostPort(proxy.Hostname(), map[string]string{"http": "80", "https": "443"}[proxy.Scheme])
+	}
+
+	return func(ctx context.Context, addr string) (net.Conn, error) {
+		var dialer net.Dialer
+		conn, err := dialer.DialContext(ctx, "tcp", proxyAddr)
+		if err != nil {
+			return nil, fmt.Errorf("could not connect to proxy %s: %w", proxy.Redacted(), err)
+		}
+		if proxy.Scheme == "https" {
+			config := policy.Clone()
+			config.ServerName = proxy.Hostname()
+			tlsConn := tls.Client(conn, config)
+			if err := tlsConn.HandshakeContext(ctx); err != nil {
+				conn.Close()
+				return nil, fmt.Errorf("could not connect to proxy %s: %w", proxy.Redacted(), err)
+			}
+			conn = tlsConn
+		}
+
+		tunnel, err := connect(ctx, conn, proxy, addr)
+		if err != nil {
+			conn.Close()
+			return nil, err
+		}
+
+		return tunnel, nil
+	}
+}
+
+// connect asks the proxy on conn to tunnel to addr.
+func connect(ctx context.Context, conn net.Conn, proxy *url.URL, addr string) (net.Conn, error) {
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+		defer conn.SetDeadline(time.Time{})
+	}
+
+	req := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Opaque: addr},
+		Host:   addr,
+		Header: http.Header{},
+	}
+	if user := proxy.User; user != nil {
+		password, _ := user.Password()
+		credentials := base64.StdEncoding.EncodeToString([]byte(user.Username() + ":" + password))
+		req.Header.Set("Proxy-Authorization", "Basic "+credentials)
+	}
+	if err := req.Write(conn); err != nil {
+		return nil, fmt.Errorf("could not connect to %s through proxy %s: %w", addr, proxy.Redacted(), err)
+	}
+
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, req)
+	if err != nil {
+		return nil, fmt.Errorf("could not connect to %s through proxy %s: %w", addr, proxy.Redacted(), err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("proxy %s refused to connect to %s: %s", proxy.Redacted(), addr, resp.Status)
+	}
+
+	return &bufferedConn{Conn: conn, reader: reader}, nil
+}
+
+// bufferedConn reads what the proxy sent after its response first.
+type bufferedConn struct {
+	net.Conn
+	reader *bufio.Reader
+}
+
+// Read implements the net.Conn interface.
+func (c *bufferedConn) Read(b []byte) (int, error) {
+	return c.reader.Read(b)
+}
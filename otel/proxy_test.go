@@ -0,0 +1,122 @@
+package otel
+
+import (
+	"context"
+	"crypto/tls"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// connectProxy tunnels the CONNECT requests with the expected credentials,
+// counting them in tunnels and sending the last addresses tunneled to on hosts.
+func connectProxy(t *testing.T, authorization string, tunnels *atomic.Int32, hosts chan string) *httptest.Server {
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodConnect || r.Header.Get("Proxy-Authorization") != authorization {
+			w.WriteHeader(http.StatusProxyAuthRequired)
+			return
+		}
+		upstream, err := net.Dial("tcp", r.Host)
+		if err != nil {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		tunnels.Add(1)
+		select {
+		case hosts <- r.Host:
+		default:
+		}
+		conn, buffered, _ := http.NewResponseController(w).Hijack()
+		io.WriteString(conn, "HTTP/1.1 200 Connection established\r\n\r\n")
+		go func() {
+			io.Copy(upstream, buffered)
+			upstream.Close()
+		}()
+		io.Copy(conn, upstream)
+		conn.Close()
+	}))
+	t.Cleanup(proxy.Close)
+
+	return proxy
+}
+
+func TestGRPCOutput_ExportsThroughProxy(t *testing.T) {
+	addr, collector := startTraceCollector(t)
+	var tunnels atomic.Int32
+	proxy := connectProxy(t, "Basic dXNlcjpzZWNyZXQ=", &tunnels, nil)
+
+	exporter := NewExporter(GRPC, &Config{
+		URL:        addr,
+		Insecure:   true,
+		SyncExport: true,
+		ProxyURL:   strings.Replace(proxy.URL, "http://", "http://user:secret@", 1),
+	}, WithGlobalRegistration(false))
+	assert.Nil(t, Ping(context.TODO(), exporter))
+
+	pipeline, err := exporter.ExportPipeline(context.TODO())
+	assert.Nil(t, err)
+	defer pipeline.Shutdown(context.TODO())
+
+	_, span := pipeline.Tracer("sample").Start(context.TODO(), "sample span")
+	span.End()
+	<-collector.requests
+	req := <-collector.requests
+	assert.Equal(t, "sample span", req.ResourceSpans[0].ScopeSpans[0].Spans[0].Name)
+	assert.GreaterOrEqual(t, tunnels.Load(), int32(2), "the connections of the ping and the pipeline")
+}
+
+func TestGRPCOutput_ProxyResolvesHost(t *testing.T) {
+	addr, collector := startTraceCollector(t)
+	_, port, _ := net.SplitHostPort(addr)
+	hosts := make(chan string, 10)
+	proxy := connectProxy(t, "", &atomic.Int32{}, hosts)
+
+	exporter := NewExporter(GRPC, &Config{
+		URL:        net.JoinHostPort("localhost", port),
+		Insecure:   true,
+		SyncExport: true,
+		ProxyURL:   proxy.URL,
+	}, WithGlobalRegistration(false))
+	pipeline, err := exporter.ExportPipeline(context.TODO())
+	assert.Nil(t, err)
+	defer pipeline.Shutdown(context.TODO())
+
+	_, span := pipeline.Tracer("sample").Start(context.TODO(), "sample span")
+	span.End()
+	<-collector.requests
+	assert.Equal(t, net.JoinHostPort("localhost", port), <-hosts)
+}
+
+func TestProxyDialer_ReportsRefusal(t *testing.T) {
+	proxy := connectProxy(t, "Basic dXNlcjpzZWNyZXQ=", &atomic.Int32{}, nil)
+	c := &Config{ProxyURL: proxy.URL}
+	proxyURL, err := c.proxy()
+	assert.Nil(t, err)
+
+	_, err = proxyDialer(proxyURL, &tls.Config{})(context.TODO(), "collector:4317")
+	assert.ErrorContains(t, err, "refused to connect to collector:4317: 407 Proxy Authentication Required")
+
+	_, err = (&Config{ProxyURL: "socks5://proxy:1080"}).proxy()
+	assert.EqualError(t, err, `invalid proxy URL "socks5://proxy:1080", expected http://[user:password@]host:port`)
+}
+
+func TestProxyDialer_RestrictsHTTPSProxy(t *testing.T) {
+	proxy := httptest.NewUnstartedServer(nil)
+	proxy.TLS = &tls.Config{MaxVersion: tls.VersionTLS12}
+	proxy.StartTLS()
+	defer proxy.Close()
+	c := &Config{ProxyURL: proxy.URL, TLSMinVersion: "1.3"}
+	proxyURL, err := c.proxy()
+	assert.Nil(t, err)
+	policy, err := c.tlsPolicy()
+	assert.Nil(t, err)
+
+	_, err = proxyDialer(proxyURL, policy)(context.TODO(), "collector:4317")
+	assert.ErrorContains(t, err, "protocol version")
+}
@@ -0,0 +1,213 @@
+package otel
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/sdk/trace"
+)
+
+// defaultQueueSize is the size of the queue processor,
+// the default queue size of the batch processor.
+const defaultQueueSize = 2048
+
+// queueProcessor queues the ended spans in front of a blocking batch
+// processor, for the queue behaviors the batch processor lacks.
+//
+// With priority it hands the notable spans first and, when the queue is
+// full, drops the oldest OK span to make room for a notable one. With block
+// callers wait up to block for room in a full queue. Other spans are dropped
+// like the batch processor does.
+type queueProcessor struct {
+	next     trace.SpanProcessor
+	size     int
+	priority bool
+	block    time.Duration
+	notable  func(trace.ReadOnlySpan) bool
+	onDrop   func(trace.ReadOnlySpan)
+
+	mu       sync.Mutex
+	cond     *sync.Cond
+	high     []trace.ReadOnlySpan
+	low      []trace.ReadOnlySpan
+	inflight bool
+	closed   bool
+	done     chan struct{}
+}
+
+// queueProcessor wraps the blocking batch processor export with the queue
+// of Config.PriorityQueue and BlockOnQueueFull, onDrop is called for every
+// dropped span.
+func (c *Config) queueProcessor(export trace.SpanProcessor, onDrop func(trace.ReadOnlySpan)) trace.SpanProcessor {
+	size := c.MaxQueueSize
+	if size <= 0 {
+		size = defaultQueueSize
+	}
+	threshold := c.SlowSpanThreshold
+
+	p := &queueProcessor{
+		next:     export,
+		size:     size,
+		priority: c.PriorityQueue,
+		block:    c.BlockOnQueueFull,
+		notable:  func(s trace.ReadOnlySpan) bool { return notableSpan(s, threshold) },
+		onDrop:   onDrop,
+		done:     make(chan struct{}),
+	}
+	p.cond = sync.NewCond(&p.mu)
+	go p.run()
+
+	return p
+}
+
+// queued reports whether the queue processor of Config is used, MemoryLimit
+// and DropRateThreshold need it as the spans dropped by the batch processor
+// can't be accounted for.
+func (c *Config) queued() bool {
+	return c.PriorityQueue || c.BlockOnQueueFull > 0 || c.MemoryLimit > 0 || c.DropRateThreshold > 0
+}
+
+// OnStart implements the trace.SpanProcessor interface.
+func (p *queueProcessor) OnStart(ctx context.Context, s trace.ReadWriteSpan) {
+	p.next.OnStart(ctx, s)
+}
+
+// OnEnd implements the trace.SpanProcessor interface.
+func (p *queueProcessor) OnEnd(s trace.ReadOnlySpan) {
+	if !s.SpanContext().IsSampled() {
+		return
+	}
+	notable := p.priority && p.notable(s)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.full() && p.block > 0 {
+		p.wait()
+	}
+	if p.closed {
+		p.onDrop(s)
+		return
+	}
+	if p.full() {
+		if !notable || len(p.low) == 0 {
+			p.onDrop(s)
+			return
+		}
+		p.onDrop(p.low[0])
+		p.low[0] = nil
+		p.low = p.low[1:]
+	}
+	if notable {
+		p.high = append(p.high, s)
+	} else {
+		p.low = append(p.low, s)
+	}
+	p.cond.Broadcast()
+}
+
+func (p *queueProcessor) full() bool {
+	return len(p.high)+len(p.low) >= p.size
+}
+
+// wait waits up to block for room in the queue, p.mu being held.
+func (p *queueProcessor) wait() {
+	expired := false
+	timer := time.AfterFunc(p.block, func() {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		expired = true
+		p.cond.Broadcast()
+	})
+	defer timer.Stop()
+
+	for p.full() && !p.closed && !expired {
+		p.cond.Wait()
+	}
+}
+
+// run hands the queued spans to the batch processor until shut down.
+func (p *queueProcessor) run() {
+	defer close(p.done)
+
+	p.mu.Lock()
+	for {
+		for len(p.high) == 0 && len(p.low) == 0 && !p.closed {
+			p.cond.Wait()
+		}
+		var s trace.ReadOnlySpan
+		switch {
+		case len(p.high) > 0:
+			s, p.high[0], p.high = p.high[0], nil, p.high[1:]
+		case len(p.low) > 0:
+			s, p.low[0], p.low = p.low[0], nil, p.low[1:]
+		default:
+			p.mu.Unlock()
+			return
+		}
+		p.inflight = true
+		p.cond.Broadcast()
+		p.mu.Unlock()
+
+		p.next.OnEnd(s)
+
+		p.mu.Lock()
+		p.inflight = false
+		p.cond.Broadcast()
+	}
+}
+
+// drain waits until the queued spans were handed to the batch processor.
+func (p *queueProcessor) drain(ctx context.Context) error {
+	stop := context.AfterFunc(ctx, func() {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		p.cond.Broadcast()
+	})
+	defer stop()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for len(p.high) > 0 || len(p.low) > 0 || p.inflight {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		p.cond.Wait()
+	}
+
+	return nil
+}
+
+// Shutdown implements the trace.SpanProcessor interface, the spans still
+// queued once ctx is done are dropped and the batch processor shut down.
+func (p *queueProcessor) Shutdown(ctx context.Context) error {
+	err := p.drain(ctx)
+
+	p.mu.Lock()
+	p.closed = true
+	if err != nil {
+		for _, s := range append(p.high, p.low...) {
+			p.onDrop(s)
+		}
+		p.high, p.low = nil, nil
+	}
+	p.cond.Broadcast()
+	p.mu.Unlock()
+	select {
+	case <-p.done:
+	case <-ctx.Done():
+	}
+
+	return errors.Join(err, p.next.Shutdown(ctx))
+}
+
+// ForceFlush implements the trace.SpanProcessor interface.
+func (p *queueProcessor) ForceFlush(ctx context.Context) error {
+	if err := p.drain(ctx); err != nil {
+		return err
+	}
+
+	return p.next.ForceFlush(ctx)
+}
@@ -0,0 +1,134 @@
+package otel
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+// gatedProcessor blocks in OnEnd until its gate is closed, like a batch
+// processor with a full queue, and records the names of the ended spans.
+type gatedProcessor struct {
+	trace.SpanProcessor
+	gate     chan struct{}
+	waiting  atomic.Bool
+	shutdown atomic.Bool
+
+	mu    sync.Mutex
+	names []string
+}
+
+func (p *gatedProcessor) OnEnd(s trace.ReadOnlySpan) {
+	p.waiting.Store(true)
+	<-p.gate
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.names = append(p.names, s.Name())
+}
+
+func (p *gatedProcessor) ForceFlush(context.Context) error {
+	return nil
+}
+
+func (p *gatedProcessor) Shutdown(context.Context) error {
+	p.shutdown.Store(true)
+	return nil
+}
+
+func sampledSpan(name string, status codes.Code) trace.ReadOnlySpan {
+	return tracetest.SpanStub{
+		Name: name,
+		SpanContext: oteltrace.NewSpanContext(oteltrace.SpanContextConfig{
+			TraceID:    oteltrace.TraceID{1},
+			SpanID:     oteltrace.SpanID{1},
+			TraceFlags: oteltrace.FlagsSampled,
+		}),
+		Status: trace.Status{Code: status},
+	}.Snapshot()
+}
+
+func TestQueueProcessor_DropsOKSpansFirst(t *testing.T) {
+	next := &gatedProcessor{gate: make(chan struct{})}
+	var dropped atomic.Int64
+	p := (&Config{MaxQueueSize: 2, PriorityQueue: true}).queueProcessor(next, func(trace.ReadOnlySpan) { dropped.Add(1) })
+
+	p.OnEnd(sampledSpan("exporting", codes.Unset))
+	assert.Eventually(t, next.waiting.Load, time.Second, time.Millisecond)
+	p.OnEnd(sampledSpan("ok 1", codes.Unset))
+	p.OnEnd(sampledSpan("ok 2", codes.Ok))
+	p.OnEnd(sampledSpan("failed", codes.Error))
+	p.OnEnd(sampledSpan("ok 3", codes.Unset))
+	assert.Equal(t, int64(2), dropped.Load())
+
+	close(next.gate)
+	assert.Nil(t, p.ForceFlush(context.TODO()))
+	assert.Equal(t, []string{"exporting", "failed", "ok 2"}, next.names)
+}
+
+func TestQueueProcessor_BlocksOnFullQueue(t *testing.T) {
+	next := &gatedProcessor{gate: make(chan struct{})}
+	var dropped atomic.Int64
+	p := (&Config{MaxQueueSize: 1, BlockOnQueueFull: 50 * time.Millisecond}).queueProcessor(next, func(trace.ReadOnlySpan) { dropped.Add(1) })
+
+	p.OnEnd(sampledSpan("exporting", codes.Unset))
+	assert.Eventually(t, next.waiting.Load, time.Second, time.Millisecond)
+	p.OnEnd(sampledSpan("queued", codes.Unset))
+
+	start := time.Now()
+	p.OnEnd(sampledSpan("timed out", codes.Unset))
+	assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+	assert.Equal(t, int64(1), dropped.Load())
+
+	ended := make(chan struct{})
+	go func() {
+		p.OnEnd(sampledSpan("blocked", codes.Unset))
+		close(ended)
+	}()
+	time.Sleep(10 * time.Millisecond)
+	close(next.gate)
+	<-ended
+	assert.Nil(t, p.ForceFlush(context.TODO()))
+	assert.Equal(t, []string{"exporting", "queued", "blocked"}, next.names)
+	assert.Equal(t, int64(1), dropped.Load())
+}
+
+func TestQueueProcessor_ShutsDownAfterDrainTimeout(t *testing.T) {
+	next := &gatedProcessor{gate: make(chan struct{})}
+	defer close(next.gate)
+	var dropped atomic.Int64
+	p := (&Config{MaxQueueSize: 2}).queueProcessor(next, func(trace.ReadOnlySpan) { dropped.Add(1) })
+
+	p.OnEnd(sampledSpan("exporting", codes.Unset))
+	assert.Eventually(t, next.waiting.Load, time.Second, time.Millisecond)
+	p.OnEnd(sampledSpan("queued", codes.Unset))
+
+	ctx, cancel := context.WithTimeout(context.TODO(), 20*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, p.Shutdown(ctx), context.DeadlineExceeded)
+	assert.True(t, next.shutdown.Load())
+	assert.Equal(t, int64(1), dropped.Load())
+}
+
+func TestQueueProcessor_ExportsOnShutdown(t *testing.T) {
+	var out bytes.Buffer
+	exporter := NewExporter(IO, &Config{Writer: &out, PriorityQueue: true}, WithGlobalRegistration(false))
+	provider, err := exporter.ExportPipeline(context.TODO())
+	assert.Nil(t, err)
+
+	_, span := provider.Tracer("sample").Start(context.TODO(), "queued span")
+	span.End()
+	assert.Nil(t, provider.Shutdown(context.TODO()))
+	assert.Contains(t, out.String(), "queued span")
+
+	snapshot, _ := Snapshot(exporter)
+	assert.Contains(t, snapshot.Processors, "priority_queue")
+}
@@ -0,0 +1,63 @@
+package otel
+
+import (
+	"context"
+	"io"
+
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// SpanRecorder holds the spans ended on the pipeline of the Test output,
+// they're kept once the provider is shut down.
+type SpanRecorder struct {
+	*tracetest.InMemoryExporter
+}
+
+// Spans returns a copy of the spans ended so far, in their end order.
+func (r *SpanRecorder) Spans() tracetest.SpanStubs {
+	return r.GetSpans()
+}
+
+// Shutdown implements the trace.SpanExporter interface, the recorded spans
+// are kept for the assertions following the provider shutdown.
+func (r *SpanRecorder) Shutdown(context.Context) error {
+	return nil
+}
+
+// testOutput exports spans to a SpanRecorder as they end.
+type testOutput struct {
+	ioOutput
+	recorder *SpanRecorder
+}
+
+func newTestOutput(c *Config, opts []Option) *testOutput {
+	recorder := &SpanRecorder{InMemoryExporter: tracetest.NewInMemoryExporter()}
+	output := &testOutput{
+		ioOutput: ioOutput{
+			Config:        c,
+			pipeline:      newPipeline(c, opts),
+			spanExporter:  recorder,
+			defaultWriter: io.Discard,
+		},
+		recorder: recorder,
+	}
+	output.syncExport = true
+
+	return output
+}
+
+// Recorder returns the recorder of the spans of the pipeline built by e for
+// the Test output, false for other outputs:
+//
+//	exporter := otel.NewExporter(otel.Test, &otel.Config{}, otel.WithGlobalRegistration(false))
+//	recorder, _ := otel.Recorder(exporter)
+//	...
+//	assert.Equal(t, "GET /orders", recorder.Spans()[0].Name)
+func Recorder(e Exporter) (*SpanRecorder, bool) {
+	output, ok := e.(*testOutput)
+	if !ok {
+		return nil, false
+	}
+
+	return output.recorder, true
+}
@@ -0,0 +1,44 @@
+package otel
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+func TestRecorder_RecordsEndedSpans(t *testing.T) {
+	exporter := NewExporter(Test, &Config{ServiceName: "orders"}, WithGlobalRegistration(false))
+	recorder, ok := Recorder(exporter)
+	assert.True(t, ok)
+
+	pipeline, err := exporter.ExportPipeline(context.TODO())
+	assert.Nil(t, err)
+
+	_, span := pipeline.Tracer("sample").Start(context.TODO(), "GET /orders", oteltrace.WithAttributes(attribute.String("http.method", "GET")))
+	span.End()
+
+	spans := recorder.Spans()
+	assert.Len(t, spans, 1)
+	assert.Equal(t, "GET /orders", spans[0].Name)
+	assert.Contains(t, spans[0].Attributes, attribute.String("http.method", "GET"))
+
+	recorder.Reset()
+	assert.Empty(t, recorder.Spans())
+
+	_, span = pipeline.Tracer("sample").Start(context.TODO(), "GET /users")
+	span.End()
+	assert.Nil(t, pipeline.Shutdown(context.TODO()))
+	assert.Equal(t, "GET /users", recorder.Spans()[0].Name)
+
+	snapshot, _ := Snapshot(exporter)
+	assert.Equal(t, "test", snapshot.Output)
+	assert.Equal(t, []string{"debug", "counter", "simple"}, snapshot.Processors)
+}
+
+func TestRecorder_UnsupportedExporter(t *testing.T) {
+	_, ok := Recorder(NewExporter(IO, &Config{}))
+	assert.False(t, ok)
+}
@@ -0,0 +1,63 @@
+package otel
+
+import (
+	"context"
+
+	"github.com/rezazadehramin/opentelemetry-go/otel/internal/exception"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+// RecoverOption configures RecoverAndRecord.
+type RecoverOption func(*recoverConfig)
+
+type recoverConfig struct {
+	repanic bool
+	handler func(recovered interface{})
+}
+
+// WithRepanic controls whether the panic is propagated once recorded,
+// enabled by default.
+func WithRepanic(enabled bool) RecoverOption {
+	return func(c *recoverConfig) {
+		c.repanic = enabled
+	}
+}
+
+// WithRecoverHandler calls handler with the recovered value once it's
+// recorded, before propagating it when WithRepanic is enabled.
+func WithRecoverHandler(handler func(recovered interface{})) RecoverOption {
+	return func(c *recoverConfig) {
+		c.handler = handler
+	}
+}
+
+// RecoverAndRecord recovers a panic and records it on the span of ctx as an
+// exception event holding its type, message and stack trace, along with an
+// error status. It must be deferred directly, after the span is ended:
+//
+//	ctx, span := tracer.Start(ctx, "work")
+//	defer span.End()
+//	defer otel.RecoverAndRecord(ctx)
+//
+// The panic is propagated afterwards unless WithRepanic(false) is given,
+// the otelmiddleware, otelgin, otelecho and otelchi middlewares record
+// panics on their server span the same way.
+func RecoverAndRecord(ctx context.Context, opts ...RecoverOption) {
+	recovered := recover()
+	if recovered == nil {
+		return
+	}
+
+	c := recoverConfig{repanic: true}
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	exception.RecordPanic(oteltrace.SpanFromContext(ctx), recovered, c.repanic)
+	if c.handler != nil {
+		c.handler(recovered)
+	}
+	if c.repanic {
+		panic(recovered)
+	}
+}
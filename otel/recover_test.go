@@ -0,0 +1,83 @@
+package otel
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestRecoverAndRecord_RecordsAndRepanics(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := trace.NewTracerProvider(trace.WithSpanProcessor(recorder))
+
+	assert.PanicsWithValue(t, "out of stock", func() {
+		ctx, span := provider.Tracer("test").Start(context.Background(), "work")
+		defer span.End()
+		defer RecoverAndRecord(ctx)
+
+		panic("out of stock")
+	})
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 1)
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	assert.Equal(t, "panic: out of stock", spans[0].Status().Description)
+	// the SDK adds its own exception event when span.End runs while panicking
+	assert.NotEmpty(t, spans[0].Events())
+
+	event := spans[0].Events()[0]
+	assert.Equal(t, "exception", event.Name)
+	assert.Contains(t, event.Attributes, attribute.String("exception.type", "string"))
+	assert.Contains(t, event.Attributes, attribute.String("exception.message", "out of stock"))
+	assert.Contains(t, event.Attributes, attribute.Bool("exception.escaped", true))
+	for _, attr := range event.Attributes {
+		if attr.Key == "exception.stacktrace" {
+			assert.Contains(t, attr.Value.AsString(), "TestRecoverAndRecord_RecordsAndRepanics")
+		}
+	}
+}
+
+func TestRecoverAndRecord_Returns(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := trace.NewTracerProvider(trace.WithSpanProcessor(recorder))
+
+	var handled interface{}
+	assert.NotPanics(t, func() {
+		ctx, span := provider.Tracer("test").Start(context.Background(), "work")
+		defer span.End()
+		defer RecoverAndRecord(ctx, WithRepanic(false), WithRecoverHandler(func(recovered interface{}) {
+			handled = recovered
+		}))
+
+		panic(errors.New("out of stock"))
+	})
+
+	assert.EqualError(t, handled.(error), "out of stock")
+	spans := recorder.Ended()
+	assert.Len(t, spans, 1)
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	assert.Contains(t, spans[0].Events()[0].Attributes, attribute.String("exception.type", "*errors.errorString"))
+	assert.Contains(t, spans[0].Events()[0].Attributes, attribute.Bool("exception.escaped", false))
+}
+
+func TestRecoverAndRecord_NoPanic(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := trace.NewTracerProvider(trace.WithSpanProcessor(recorder))
+
+	func() {
+		ctx, span := provider.Tracer("test").Start(context.Background(), "work")
+		defer span.End()
+		defer RecoverAndRecord(ctx)
+	}()
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 1)
+	assert.Equal(t, codes.Unset, spans[0].Status().Code)
+	assert.Empty(t, spans[0].Events())
+}
@@ -0,0 +1,135 @@
+package otel
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/sdk/trace"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+// Names of the span metrics, the ones of the collector spanmetrics connector
+// so the same dashboards work with either.
+const (
+	SpanMetricsCallsName    = "traces.span.metrics.calls"
+	SpanMetricsDurationName = "traces.span.metrics.duration"
+)
+
+// Attributes of the span metrics.
+const (
+	SpanNameKey       = attribute.Key("span.name")
+	SpanKindKey       = attribute.Key("span.kind")
+	SpanStatusCodeKey = attribute.Key("status.code")
+)
+
+type redInstruments struct {
+	calls    metric.Int64Counter
+	duration metric.Float64Histogram
+}
+
+// REDProcessor is a span processor counting the ended server and consumer
+// spans and recording their duration, by span name, kind and status code.
+// The error rate is the rate of calls with the STATUS_CODE_ERROR status code.
+//
+// Only the spans it's handed are measured, use a sampler recording the spans
+// it doesn't sample to measure every request, like WithSpanMetrics does.
+// Register it on the provider returned by ExportPipeline with RegisterSpanProcessor.
+type REDProcessor struct {
+	instruments atomic.Pointer[redInstruments]
+}
+
+var _ trace.SpanProcessor = (*REDProcessor)(nil)
+
+// NewREDProcessor creates a processor recording the span metrics on mp,
+// or the global MeterProvider when mp is nil.
+func NewREDProcessor(mp metric.MeterProvider) (*REDProcessor, error) {
+	if mp == nil {
+		mp = otel.GetMeterProvider()
+	}
+
+	p := &REDProcessor{}
+	if err := p.register(mp); err != nil {
+		return nil, fmt.Errorf("could not register span metrics: %w", err)
+	}
+
+	return p, nil
+}
+
+// register creates the instruments on mp, spans ended before are not measured.
+func (p *REDProcessor) register(mp metric.MeterProvider) error {
+	meter := mp.Meter(instrumentationName)
+
+	calls, err := meter.Int64Counter(SpanMetricsCallsName,
+		metric.WithDescription("Number of server and consumer spans ended."),
+		metric.WithUnit("{call}"))
+	if err != nil {
+		return err
+	}
+
+	duration, err := meter.Float64Histogram(SpanMetricsDurationName,
+		metric.WithDescription("Duration of server and consumer spans."),
+		metric.WithUnit("s"))
+	if err != nil {
+		return err
+	}
+
+	p.instruments.Store(&redInstruments{calls: calls, duration: duration})
+	return nil
+}
+
+// OnStart implements the trace.SpanProcessor interface.
+func (p *REDProcessor) OnStart(context.Context, trace.ReadWriteSpan) {}
+
+// OnEnd implements the trace.SpanProcessor interface.
+func (p *REDProcessor) OnEnd(s trace.ReadOnlySpan) {
+	instruments := p.instruments.Load()
+	kind := s.SpanKind()
+	if instruments == nil || kind != oteltrace.SpanKindServer && kind != oteltrace.SpanKindConsumer {
+		return
+	}
+
+	attrs := metric.WithAttributeSet(attribute.NewSet(
+		SpanNameKey.String(s.Name()),
+		SpanKindKey.String("SPAN_KIND_"+strings.ToUpper(kind.String())),
+		SpanStatusCodeKey.String("STATUS_CODE_"+strings.ToUpper(s.Status().Code.String())),
+	))
+	ctx := context.Background()
+	instruments.calls.Add(ctx, 1, attrs)
+	instruments.duration.Record(ctx, s.EndTime().Sub(s.StartTime()).Seconds(), attrs)
+}
+
+// Shutdown implements the trace.SpanProcessor interface.
+func (p *REDProcessor) Shutdown(context.Context) error {
+	return nil
+}
+
+// ForceFlush implements the trace.SpanProcessor interface.
+func (p *REDProcessor) ForceFlush(context.Context) error {
+	return nil
+}
+
+// recordOnlySampler records the spans dropped by next without sampling them,
+// so processors see every span while only the sampled ones are exported.
+type recordOnlySampler struct {
+	next trace.Sampler
+}
+
+// ShouldSample implements the trace.Sampler interface.
+func (s recordOnlySampler) ShouldSample(p trace.SamplingParameters) trace.SamplingResult {
+	result := s.next.ShouldSample(p)
+	if result.Decision == trace.Drop {
+		result.Decision = trace.RecordOnly
+	}
+
+	return result
+}
+
+// Description implements the trace.Sampler interface.
+func (s recordOnlySampler) Description() string {
+	return fmt.Sprintf("RecordOnly{%s}", s.next.Description())
+}
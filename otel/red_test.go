@@ -0,0 +1,85 @@
+package otel
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/trace"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+func TestREDProcessor_RecordsServerSpans(t *testing.T) {
+	reader := metric.NewManualReader()
+	red, err := NewREDProcessor(metric.NewMeterProvider(metric.WithReader(reader)))
+	assert.Nil(t, err)
+	tp := trace.NewTracerProvider(trace.WithSpanProcessor(red))
+	defer tp.Shutdown(context.TODO())
+	tracer := tp.Tracer("sample")
+
+	for i := 0; i < 3; i++ {
+		_, span := tracer.Start(context.TODO(), "GET /orders", oteltrace.WithSpanKind(oteltrace.SpanKindServer))
+		if i == 0 {
+			span.SetStatus(codes.Error, "boom")
+		}
+		span.End()
+	}
+	_, internal := tracer.Start(context.TODO(), "load order")
+	internal.End()
+
+	var rm metricdata.ResourceMetrics
+	assert.Nil(t, reader.Collect(context.TODO(), &rm))
+	calls := rm.ScopeMetrics[0].Metrics[0]
+	assert.Equal(t, SpanMetricsCallsName, calls.Name)
+
+	counts := map[string]int64{}
+	for _, dp := range calls.Data.(metricdata.Sum[int64]).DataPoints {
+		code, _ := dp.Attributes.Value(SpanStatusCodeKey)
+		kind, _ := dp.Attributes.Value(SpanKindKey)
+		assert.Equal(t, "SPAN_KIND_SERVER", kind.AsString())
+		counts[code.AsString()] = dp.Value
+	}
+	assert.Equal(t, map[string]int64{"STATUS_CODE_ERROR": 1, "STATUS_CODE_UNSET": 2}, counts)
+
+	duration := rm.ScopeMetrics[0].Metrics[1]
+	assert.Equal(t, SpanMetricsDurationName, duration.Name)
+	assert.Len(t, duration.Data.(metricdata.Histogram[float64]).DataPoints, 2)
+}
+
+func TestExporter_SpanMetricsMeasureUnsampledSpans(t *testing.T) {
+	var out bytes.Buffer
+	exporter := NewExporter(IO, &Config{Writer: &out}, WithGlobalRegistration(false), WithSpanMetrics())
+
+	meterProvider, err := ExportMetricPipeline(context.TODO(), exporter)
+	assert.Nil(t, err)
+	defer meterProvider.Shutdown(context.TODO())
+	pipeline, err := exporter.ExportPipeline(context.TODO())
+	assert.Nil(t, err)
+	defer pipeline.Shutdown(context.TODO())
+
+	parent := oteltrace.NewSpanContext(oteltrace.SpanContextConfig{
+		TraceID: oteltrace.TraceID{1},
+		SpanID:  oteltrace.SpanID{1},
+		Remote:  true,
+	})
+	ctx := oteltrace.ContextWithRemoteSpanContext(context.TODO(), parent)
+	_, span := pipeline.Tracer("sample").Start(ctx, "GET /unsampled", oteltrace.WithSpanKind(oteltrace.SpanKindServer),
+		oteltrace.WithAttributes(attribute.String("http.method", "GET")))
+	assert.False(t, span.SpanContext().IsSampled())
+	span.End()
+
+	assert.Nil(t, pipeline.ForceFlush(context.TODO()))
+	assert.NotContains(t, out.String(), "GET /unsampled")
+
+	assert.Nil(t, meterProvider.ForceFlush(context.TODO()))
+	assert.Contains(t, out.String(), SpanMetricsCallsName)
+	assert.Contains(t, out.String(), "GET /unsampled")
+
+	snapshot, _ := Snapshot(exporter)
+	assert.Equal(t, []string{"debug", "counter", "span_metrics", "batch"}, snapshot.Processors)
+}
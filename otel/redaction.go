@@ -0,0 +1,247 @@
+package otel
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"slices"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/trace"
+)
+
+// RedactedValue replaces the matches of the redaction rules not hashing them.
+const RedactedValue = "[REDACTED]"
+
+// RedactionRule replaces the parts of string attribute values matching Pattern,
+// with RedactedValue or the "hmac:" prefixed HMAC-SHA256 of the match keyed
+// with HashKey when set so redacted values can still be correlated. Valid, when
+// set, filters out false positives of Pattern.
+//
+// Hashing pseudonymizes the matches rather than anonymizing them: anyone
+// holding HashKey can tell whether a value was redacted to a given hash, keep
+// it secret and rotate it like other credentials.
+type RedactionRule struct {
+	Pattern *regexp.Regexp
+	Valid   func(match string) bool
+	HashKey []byte
+}
+
+// Redaction rules of the kinds of data most often leaking into attributes.
+var (
+	EmailRedaction = RedactionRule{
+		Pattern: regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`),
+	}
+	CreditCardRedaction = RedactionRule{
+		Pattern: regexp.MustCompile(`\b\d(?:[ \-]?\d){12,18}\b`),
+		Valid:   luhn,
+	}
+	BearerTokenRedaction = RedactionRule{
+		Pattern: regexp.MustCompile(`(?i)\bbearer\s+[A-Za-z0-9\-._~+/]+=*`),
+	}
+)
+
+// redactions are the supported Config.Redactions and OTEL_REDACTIONS values.
+var redactions = map[string]RedactionRule{
+	"email":        EmailRedaction,
+	"credit_card":  CreditCardRedaction,
+	"bearer_token": BearerTokenRedaction,
+}
+
+// luhn reports whether the digits of s pass the Luhn checksum of card numbers.
+func luhn(s string) bool {
+	var sum, n int
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+		d := int(c - '0')
+		if n%2 == 1 {
+			if d *= 2; d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		n++
+	}
+
+	return n > 0 && sum%10 == 0
+}
+
+// redactString applies the rules to s, it reports whether s changed.
+func redactString(s string, rules []RedactionRule) (string, bool) {
+	changed := false
+	for _, rule := range rules {
+		s = rule.Pattern.ReplaceAllStringFunc(s, func(match string) string {
+			if rule.Valid != nil && !rule.Valid(match) {
+				return match
+			}
+			changed = true
+			if len(rule.HashKey) > 0 {
+				mac := hmac.New(sha256.New, rule.HashKey)
+				mac.Write([]byte(match))
+				return "hmac:" + hex.EncodeToString(mac.Sum(nil)[:16])
+			}
+			return RedactedValue
+		})
+	}
+
+	return s, changed
+}
+
+// redactAttributes returns attrs with their string values redacted,
+// attrs itself when none changed.
+func redactAttributes(attrs []attribute.KeyValue, rules []RedactionRule) ([]attribute.KeyValue, bool) {
+	var redacted []attribute.KeyValue
+	for i, kv := range attrs {
+		value, changed := redactValue(kv.Value, rules)
+		if !changed {
+			continue
+		}
+		if redacted == nil {
+			redacted = append([]attribute.KeyValue(nil), attrs...)
+		}
+		redacted[i] = attribute.KeyValue{Key: kv.Key, Value: value}
+	}
+	if redacted == nil {
+		return attrs, false
+	}
+
+	return redacted, true
+}
+
+func redactValue(v attribute.Value, rules []RedactionRule) (attribute.Value, bool) {
+	switch v.Type() {
+	case attribute.STRING:
+		s, changed := redactString(v.AsString(), rules)
+		return attribute.StringValue(s), changed
+	case attribute.STRINGSLICE:
+		values := v.AsStringSlice()
+		changed := false
+		for i, s := range values {
+			var c bool
+			values[i], c = redactString(s, rules)
+			changed = changed || c
+		}
+		return attribute.StringSliceValue(values), changed
+	}
+
+	return v, false
+}
+
+// mappedSpan is an ended span with its attributes, events, links and status
+// replaced by the processors scrubbing them.
+type mappedSpan struct {
+	trace.ReadOnlySpan
+	attributes []attribute.KeyValue
+	events     []trace.Event
+	links      []trace.Link
+	status     trace.Status
+}
+
+// Attributes implements the trace.ReadOnlySpan interface.
+func (s *mappedSpan) Attributes() []attribute.KeyValue { return s.attributes }
+
+// Events implements the trace.ReadOnlySpan interface.
+func (s *mappedSpan) Events() []trace.Event { return s.events }
+
+// Links implements the trace.ReadOnlySpan interface.
+func (s *mappedSpan) Links() []trace.Link { return s.links }
+
+// Status implements the trace.ReadOnlySpan interface.
+func (s *mappedSpan) Status() trace.Status { return s.status }
+
+// mapAttributes returns the span with the attributes of the span, its events
+// and links replaced by the ones returned by f, it reports whether f changed any.
+func mapAttributes(s trace.ReadOnlySpan, f func([]attribute.KeyValue) ([]attribute.KeyValue, bool)) (*mappedSpan, bool) {
+	span := &mappedSpan{
+		ReadOnlySpan: s,
+		events:       slices.Clone(s.Events()),
+		links:        slices.Clone(s.Links()),
+		status:       s.Status(),
+	}
+
+	var mapped, changed bool
+	span.attributes, mapped = f(s.Attributes())
+	span.attributes = slices.Clip(span.attributes)
+	for i := range span.events {
+		span.events[i].Attributes, changed = f(span.events[i].Attributes)
+		mapped = mapped || changed
+	}
+	for i := range span.links {
+		span.links[i].Attributes, changed = f(span.links[i].Attributes)
+		mapped = mapped || changed
+	}
+
+	return span, mapped
+}
+
+// RedactionProcessor is a span processor scrubbing the attributes, events,
+// links and status description of ended spans before handing them to next,
+// usually the batch processor of the exporter. Set Config.Redactions or
+// Config.RedactionRules, or wrap a processor and register it on the provider
+// returned by ExportPipeline with RegisterSpanProcessor.
+//
+// Spans are redacted once ended, the processors registered along with the
+// exporter one still see the original values.
+type RedactionProcessor struct {
+	next  trace.SpanProcessor
+	rules []RedactionRule
+}
+
+var _ trace.SpanProcessor = (*RedactionProcessor)(nil)
+
+// NewRedactionProcessor creates a processor applying the rules to the spans
+// before handing them to next.
+func NewRedactionProcessor(next trace.SpanProcessor, rules ...RedactionRule) *RedactionProcessor {
+	return &RedactionProcessor{next: next, rules: rules}
+}
+
+// OnStart implements the trace.SpanProcessor interface.
+func (p *RedactionProcessor) OnStart(ctx context.Context, s trace.ReadWriteSpan) {
+	p.next.OnStart(ctx, s)
+}
+
+// OnEnd implements the trace.SpanProcessor interface.
+func (p *RedactionProcessor) OnEnd(s trace.ReadOnlySpan) {
+	span, redacted := mapAttributes(s, func(attrs []attribute.KeyValue) ([]attribute.KeyValue, bool) {
+		return redactAttributes(attrs, p.rules)
+	})
+	var changed bool
+	span.status.Description, changed = redactString(span.status.Description, p.rules)
+	redacted = redacted || changed
+
+	if !redacted {
+		p.next.OnEnd(s)
+		return
+	}
+	p.next.OnEnd(span)
+}
+
+// Shutdown implements the trace.SpanProcessor interface.
+func (p *RedactionProcessor) Shutdown(ctx context.Context) error {
+	return p.next.Shutdown(ctx)
+}
+
+// ForceFlush implements the trace.SpanProcessor interface.
+func (p *RedactionProcessor) ForceFlush(ctx context.Context) error {
+	return p.next.ForceFlush(ctx)
+}
+
+// redactionRules returns the rules of Config.Redactions followed by Config.RedactionRules.
+func (c *Config) redactionRules() ([]RedactionRule, error) {
+	var rules []RedactionRule
+	for _, name := range c.Redactions {
+		rule, ok := redactions[name]
+		if !ok {
+			return nil, fmt.Errorf("unsupported redaction %q", name)
+		}
+		rules = append(rules, rule)
+	}
+
+	return append(rules, c.RedactionRules...), nil
+}
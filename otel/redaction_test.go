@@ -0,0 +1,64 @@
+package otel
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+func TestRedactionProcessor_ScrubsMatches(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	hashedEmail := EmailRedaction
+	hashedEmail.HashKey = []byte("pseudonym key")
+	tp := trace.NewTracerProvider(trace.WithSpanProcessor(NewRedactionProcessor(recorder,
+		hashedEmail, CreditCardRedaction, BearerTokenRedaction)))
+	defer tp.Shutdown(context.TODO())
+
+	_, span := tp.Tracer("sample").Start(context.TODO(), "checkout", oteltrace.WithAttributes(
+		attribute.String("user.email", "jane@example.com"),
+		attribute.String("payment.card", "4111 1111 1111 1111"),
+		attribute.String("order.id", "1234567890123"),
+		attribute.StringSlice("http.request.header.authorization", []string{"Bearer abc.def-ghi"}),
+	))
+	span.AddEvent("charged", oteltrace.WithAttributes(attribute.String("card", "4111111111111111")))
+	span.SetStatus(codes.Error, "card 4111111111111111 declined")
+	span.End()
+
+	ended := recorder.Ended()[0]
+	attrs := ended.Attributes()
+	assert.Equal(t, "hmac:dc0167b6366ca77f7e95415e7422a063", attrs[0].Value.AsString())
+	assert.Equal(t, RedactedValue, attrs[1].Value.AsString())
+	assert.Equal(t, "1234567890123", attrs[2].Value.AsString(), "numbers failing the Luhn check are kept")
+	assert.Equal(t, []string{RedactedValue}, attrs[3].Value.AsStringSlice())
+	assert.Equal(t, RedactedValue, ended.Events()[0].Attributes[0].Value.AsString())
+	assert.Equal(t, "card [REDACTED] declined", ended.Status().Description)
+}
+
+func TestRedactionProcessor_ConfiguredOnPipeline(t *testing.T) {
+	setEnv()
+	defer unsetEnv()
+
+	c := NewENVConfig()
+	c.Writer = io.Discard
+	c.Redactions = []string{"email"}
+	exporter := NewExporter(IO, c)
+
+	pipeline, err := exporter.ExportPipeline(context.TODO())
+	assert.Nil(t, err)
+	defer pipeline.Shutdown(context.TODO())
+
+	snapshot, _ := Snapshot(exporter)
+	assert.Equal(t, []string{"debug", "counter", "redaction", "batch"}, snapshot.Processors)
+
+	c = NewENVConfig()
+	c.Redactions = []string{"ssn"}
+	_, err = NewExporter(IO, c).ExportPipeline(context.TODO())
+	assert.ErrorContains(t, err, `unsupported redaction "ssn"`)
+}
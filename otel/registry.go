@@ -0,0 +1,162 @@
+package otel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"go.opentelemetry.io/otel/sdk/trace"
+)
+
+// Registry holds named pipelines built independently of each other, like an
+// "audit" pipeline exporting every span to its own endpoint next to the
+// sampled "default" one. Its pipelines are never registered globally, get
+// their providers from it:
+//
+//	registry := otel.NewRegistry()
+//	_, err := registry.Register(ctx, "default", otel.GRPC, otel.NewENVConfig())
+//	...
+//	_, err = registry.Register(ctx, "audit", otel.GRPC, auditConfig)
+//	...
+//	defer registry.ShutdownAll(context.Background())
+//
+//	provider, _ := registry.TracerProvider("audit")
+//	tracer := provider.Tracer("payments")
+type Registry struct {
+	mu        sync.Mutex
+	exporters map[string]Exporter
+	// building holds the names of the pipelines being built, reserved
+	// while the registry is unlocked.
+	building map[string]bool
+}
+
+// NewRegistry creates an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{exporters: make(map[string]Exporter), building: make(map[string]bool)}
+}
+
+// Register builds the pipeline name of outputType from c, like ExportPipeline
+// on the exporter returned by NewExporter. Names must be unique, a pipeline
+// shut down frees its name.
+func (r *Registry) Register(ctx context.Context, name string, outputType OutputType, c *Config, opts ...Option) (*trace.TracerProvider, error) {
+	r.mu.Lock()
+	if _, ok := r.exporters[name]; ok || r.building[name] {
+		r.mu.Unlock()
+		return nil, fmt.Errorf("pipeline %q already registered", name)
+	}
+	r.building[name] = true
+	r.mu.Unlock()
+
+	// The pipeline is built unlocked, its detectors and connection not
+	// blocking the other pipelines of the registry.
+	exporter, provider, err := buildPipeline(ctx, outputType, c, append(opts, WithGlobalRegistration(false))...)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.building, name)
+	if err != nil {
+		return nil, fmt.Errorf("could not build pipeline %q: %w", name, err)
+	}
+	r.exporters[name] = exporter
+
+	return provider, nil
+}
+
+// Exporter returns the exporter of the pipeline name, for ExportMetricPipeline,
+// ExportLogPipeline and the handlers of this package.
+func (r *Registry) Exporter(name string) (Exporter, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	exporter, ok := r.exporters[name]
+	return exporter, ok
+}
+
+// TracerProvider returns the tracer provider of the pipeline name.
+func (r *Registry) TracerProvider(name string) (*trace.TracerProvider, bool) {
+	exporter, ok := r.Exporter(name)
+	if !ok {
+		return nil, false
+	}
+	p, _ := pipelineOf(exporter)
+
+	return p.current(), true
+}
+
+// Names returns the names of the registered pipelines, sorted.
+func (r *Registry) Names() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	names := make([]string, 0, len(r.exporters))
+	for name := range r.exporters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// Shutdown shuts down the tracer, meter and logger providers of the pipeline
+// name and removes it from the registry.
+func (r *Registry) Shutdown(ctx context.Context, name string) error {
+	r.mu.Lock()
+	exporter, ok := r.exporters[name]
+	delete(r.exporters, name)
+	r.mu.Unlock()
+
+	if !ok {
+		return fmt.Errorf("pipeline %q not registered", name)
+	}
+	p, _ := pipelineOf(exporter)
+	if err := p.shutdown(ctx); err != nil {
+		return fmt.Errorf("could not shut down pipeline %q: %w", name, err)
+	}
+
+	return nil
+}
+
+// ShutdownAll shuts down every pipeline of the registry, see Shutdown.
+func (r *Registry) ShutdownAll(ctx context.Context) error {
+	var errs []error
+	for _, name := range r.Names() {
+		errs = append(errs, r.Shutdown(ctx, name))
+	}
+
+	return errors.Join(errs...)
+}
+
+// shutdown shuts down the providers built from p.
+func (p *pipeline) shutdown(ctx context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var errs []error
+	if p.provider != nil {
+		errs = append(errs, p.provider.Shutdown(ctx))
+	}
+	if p.meterProvider != nil {
+		errs = append(errs, p.meterProvider.Shutdown(ctx))
+	}
+	if p.loggerProvider != nil {
+		errs = append(errs, p.loggerProvider.Shutdown(ctx))
+	}
+
+	return errors.Join(errs...)
+}
+
+// buildPipeline builds the pipeline of outputType from c.
+func buildPipeline(ctx context.Context, outputType OutputType, c *Config, opts ...Option) (Exporter, *trace.TracerProvider, error) {
+	exporter := NewExporter(outputType, c, opts...)
+	if exporter == nil {
+		return nil, nil, fmt.Errorf("unsupported output type %d", outputType)
+	}
+	provider, err := exporter.ExportPipeline(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return exporter, provider, nil
+}
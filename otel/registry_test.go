@@ -0,0 +1,48 @@
+package otel
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/sdk/trace"
+)
+
+func TestRegistry_IndependentPipelines(t *testing.T) {
+	global := otel.GetTracerProvider()
+	registry := NewRegistry()
+
+	_, err := registry.Register(context.TODO(), "default", Test, &Config{Sampler: trace.NeverSample()})
+	assert.Nil(t, err)
+	audit, err := registry.Register(context.TODO(), "audit", Test, &Config{})
+	assert.Nil(t, err)
+	_, err = registry.Register(context.TODO(), "audit", IO, &Config{})
+	assert.EqualError(t, err, `pipeline "audit" already registered`)
+	assert.Equal(t, []string{"audit", "default"}, registry.Names())
+	assert.Equal(t, global, otel.GetTracerProvider())
+
+	provider, ok := registry.TracerProvider("default")
+	assert.True(t, ok)
+	_, span := provider.Tracer("sample").Start(context.TODO(), "sampled out")
+	span.End()
+	_, span = audit.Tracer("sample").Start(context.TODO(), "payment captured")
+	span.End()
+
+	exporter, _ := registry.Exporter("default")
+	recorder, _ := Recorder(exporter)
+	assert.Empty(t, recorder.Spans())
+	exporter, _ = registry.Exporter("audit")
+	recorder, _ = Recorder(exporter)
+	assert.Equal(t, "payment captured", recorder.Spans()[0].Name)
+
+	_, err = ExportMetricPipeline(context.TODO(), exporter)
+	assert.Nil(t, err)
+	assert.Nil(t, registry.Shutdown(context.TODO(), "audit"))
+	assert.EqualError(t, registry.Shutdown(context.TODO(), "audit"), `pipeline "audit" not registered`)
+	_, ok = registry.TracerProvider("audit")
+	assert.False(t, ok)
+
+	assert.Nil(t, registry.ShutdownAll(context.TODO()))
+	assert.Empty(t, registry.Names())
+}
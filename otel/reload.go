@@ -0,0 +1,279 @@
+package otel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/sdk/trace"
+	"go.yaml.in/yaml/v3"
+)
+
+// defaultWatchInterval is how often WatchConfig checks the file by default.
+const defaultWatchInterval = 2 * time.Second
+
+// ReloadableConfig holds the settings of a config file ReloadConfig applies
+// to a running pipeline, in YAML or JSON:
+//
+//	sampling_ratio: 0.25
+//	drop_spans: ["GET /healthz", "url.path=/static/*"]
+//	batch:
+//	  max_export_batch_size: 512
+//	  max_queue_size: 2048
+//	  timeout: 5s
+//
+// Settings missing from the file restore their default: the output sampler
+// without sampling_ratio, Config.DropSpans alone without drop_spans and the
+// output batch settings without batch.
+type ReloadableConfig struct {
+	SamplingRatio *float64 `yaml:"sampling_ratio"`
+	DropSpans     []string `yaml:"drop_spans"`
+	Batch         struct {
+		MaxExportBatchSize int           `yaml:"max_export_batch_size"`
+		MaxQueueSize       int           `yaml:"max_queue_size"`
+		Timeout            time.Duration `yaml:"timeout"`
+	} `yaml:"batch"`
+}
+
+// ReloadConfig applies the ReloadableConfig of the file at path to the
+// pipeline built by e, once ExportPipeline succeeded.
+func ReloadConfig(ctx context.Context, e Exporter, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("could not read config: %w", err)
+	}
+	var c ReloadableConfig
+	if err := yaml.Unmarshal(data, &c); err != nil {
+		return fmt.Errorf("could not parse config %s: %w", path, err)
+	}
+
+	return ApplyConfig(ctx, e, c)
+}
+
+// ApplyConfig applies c to the pipeline built by e like ReloadConfig,
+// for configs coming from elsewhere than a file.
+func ApplyConfig(ctx context.Context, e Exporter, c ReloadableConfig) error {
+	p, ok := pipelineOf(e)
+	if !ok {
+		return errors.New("unsupported exporter")
+	}
+	if p.current() == nil {
+		return errors.New("pipeline not started")
+	}
+	if p.config.Disabled {
+		return nil
+	}
+
+	return p.reload(ctx, c)
+}
+
+func (p *pipeline) reload(ctx context.Context, c ReloadableConfig) error {
+	if c.SamplingRatio != nil && (*c.SamplingRatio < 0 || *c.SamplingRatio > 1) {
+		return fmt.Errorf("sampling_ratio must be between 0 and 1, got %v", *c.SamplingRatio)
+	}
+
+	if c.SamplingRatio != nil {
+		p.sampler.setRatio(*c.SamplingRatio)
+	} else {
+		p.sampler.setRatio(-1)
+	}
+
+	matchers := slices.Clone(p.config.DropSpans)
+	for _, s := range c.DropSpans {
+		matchers = append(matchers, ParseSpanMatcher(s))
+	}
+	p.drops.matchers.Store(&matchers)
+
+	if p.batch != nil {
+		return p.batch.reload(ctx, batchSettings{
+			maxExportBatchSize: c.Batch.MaxExportBatchSize,
+			maxQueueSize:       c.Batch.MaxQueueSize,
+			timeout:            c.Batch.Timeout,
+		})
+	}
+
+	return nil
+}
+
+// WatchConfig applies the config file at path to the pipeline built by e
+// like ReloadConfig, then again every time it changes until ctx is done,
+// checking it every interval (2s when <= 0). Only the first reload error
+// is returned, later ones are reported to the error handler:
+//
+//	if err := otel.WatchConfig(ctx, exporter, "/etc/otel/otel.yaml", 0); err != nil {
+//		...
+//	}
+func WatchConfig(ctx context.Context, e Exporter, path string, interval time.Duration) error {
+	if interval <= 0 {
+		interval = defaultWatchInterval
+	}
+	last, _ := os.Stat(path)
+	if err := ReloadConfig(ctx, e, path); err != nil {
+		return err
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			info, err := os.Stat(path)
+			if err != nil || (last != nil && info.ModTime().Equal(last.ModTime()) && info.Size() == last.Size()) {
+				continue
+			}
+			last = info
+			if err := ReloadConfig(ctx, e, path); err != nil {
+				handle(errorKindReload, fmt.Errorf("could not reload config: %w", err))
+			}
+		}
+	}()
+
+	return nil
+}
+
+// dropRules drops the spans matched by its matchers, replaced on reload.
+type dropRules struct {
+	next     trace.Sampler
+	matchers atomic.Pointer[[]SpanMatcher]
+}
+
+func newDropRules(next trace.Sampler, matchers []SpanMatcher) *dropRules {
+	d := &dropRules{next: next}
+	d.matchers.Store(&matchers)
+
+	return d
+}
+
+func (d *dropRules) sampler() trace.Sampler {
+	matchers := *d.matchers.Load()
+	if len(matchers) == 0 {
+		return d.next
+	}
+
+	return dropSampler{next: d.next, matchers: matchers}
+}
+
+// ShouldSample implements the trace.Sampler interface.
+func (d *dropRules) ShouldSample(p trace.SamplingParameters) trace.SamplingResult {
+	return d.sampler().ShouldSample(p)
+}
+
+// Description implements the trace.Sampler interface.
+func (d *dropRules) Description() string {
+	return d.sampler().Description()
+}
+
+// batchSettings are the batch processor settings changed on reload,
+// zero values being the output defaults.
+type batchSettings struct {
+	maxExportBatchSize int
+	maxQueueSize       int
+	timeout            time.Duration
+}
+
+// reloadableBatch is a batch processor rebuilt when its settings change,
+// the spans queued by the previous one are exported before it's dropped.
+type reloadableBatch struct {
+	exp  trace.SpanExporter
+	opts []trace.BatchSpanProcessorOption
+
+	mu       sync.RWMutex
+	current  trace.SpanProcessor
+	settings batchSettings
+}
+
+var _ trace.SpanProcessor = (*reloadableBatch)(nil)
+
+func newReloadableBatch(exp trace.SpanExporter, opts ...trace.BatchSpanProcessorOption) *reloadableBatch {
+	return &reloadableBatch{
+		exp:     exp,
+		opts:    opts,
+		current: trace.NewBatchSpanProcessor(keepOpenExporter{exp}, opts...),
+	}
+}
+
+func (b *reloadableBatch) reload(ctx context.Context, settings batchSettings) error {
+	b.mu.Lock()
+	if settings == b.settings {
+		b.mu.Unlock()
+		return nil
+	}
+	opts := slices.Clip(b.opts)
+	if settings.maxExportBatchSize > 0 {
+		opts = append(opts, trace.WithMaxExportBatchSize(settings.maxExportBatchSize))
+	}
+	if settings.maxQueueSize > 0 {
+		opts = append(opts, trace.WithMaxQueueSize(settings.maxQueueSize))
+	}
+	if settings.timeout > 0 {
+		opts = append(opts, trace.WithBatchTimeout(settings.timeout))
+	}
+	previous := b.current
+	b.current = trace.NewBatchSpanProcessor(keepOpenExporter{b.exp}, opts...)
+	b.settings = settings
+	b.mu.Unlock()
+
+	return previous.Shutdown(ctx)
+}
+
+// OnStart implements the trace.SpanProcessor interface.
+func (b *reloadableBatch) OnStart(ctx context.Context, s trace.ReadWriteSpan) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	b.current.OnStart(ctx, s)
+}
+
+// OnEnd implements the trace.SpanProcessor interface.
+func (b *reloadableBatch) OnEnd(s trace.ReadOnlySpan) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	b.current.OnEnd(s)
+}
+
+// Shutdown implements the trace.SpanProcessor interface.
+func (b *reloadableBatch) Shutdown(ctx context.Context) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	return errors.Join(b.current.Shutdown(ctx), b.exp.Shutdown(ctx))
+}
+
+// ForceFlush implements the trace.SpanProcessor interface, waiting for the
+// export workers to export the batches they took.
+func (b *reloadableBatch) ForceFlush(ctx context.Context) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if err := b.current.ForceFlush(ctx); err != nil {
+		return err
+	}
+	if workers, ok := b.exp.(*workerExporter); ok {
+		return workers.flush(ctx)
+	}
+
+	return nil
+}
+
+// keepOpenExporter leaves its exporter open when the batch processor
+// using it is replaced, reloadableBatch shuts it down.
+type keepOpenExporter struct {
+	trace.SpanExporter
+}
+
+// Shutdown implements the trace.SpanExporter interface.
+func (keepOpenExporter) Shutdown(context.Context) error {
+	return nil
+}
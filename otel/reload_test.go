@@ -0,0 +1,89 @@
+package otel
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+func TestReloadConfig_AppliesToRunningPipeline(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "otel.yaml")
+	exporter := NewExporter(IO, &Config{Writer: &toggleWriter{}, DropSpans: []SpanMatcher{{Name: "GET /healthz"}}}, WithGlobalRegistration(false))
+	assert.EqualError(t, ApplyConfig(context.TODO(), exporter, ReloadableConfig{}), "pipeline not started")
+
+	pipeline, err := exporter.ExportPipeline(context.TODO())
+	assert.Nil(t, err)
+	defer pipeline.Shutdown(context.TODO())
+	p, _ := pipelineOf(exporter)
+
+	assert.Nil(t, os.WriteFile(path, []byte(`
+sampling_ratio: 0.25
+drop_spans: ["url.path=/static/*"]
+batch:
+  max_export_batch_size: 10
+  timeout: 10ms
+`), 0o600))
+	assert.Nil(t, ReloadConfig(context.TODO(), exporter, path))
+	assert.Equal(t, 0.25, p.sampler.ratio())
+	assert.Equal(t, batchSettings{maxExportBatchSize: 10, timeout: 10 * time.Millisecond}, p.batch.settings)
+
+	_, span := pipeline.Tracer("sample").Start(context.TODO(), "GET /static/app.js",
+		oteltrace.WithAttributes(attribute.String("url.path", "/static/app.js")))
+	assert.False(t, span.IsRecording())
+	_, span = pipeline.Tracer("sample").Start(context.TODO(), "GET /healthz")
+	assert.False(t, span.IsRecording())
+
+	assert.Nil(t, os.WriteFile(path, []byte(`{"sampling_ratio": 2}`), 0o600))
+	assert.EqualError(t, ReloadConfig(context.TODO(), exporter, path), "sampling_ratio must be between 0 and 1, got 2")
+
+	assert.Nil(t, os.WriteFile(path, []byte(`{}`), 0o600))
+	assert.Nil(t, ReloadConfig(context.TODO(), exporter, path))
+	assert.Equal(t, float64(-1), p.sampler.ratio())
+	assert.Equal(t, batchSettings{}, p.batch.settings)
+	_, span = pipeline.Tracer("sample").Start(context.TODO(), "GET /static/app.js",
+		oteltrace.WithAttributes(attribute.String("url.path", "/static/app.js")))
+	assert.True(t, span.IsRecording())
+}
+
+func TestReloadableBatch_ExportsQueuedSpansOnReload(t *testing.T) {
+	var out bytes.Buffer
+	exporter := NewExporter(IO, &Config{Writer: &out}, WithGlobalRegistration(false))
+	pipeline, err := exporter.ExportPipeline(context.TODO())
+	assert.Nil(t, err)
+	defer pipeline.Shutdown(context.TODO())
+	p, _ := pipelineOf(exporter)
+
+	_, span := pipeline.Tracer("sample").Start(context.TODO(), "sample span")
+	span.End()
+	assert.Nil(t, p.batch.reload(context.TODO(), batchSettings{maxQueueSize: 10}))
+	assert.Contains(t, out.String(), `"Name":"sample span"`)
+
+	_, span = pipeline.Tracer("sample").Start(context.TODO(), "after reload")
+	span.End()
+	assert.Nil(t, pipeline.Shutdown(context.TODO()))
+	assert.Contains(t, out.String(), `"Name":"after reload"`)
+}
+
+func TestWatchConfig_ReloadsOnChange(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "otel.yaml")
+	assert.Nil(t, os.WriteFile(path, []byte("sampling_ratio: 0.5\n"), 0o600))
+	exporter := NewExporter(Test, &Config{}, WithGlobalRegistration(false))
+	_, err := exporter.ExportPipeline(context.TODO())
+	assert.Nil(t, err)
+	p, _ := pipelineOf(exporter)
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+	assert.Nil(t, WatchConfig(ctx, exporter, path, 10*time.Millisecond))
+	assert.Equal(t, 0.5, p.sampler.ratio())
+
+	assert.Nil(t, os.WriteFile(path, []byte("sampling_ratio: 1\n"), 0o600))
+	assert.Eventually(t, func() bool { return p.sampler.ratio() == 1 }, time.Second, 10*time.Millisecond)
+}
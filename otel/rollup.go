@@ -0,0 +1,142 @@
+package otel
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+// Rollup attributes recorded on parent spans by the RollupProcessor.
+const (
+	RollupChildCountKey       = attribute.Key("rollup.child_count")
+	RollupDBDurationKey       = attribute.Key("rollup.db.duration")
+	RollupExternalDurationKey = attribute.Key("rollup.external.duration")
+)
+
+var (
+	// rollupMaxAge is how long a span is tracked for its children, spans
+	// never ended being forgotten after it.
+	rollupMaxAge = 10 * time.Minute
+	// rollupMaxSpans bounds the spans tracked, the spans started over it
+	// don't get rollup attributes.
+	rollupMaxSpans = 100000
+)
+
+type rollup struct {
+	span     trace.ReadWriteSpan
+	children int64
+	db       time.Duration
+	external time.Duration
+	expires  time.Time
+}
+
+// RollupProcessor is a span processor summarizing the children of a span
+// on the span itself, giving a per request breakdown without walking the trace.
+//
+// Parents get the number of their direct children and the time spent in
+// database (spans with a db.system attribute) and external calls (other client
+// and producer spans) by all their descendants, as Duration attributes.
+// Only children ending before their parent are accounted for, and spans
+// lasting over 10 minutes or started while 100000 spans are running don't
+// get the attributes. Register it on the provider returned by ExportPipeline
+// with RegisterSpanProcessor.
+type RollupProcessor struct {
+	mu        sync.Mutex
+	spans     map[oteltrace.SpanID]*rollup
+	nextSweep time.Time
+}
+
+var _ trace.SpanProcessor = (*RollupProcessor)(nil)
+
+// NewRollupProcessor creates a processor recording the rollup attributes.
+func NewRollupProcessor() *RollupProcessor {
+	return &RollupProcessor{spans: make(map[oteltrace.SpanID]*rollup)}
+}
+
+// OnStart implements the trace.SpanProcessor interface.
+func (p *RollupProcessor) OnStart(_ context.Context, s trace.ReadWriteSpan) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	p.sweep(now)
+	if len(p.spans) >= rollupMaxSpans {
+		return
+	}
+	p.spans[s.SpanContext().SpanID()] = &rollup{span: s, expires: now.Add(rollupMaxAge)}
+}
+
+// sweep forgets the spans tracked for longer than rollupMaxAge,
+// at most once per second.
+func (p *RollupProcessor) sweep(now time.Time) {
+	if now.Before(p.nextSweep) {
+		return
+	}
+	p.nextSweep = now.Add(time.Second)
+
+	for id, r := range p.spans {
+		if now.After(r.expires) {
+			delete(p.spans, id)
+		}
+	}
+}
+
+// OnEnd implements the trace.SpanProcessor interface.
+func (p *RollupProcessor) OnEnd(s trace.ReadOnlySpan) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	child := p.spans[s.SpanContext().SpanID()]
+	delete(p.spans, s.SpanContext().SpanID())
+
+	parent, ok := p.spans[s.Parent().SpanID()]
+	if !ok {
+		return
+	}
+
+	duration := s.EndTime().Sub(s.StartTime())
+	parent.children++
+	switch {
+	case isDBSpan(s):
+		parent.db += duration
+	case s.SpanKind() == oteltrace.SpanKindClient || s.SpanKind() == oteltrace.SpanKindProducer:
+		parent.external += duration
+	case child != nil:
+		parent.db += child.db
+		parent.external += child.external
+	}
+
+	attrs := []attribute.KeyValue{RollupChildCountKey.Int64(parent.children)}
+	attrs = append(attrs, Duration(RollupDBDurationKey, parent.db)...)
+	attrs = append(attrs, Duration(RollupExternalDurationKey, parent.external)...)
+	parent.span.SetAttributes(attrs...)
+}
+
+func isDBSpan(s trace.ReadOnlySpan) bool {
+	for _, attr := range s.Attributes() {
+		if attr.Key == semconv.DBSystemKey {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Shutdown implements the trace.SpanProcessor interface.
+func (p *RollupProcessor) Shutdown(context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.spans = make(map[oteltrace.SpanID]*rollup)
+	return nil
+}
+
+// ForceFlush implements the trace.SpanProcessor interface.
+func (p *RollupProcessor) ForceFlush(context.Context) error {
+	return nil
+}
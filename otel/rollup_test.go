@@ -0,0 +1,64 @@
+package otel
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+func TestRollupProcessor_SummarizesChildren(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := trace.NewTracerProvider(trace.WithSpanProcessor(NewRollupProcessor()), trace.WithSpanProcessor(recorder))
+	defer tp.Shutdown(context.TODO())
+	tracer := tp.Tracer("sample")
+
+	start := time.Now()
+	span := func(ctx context.Context, name string, d time.Duration, opts ...oteltrace.SpanStartOption) context.Context {
+		ctx, s := tracer.Start(ctx, name, append(opts, oteltrace.WithTimestamp(start))...)
+		s.End(oteltrace.WithTimestamp(start.Add(d)))
+		return ctx
+	}
+
+	ctx, request := tracer.Start(context.TODO(), "request", oteltrace.WithTimestamp(start))
+	repository, repo := tracer.Start(ctx, "repository", oteltrace.WithTimestamp(start))
+	span(repository, "SELECT", 30*time.Millisecond, oteltrace.WithAttributes(attribute.String("db.system", "postgresql")))
+	span(repository, "UPDATE", 20*time.Millisecond, oteltrace.WithAttributes(attribute.String("db.system", "postgresql")))
+	repo.End(oteltrace.WithTimestamp(start.Add(60 * time.Millisecond)))
+	span(ctx, "HTTP GET", 100*time.Millisecond, oteltrace.WithSpanKind(oteltrace.SpanKindClient))
+	request.End(oteltrace.WithTimestamp(start.Add(200 * time.Millisecond)))
+
+	spans := recorder.Ended()
+	attrs := spans[len(spans)-1].Attributes()
+	assert.Contains(t, attrs, RollupChildCountKey.Int64(2))
+	assert.Contains(t, attrs, RollupDBDurationKey.Float64(50))
+	assert.Contains(t, attrs, RollupExternalDurationKey.Float64(100))
+
+	assert.Contains(t, spans[2].Attributes(), RollupChildCountKey.Int64(2))
+	assert.Len(t, spans[0].Attributes(), 1)
+}
+
+func TestRollupProcessor_ForgetsSpansNeverEnded(t *testing.T) {
+	defer func(age time.Duration, spans int) { rollupMaxAge, rollupMaxSpans = age, spans }(rollupMaxAge, rollupMaxSpans)
+	rollupMaxAge, rollupMaxSpans = -time.Second, 2
+
+	p := NewRollupProcessor()
+	tp := trace.NewTracerProvider(trace.WithSpanProcessor(p))
+	defer tp.Shutdown(context.TODO())
+
+	tp.Tracer("sample").Start(context.TODO(), "leaked")
+	p.nextSweep = time.Time{}
+	tp.Tracer("sample").Start(context.TODO(), "leaked")
+	assert.Len(t, p.spans, 1, "expired span forgotten")
+
+	rollupMaxAge = time.Minute
+	for range 3 {
+		tp.Tracer("sample").Start(context.TODO(), "leaked")
+	}
+	assert.Len(t, p.spans, 2, "bounded")
+}
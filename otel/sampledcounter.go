@@ -0,0 +1,162 @@
+package otel
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/otel/metric"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Sampling thresholds are 56 bit values carried in the "th" field of the
+// "ot" tracestate entry, a span is sampled with probability 1 - th/2^56.
+const (
+	tracestateKey = "ot"
+	maxThreshold  = 1 << 56
+)
+
+// samplingThreshold returns the hex encoded threshold of a probability,
+// without trailing zeros.
+func samplingThreshold(p float64) string {
+	if p >= 1 {
+		return "0"
+	}
+
+	th := strconv.FormatUint(uint64((1-p)*maxThreshold), 16)
+	th = strings.Repeat("0", 14-len(th)) + th
+	if th = strings.TrimRight(th, "0"); th == "" {
+		return "0"
+	}
+
+	return th
+}
+
+// consistentSampler samples the root spans whose randomness, the rv field of
+// their tracestate or the 56 low bits of their trace ID, is at least the
+// threshold of its probability, recording the threshold in their tracestate.
+type consistentSampler struct {
+	probability float64
+	threshold   uint64
+}
+
+// ConsistentProbabilitySampler returns a sampler sampling probability of the
+// root spans consistently with the OpenTelemetry probability sampling
+// specification, so SamplingProbability and SampledCounter account for them.
+// Wrap it with trace.ParentBased to sample the child spans like their parent.
+func ConsistentProbabilitySampler(probability float64) sdktrace.Sampler {
+	probability = max(0, min(probability, 1))
+
+	return consistentSampler{probability: probability, threshold: uint64((1 - probability) * maxThreshold)}
+}
+
+// ShouldSample implements the trace.Sampler interface.
+func (s consistentSampler) ShouldSample(p sdktrace.SamplingParameters) sdktrace.SamplingResult {
+	ts := trace.SpanContextFromContext(p.ParentContext).TraceState()
+	if s.threshold >= maxThreshold || randomness(p.TraceID, ts) < s.threshold {
+		return sdktrace.SamplingResult{Decision: sdktrace.Drop, Tracestate: ts}
+	}
+
+	if inserted, err := ts.Insert(tracestateKey, setTracestateField(ts.Get(tracestateKey), "th", samplingThreshold(s.probability))); err == nil {
+		ts = inserted
+	}
+
+	return sdktrace.SamplingResult{Decision: sdktrace.RecordAndSample, Tracestate: ts}
+}
+
+// Description implements the trace.Sampler interface.
+func (s consistentSampler) Description() string {
+	return fmt.Sprintf("ConsistentProbabilitySampler{%g}", s.probability)
+}
+
+// randomness returns the explicit rv field of the tracestate, or the 56 low
+// bits of the trace ID.
+func randomness(id trace.TraceID, ts trace.TraceState) uint64 {
+	for _, field := range strings.Split(ts.Get(tracestateKey), ";") {
+		if value, ok := strings.CutPrefix(field, "rv:"); ok && len(value) == 14 {
+			if rv, err := strconv.ParseUint(value, 16, 64); err == nil {
+				return rv
+			}
+		}
+	}
+
+	return binary.BigEndian.Uint64(id[8:]) & (maxThreshold - 1)
+}
+
+// setTracestateField sets the key field of the value of the ot tracestate entry.
+func setTracestateField(value, key, field string) string {
+	fields := []string{key + ":" + field}
+	for _, f := range strings.Split(value, ";") {
+		if f != "" && !strings.HasPrefix(f, key+":") {
+			fields = append(fields, f)
+		}
+	}
+
+	return strings.Join(fields, ";")
+}
+
+// SamplingProbability returns the probability sc was sampled with, read from
+// the th field of its tracestate, 1 when absent. It's recorded by the
+// ConsistentProbabilitySampler, used for the sampling ratios set through the
+// AdminHandler or the sampling_ratio of a configuration file.
+func SamplingProbability(sc trace.SpanContext) float64 {
+	for _, field := range strings.Split(sc.TraceState().Get(tracestateKey), ";") {
+		value, ok := strings.CutPrefix(field, "th:")
+		if !ok || value == "" || len(value) > 14 {
+			continue
+		}
+
+		th, err := strconv.ParseUint(value+strings.Repeat("0", 14-len(value)), 16, 64)
+		if err != nil {
+			continue
+		}
+		return 1 - float64(th)/maxThreshold
+	}
+
+	return 1
+}
+
+// AdjustedCount returns the number of spans sc stands for: the inverse of its
+// sampling probability when sampled, 0 when not, and 1 outside of a trace.
+func AdjustedCount(sc trace.SpanContext) float64 {
+	switch {
+	case !sc.IsValid():
+		return 1
+	case !sc.IsSampled():
+		return 0
+	}
+
+	p := SamplingProbability(sc)
+	if p <= 0 {
+		return 0
+	}
+
+	return 1 / p
+}
+
+// SampledCounter is a counter scaling the recorded values by the adjusted
+// count of the span in the context, so volumes derived from sampled spans,
+// e.g. in a span processor, estimate the volumes of all spans.
+type SampledCounter struct {
+	counter metric.Float64Counter
+}
+
+// NewSampledCounter creates a SampledCounter recording on meter.
+func NewSampledCounter(meter metric.Meter, name string, opts ...metric.Float64CounterOption) (*SampledCounter, error) {
+	counter, err := meter.Float64Counter(name, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SampledCounter{counter: counter}, nil
+}
+
+// Add records value scaled by the AdjustedCount of the span in ctx.
+func (c *SampledCounter) Add(ctx context.Context, value float64, opts ...metric.AddOption) {
+	if count := AdjustedCount(trace.SpanContextFromContext(ctx)); count > 0 {
+		c.counter.Add(ctx, value*count, opts...)
+	}
+}
@@ -0,0 +1,78 @@
+package otel
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/trace"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+func TestSampledCounter_ScalesByAdjustedCount(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	counter, err := NewSampledCounter(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)).Meter("sample"), "requests")
+	assert.Nil(t, err)
+
+	sampler := newDynamicSampler()
+	sampler.setRatio(0.25)
+	tp := trace.NewTracerProvider(trace.WithSampler(sampler))
+
+	var sampled int
+	for i := 0; i < 100; i++ {
+		ctx, span := tp.Tracer("sample").Start(context.TODO(), "request")
+		if span.SpanContext().IsSampled() {
+			sampled++
+			assert.InDelta(t, 0.25, SamplingProbability(span.SpanContext()), 0.0001)
+		}
+		counter.Add(ctx, 1)
+		span.End()
+	}
+	counter.Add(context.TODO(), 1)
+
+	var rm metricdata.ResourceMetrics
+	assert.Nil(t, reader.Collect(context.TODO(), &rm))
+	sum := rm.ScopeMetrics[0].Metrics[0].Data.(metricdata.Sum[float64])
+	assert.InDelta(t, float64(sampled*4+1), sum.DataPoints[0].Value, 0.0001)
+}
+
+func TestSampledCounter_AdjustedCount(t *testing.T) {
+	ts, _ := oteltrace.ParseTraceState("ot=th:c")
+	sc := oteltrace.NewSpanContext(oteltrace.SpanContextConfig{
+		TraceID:    oteltrace.TraceID{1},
+		SpanID:     oteltrace.SpanID{1},
+		TraceFlags: oteltrace.FlagsSampled,
+		TraceState: ts,
+	})
+	assert.InDelta(t, 4, AdjustedCount(sc), 0.0001)
+	assert.Equal(t, "c", samplingThreshold(0.25))
+	assert.Equal(t, "0", samplingThreshold(1))
+
+	assert.Equal(t, float64(0), AdjustedCount(sc.WithTraceFlags(0)))
+	assert.Equal(t, float64(1), AdjustedCount(oteltrace.SpanContext{}))
+}
+
+func TestConsistentProbabilitySampler_SamplesByRandomness(t *testing.T) {
+	sampler := ConsistentProbabilitySampler(0.25)
+	sample := func(id oteltrace.TraceID, tracestate string) trace.SamplingResult {
+		ts, _ := oteltrace.ParseTraceState(tracestate)
+		parent := oteltrace.ContextWithSpanContext(context.TODO(), oteltrace.SpanContext{}.WithTraceState(ts))
+		return sampler.ShouldSample(trace.SamplingParameters{ParentContext: parent, TraceID: id})
+	}
+
+	// the threshold of 0.25 is 0xc0000000000000
+	result := sample(oteltrace.TraceID{8: 0xff, 9: 0xc0}, "")
+	assert.Equal(t, trace.RecordAndSample, result.Decision)
+	assert.Equal(t, "th:c", result.Tracestate.Get("ot"))
+	assert.Equal(t, trace.Drop, sample(oteltrace.TraceID{8: 0xff, 9: 0xbf, 10: 0xff}, "").Decision)
+
+	result = sample(oteltrace.TraceID{}, "ot=rv:d0000000000000;p:8,vendor=1")
+	assert.Equal(t, trace.RecordAndSample, result.Decision)
+	assert.Equal(t, "th:c;rv:d0000000000000;p:8", result.Tracestate.Get("ot"))
+	assert.Equal(t, "1", result.Tracestate.Get("vendor"))
+
+	assert.Equal(t, trace.Drop, ConsistentProbabilitySampler(0).ShouldSample(trace.SamplingParameters{TraceID: oteltrace.TraceID{8: 0xff, 9: 0xff}}).Decision)
+	assert.Equal(t, "ConsistentProbabilitySampler{0.25}", sampler.Description())
+}
@@ -0,0 +1,124 @@
+package otel
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// secretRefreshInterval is the interval the API key is read again at.
+const secretRefreshInterval = time.Minute
+
+// secretTimeout bounds the reads of the API key.
+const secretTimeout = 10 * time.Second
+
+// SecretProvider provides a secret like the API key, it's called again
+// every minute to pick up rotations. Implement it to read the key from a
+// secret manager like Vault or AWS Secrets Manager:
+//
+//	c.APIKeyProvider = otel.SecretFunc(func(ctx context.Context) (string, error) {
+//		out, err := client.GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{SecretId: aws.String("otel/api-key")})
+//		if err != nil {
+//			return "", err
+//		}
+//		return aws.ToString(out.SecretString), nil
+//	})
+type SecretProvider interface {
+	Secret(ctx context.Context) (string, error)
+}
+
+// SecretFunc is a function implementing SecretProvider.
+type SecretFunc func(ctx context.Context) (string, error)
+
+// Secret implements the SecretProvider interface.
+func (f SecretFunc) Secret(ctx context.Context) (string, error) {
+	return f(ctx)
+}
+
+// FileSecret returns a provider reading the secret from the file at path,
+// like the Docker and Kubernetes secrets, surrounding whitespace trimmed.
+func FileSecret(path string) SecretProvider {
+	return SecretFunc(func(context.Context) (string, error) {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("could not read secret: %w", err)
+		}
+
+		return strings.TrimSpace(string(b)), nil
+	})
+}
+
+// apiKeyProvider returns the provider of the API key, nil when APIKey
+// holds it.
+func (c *Config) apiKeyProvider() SecretProvider {
+	switch {
+	case c.APIKeyProvider != nil:
+		return c.APIKeyProvider
+	case c.APIKeyFile != "":
+		return FileSecret(c.APIKeyFile)
+	}
+
+	return nil
+}
+
+// apiKeyCredentials sets the api-key header of every request from provider,
+// read again every secretRefreshInterval. The last key is kept when it
+// can't be read anymore, the error is reported to the error handler.
+//
+// The key is read unlocked, the requests made meanwhile using the last one.
+type apiKeyCredentials struct {
+	provider SecretProvider
+
+	mu         sync.Mutex
+	key        string
+	expires    time.Time
+	refreshing bool
+}
+
+func (c *apiKeyCredentials) secret(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	if time.Now().Before(c.expires) || (c.refreshing && c.key != "") {
+		defer c.mu.Unlock()
+		return c.key, nil
+	}
+	c.refreshing = true
+	c.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(ctx, secretTimeout)
+	defer cancel()
+	key, err := c.provider.Secret(ctx)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.refreshing = false
+	if err != nil {
+		err = fmt.Errorf("could not get the API key: %w", err)
+		if c.key == "" {
+			return "", err
+		}
+		handle(errorKindAPIKey, err)
+		key = c.key
+	}
+	c.key, c.expires = key, time.Now().Add(secretRefreshInterval)
+
+	return key, nil
+}
+
+// GetRequestMetadata implements the credentials.PerRPCCredentials interface.
+func (c *apiKeyCredentials) GetRequestMetadata(ctx context.Context, _ ...string) (map[string]string, error) {
+	key, err := c.secret(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]string{"api-key": key}, nil
+}
+
+// RequireTransportSecurity implements the credentials.PerRPCCredentials
+// interface, the key is sent over insecure connections like a header.
+func (c *apiKeyCredentials) RequireTransportSecurity() bool {
+	return false
+}
@@ -0,0 +1,117 @@
+package otel
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// keyCollector records the api-key header of every export.
+type keyCollector struct {
+	coltracepb.UnimplementedTraceServiceServer
+	keys chan string
+}
+
+func (c *keyCollector) Export(ctx context.Context, _ *coltracepb.ExportTraceServiceRequest) (*coltracepb.ExportTraceServiceResponse, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	c.keys <- md.Get("api-key")[0]
+	return &coltracepb.ExportTraceServiceResponse{}, nil
+}
+
+func TestGRPCOutput_ReadsAPIKeyFile(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	collector := &keyCollector{keys: make(chan string, 10)}
+	server := grpc.NewServer()
+	coltracepb.RegisterTraceServiceServer(server, collector)
+	go server.Serve(lis)
+	defer server.Stop()
+
+	path := filepath.Join(t.TempDir(), "api-key")
+	assert.Nil(t, os.WriteFile(path, []byte("secret-key\n"), 0o600))
+	exporter := NewExporter(GRPC, &Config{
+		URL:        lis.Addr().String(),
+		APIKey:     "ignored",
+		APIKeyFile: path,
+		Insecure:   true,
+		SyncExport: true,
+	}, WithGlobalRegistration(false))
+	pipeline, err := exporter.ExportPipeline(context.TODO())
+	assert.Nil(t, err)
+	defer pipeline.Shutdown(context.TODO())
+
+	_, span := pipeline.Tracer("sample").Start(context.TODO(), "sample span")
+	span.End()
+	assert.Equal(t, "secret-key", <-collector.keys)
+}
+
+func TestGRPCOutput_FailsWithoutAPIKey(t *testing.T) {
+	exporter := NewExporter(GRPC, &Config{
+		URL:        "localhost:4317",
+		APIKeyFile: filepath.Join(t.TempDir(), "missing"),
+	}, WithGlobalRegistration(false))
+
+	_, err := exporter.ExportPipeline(context.TODO())
+	assert.ErrorContains(t, err, "could not get the API key: could not read secret")
+}
+
+func TestAPIKeyCredentials_RefreshesKey(t *testing.T) {
+	keys := []string{"first", "", "second"}
+	c := &apiKeyCredentials{provider: SecretFunc(func(context.Context) (string, error) {
+		key := keys[0]
+		keys = keys[1:]
+		if key == "" {
+			return "", errors.New("vault sealed")
+		}
+		return key, nil
+	})}
+
+	md, err := c.GetRequestMetadata(context.TODO())
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{"api-key": "first"}, md)
+	key, _ := c.secret(context.TODO())
+	assert.Equal(t, "first", key, "cached")
+
+	c.expires = time.Now()
+	key, err = c.secret(context.TODO())
+	assert.Nil(t, err)
+	assert.Equal(t, "first", key, "kept when the provider fails")
+
+	c.expires = time.Now()
+	key, _ = c.secret(context.TODO())
+	assert.Equal(t, "second", key)
+}
+
+func TestAPIKeyCredentials_RefreshesUnlocked(t *testing.T) {
+	release := make(chan struct{})
+	deadlines := make(chan bool, 1)
+	c := &apiKeyCredentials{key: "first", provider: SecretFunc(func(ctx context.Context) (string, error) {
+		_, ok := ctx.Deadline()
+		deadlines <- ok
+		<-release
+		return "second", nil
+	})}
+
+	refreshed := make(chan string)
+	go func() {
+		key, _ := c.secret(context.Background())
+		refreshed <- key
+	}()
+	assert.True(t, <-deadlines, "bounded")
+
+	key, err := c.secret(context.TODO())
+	assert.Nil(t, err)
+	assert.Equal(t, "first", key, "used while refreshing")
+
+	close(release)
+	assert.Equal(t, "second", <-refreshed)
+}
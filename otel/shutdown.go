@@ -0,0 +1,57 @@
+package otel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// Shutdowner is implemented by the providers returned from the pipelines
+// like *trace.TracerProvider.
+type Shutdowner interface {
+	ForceFlush(context.Context) error
+	Shutdown(context.Context) error
+}
+
+// ShutdownOnSignal flushes and shuts down provider once the process receives
+// one of the signals, SIGINT and SIGTERM when none are given.
+//
+// It returns immediately, the returned channel receives the result of the
+// shutdown, bounded by timeout (no bound when timeout <= 0), and is closed afterwards.
+func ShutdownOnSignal(provider Shutdowner, timeout time.Duration, signals ...os.Signal) <-chan error {
+	if len(signals) == 0 {
+		signals = []os.Signal{os.Interrupt, syscall.SIGTERM}
+	}
+
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, signals...)
+
+	done := make(chan error, 1)
+	go func() {
+		defer close(done)
+
+		<-sig
+		signal.Stop(sig)
+
+		ctx := context.Background()
+		if timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+
+		// The provider is shut down even when the flush fails, releasing
+		// its exporters and flushing what can still be.
+		var flushErr error
+		if err := provider.ForceFlush(ctx); err != nil {
+			flushErr = fmt.Errorf("could not flush provider: %w", err)
+		}
+		done <- errors.Join(flushErr, provider.Shutdown(ctx))
+	}()
+
+	return done
+}
@@ -0,0 +1,65 @@
+//go:build unix
+
+package otel
+
+import (
+	"context"
+	"errors"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeShutdowner struct {
+	flushErr error
+	flushed  bool
+	shutdown bool
+}
+
+func (f *fakeShutdowner) ForceFlush(context.Context) error {
+	f.flushed = true
+	return f.flushErr
+}
+
+func (f *fakeShutdowner) Shutdown(context.Context) error {
+	f.shutdown = true
+	return nil
+}
+
+func TestShutdownOnSignal_FlushesAndShutsDown(t *testing.T) {
+	provider := &fakeShutdowner{}
+	done := ShutdownOnSignal(provider, time.Second, syscall.SIGUSR1)
+
+	p, _ := os.FindProcess(os.Getpid())
+	assert.Nil(t, p.Signal(syscall.SIGUSR1))
+
+	select {
+	case err := <-done:
+		assert.Nil(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("provider was not shut down")
+	}
+
+	assert.True(t, provider.flushed)
+	assert.True(t, provider.shutdown)
+}
+
+func TestShutdownOnSignal_ShutsDownWhenFlushFails(t *testing.T) {
+	provider := &fakeShutdowner{flushErr: errors.New("collector unavailable")}
+	done := ShutdownOnSignal(provider, time.Second, syscall.SIGUSR2)
+
+	p, _ := os.FindProcess(os.Getpid())
+	assert.Nil(t, p.Signal(syscall.SIGUSR2))
+
+	select {
+	case err := <-done:
+		assert.EqualError(t, err, "could not flush provider: collector unavailable")
+	case <-time.After(5 * time.Second):
+		t.Fatal("provider was not shut down")
+	}
+
+	assert.True(t, provider.shutdown)
+}
@@ -0,0 +1,258 @@
+package otel
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/sdk/trace"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+const (
+	defaultSLOWindow = time.Hour
+	minSLOWindow     = time.Minute
+	sloBuckets       = 60
+)
+
+// SLO is a service level objective evaluated against server spans.
+//
+// SuccessTarget is the expected ratio of non error spans, e.g. 0.999.
+// LatencyTarget is the expected ratio of spans ending within LatencyThreshold,
+// it's only evaluated when LatencyThreshold is set.
+// Window is the rolling window SLIs are computed over, one hour by default
+// and at least one minute.
+// Match narrows the server spans the SLO applies to, all of them when nil.
+type SLO struct {
+	Name             string
+	SuccessTarget    float64
+	LatencyThreshold time.Duration
+	LatencyTarget    float64
+	Window           time.Duration
+	Match            func(trace.ReadOnlySpan) bool
+}
+
+// SLOStatus holds the SLI and burn rate values of an SLO over its window.
+//
+// A burn rate of 1 means the error budget is consumed exactly at the pace
+// allowed by the target, above 1 means it will be exhausted early.
+type SLOStatus struct {
+	Total           int64
+	Failed          int64
+	Slow            int64
+	SuccessSLI      float64
+	LatencySLI      float64
+	SuccessBurnRate float64
+	LatencyBurnRate float64
+}
+
+type sloBucket struct {
+	index  int64
+	total  int64
+	failed int64
+	slow   int64
+}
+
+type sloTracker struct {
+	SLO
+	width   time.Duration
+	buckets [sloBuckets]sloBucket
+}
+
+func (s *sloTracker) record(now time.Time, failed, slow bool) {
+	index := now.UnixNano() / int64(s.width)
+	b := &s.buckets[index%sloBuckets]
+	if b.index != index {
+		*b = sloBucket{index: index}
+	}
+
+	b.total++
+	if failed {
+		b.failed++
+	}
+	if slow {
+		b.slow++
+	}
+}
+
+func (s *sloTracker) status(now time.Time) SLOStatus {
+	var status SLOStatus
+	index := now.UnixNano() / int64(s.width)
+	for _, b := range s.buckets {
+		if b.index <= index-sloBuckets || b.index > index {
+			continue
+		}
+		status.Total += b.total
+		status.Failed += b.failed
+		status.Slow += b.slow
+	}
+
+	status.SuccessSLI, status.SuccessBurnRate = sli(status.Total, status.Failed, s.SuccessTarget)
+	if s.LatencyThreshold > 0 {
+		status.LatencySLI, status.LatencyBurnRate = sli(status.Total, status.Slow, s.LatencyTarget)
+	}
+
+	return status
+}
+
+func sli(total, bad int64, target float64) (float64, float64) {
+	if total == 0 {
+		return 1, 0
+	}
+
+	value := 1 - float64(bad)/float64(total)
+	return value, (1 - value) / (1 - target)
+}
+
+// SLOProcessor is a span processor computing rolling SLIs and burn rates
+// from ended server spans.
+//
+// Values are published as the slo.sli and slo.burn_rate gauges and can be
+// queried with Status. Register it on the provider returned by ExportPipeline
+// with RegisterSpanProcessor.
+type SLOProcessor struct {
+	mu       sync.Mutex
+	now      func() time.Time
+	trackers []*sloTracker
+	reg      metric.Registration
+}
+
+var _ trace.SpanProcessor = (*SLOProcessor)(nil)
+
+// NewSLOProcessor creates a processor tracking the given SLOs, metrics are
+// recorded on mp or the global MeterProvider when mp is nil.
+func NewSLOProcessor(mp metric.MeterProvider, slos ...SLO) (*SLOProcessor, error) {
+	p := &SLOProcessor{now: time.Now}
+	for _, slo := range slos {
+		if slo.Name == "" {
+			return nil, fmt.Errorf("invalid SLO: name is required")
+		}
+		if slo.SuccessTarget <= 0 || slo.SuccessTarget >= 1 {
+			return nil, fmt.Errorf("invalid SLO %q: success target must be between 0 and 1", slo.Name)
+		}
+		if slo.LatencyThreshold > 0 && (slo.LatencyTarget <= 0 || slo.LatencyTarget >= 1) {
+			return nil, fmt.Errorf("invalid SLO %q: latency target must be between 0 and 1", slo.Name)
+		}
+		if slo.Window <= 0 {
+			slo.Window = defaultSLOWindow
+		}
+		if slo.Window < minSLOWindow {
+			return nil, fmt.Errorf("invalid SLO %q: window must be at least %s", slo.Name, minSLOWindow)
+		}
+
+		p.trackers = append(p.trackers, &sloTracker{SLO: slo, width: slo.Window / sloBuckets})
+	}
+
+	if mp == nil {
+		mp = otel.GetMeterProvider()
+	}
+	if err := p.registerMetrics(mp.Meter(instrumentationName)); err != nil {
+		return nil, fmt.Errorf("could not register SLO metrics: %w", err)
+	}
+
+	return p, nil
+}
+
+func (p *SLOProcessor) registerMetrics(meter metric.Meter) error {
+	indicator, err := meter.Float64ObservableGauge("slo.sli",
+		metric.WithDescription("Ratio of good server spans over the SLO window."))
+	if err != nil {
+		return err
+	}
+
+	burnRate, err := meter.Float64ObservableGauge("slo.burn_rate",
+		metric.WithDescription("Pace the SLO error budget is consumed at over the SLO window."))
+	if err != nil {
+		return err
+	}
+
+	p.reg, err = meter.RegisterCallback(func(_ context.Context, o metric.Observer) error {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+
+		now := p.now()
+		for _, t := range p.trackers {
+			status := t.status(now)
+			success := metric.WithAttributes(attribute.String("slo.name", t.Name), attribute.String("slo.kind", "success"))
+			o.ObserveFloat64(indicator, status.SuccessSLI, success)
+			o.ObserveFloat64(burnRate, status.SuccessBurnRate, success)
+
+			// The latency is only evaluated with a threshold.
+			if t.LatencyThreshold <= 0 {
+				continue
+			}
+			latency := metric.WithAttributes(attribute.String("slo.name", t.Name), attribute.String("slo.kind", "latency"))
+			o.ObserveFloat64(indicator, status.LatencySLI, latency)
+			o.ObserveFloat64(burnRate, status.LatencyBurnRate, latency)
+		}
+		return nil
+	}, indicator, burnRate)
+
+	return err
+}
+
+// Status returns the current status of the named SLO.
+func (p *SLOProcessor) Status(name string) (SLOStatus, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, t := range p.trackers {
+		if t.Name == name {
+			return t.status(p.now()), true
+		}
+	}
+
+	return SLOStatus{}, false
+}
+
+// Statuses returns the current status of every SLO keyed by name.
+func (p *SLOProcessor) Statuses() map[string]SLOStatus {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.now()
+	statuses := make(map[string]SLOStatus, len(p.trackers))
+	for _, t := range p.trackers {
+		statuses[t.Name] = t.status(now)
+	}
+
+	return statuses
+}
+
+// OnStart implements the trace.SpanProcessor interface.
+func (p *SLOProcessor) OnStart(context.Context, trace.ReadWriteSpan) {}
+
+// OnEnd implements the trace.SpanProcessor interface.
+func (p *SLOProcessor) OnEnd(s trace.ReadOnlySpan) {
+	if s.SpanKind() != oteltrace.SpanKindServer {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := p.now()
+	failed := s.Status().Code == codes.Error
+	duration := s.EndTime().Sub(s.StartTime())
+	for _, t := range p.trackers {
+		if t.Match != nil && !t.Match(s) {
+			continue
+		}
+		t.record(now, failed, t.LatencyThreshold > 0 && duration > t.LatencyThreshold)
+	}
+}
+
+// Shutdown implements the trace.SpanProcessor interface.
+func (p *SLOProcessor) Shutdown(context.Context) error {
+	return p.reg.Unregister()
+}
+
+// ForceFlush implements the trace.SpanProcessor interface.
+func (p *SLOProcessor) ForceFlush(context.Context) error {
+	return nil
+}
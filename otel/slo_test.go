@@ -0,0 +1,98 @@
+package otel
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"go.opentelemetry.io/otel/sdk/trace"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+func TestSLOProcessor_ComputesBurnRate(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	p, err := NewSLOProcessor(mp, SLO{
+		Name:             "checkout",
+		SuccessTarget:    0.9,
+		LatencyThreshold: 100 * time.Millisecond,
+		LatencyTarget:    0.5,
+	})
+	assert.Nil(t, err)
+
+	tp := trace.NewTracerProvider(trace.WithSpanProcessor(p))
+	defer tp.Shutdown(context.TODO())
+	tracer := tp.Tracer("sample")
+
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		_, span := tracer.Start(context.TODO(), "server span",
+			oteltrace.WithSpanKind(oteltrace.SpanKindServer), oteltrace.WithTimestamp(start))
+		if i == 0 {
+			span.SetStatus(codes.Error, "failed")
+		}
+		span.End(oteltrace.WithTimestamp(start.Add(time.Duration(i) * 100 * time.Millisecond)))
+	}
+	_, internal := tracer.Start(context.TODO(), "internal span")
+	internal.End()
+
+	status, ok := p.Status("checkout")
+	assert.True(t, ok)
+	assert.Equal(t, int64(4), status.Total)
+	assert.Equal(t, int64(1), status.Failed)
+	assert.Equal(t, int64(2), status.Slow)
+	assert.InDelta(t, 0.75, status.SuccessSLI, 0.0001)
+	assert.InDelta(t, 2.5, status.SuccessBurnRate, 0.0001)
+	assert.InDelta(t, 1.0, status.LatencyBurnRate, 0.0001)
+
+	var rm metricdata.ResourceMetrics
+	assert.Nil(t, reader.Collect(context.TODO(), &rm))
+	assert.Len(t, rm.ScopeMetrics[0].Metrics, 2)
+}
+
+func TestSLOProcessor_ForgetsSpansOutsideWindow(t *testing.T) {
+	p, err := NewSLOProcessor(nil, SLO{Name: "api", SuccessTarget: 0.99, Window: time.Minute})
+	assert.Nil(t, err)
+
+	now := time.Now()
+	p.now = func() time.Time { return now }
+	p.trackers[0].record(now, true, false)
+
+	now = now.Add(2 * time.Minute)
+	status, _ := p.Status("api")
+
+	assert.Equal(t, int64(0), status.Total)
+	assert.Equal(t, 1.0, status.SuccessSLI)
+}
+
+func TestSLOProcessor_InvalidTarget(t *testing.T) {
+	_, err := NewSLOProcessor(nil, SLO{Name: "api", SuccessTarget: 1})
+
+	assert.EqualError(t, err, `invalid SLO "api": success target must be between 0 and 1`)
+}
+
+func TestSLOProcessor_InvalidWindow(t *testing.T) {
+	_, err := NewSLOProcessor(nil, SLO{Name: "api", SuccessTarget: 0.99, Window: 59 * time.Nanosecond})
+
+	assert.EqualError(t, err, `invalid SLO "api": window must be at least 1m0s`)
+}
+
+func TestSLOProcessor_ObservesLatencyWithThreshold(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	mp := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	_, err := NewSLOProcessor(mp, SLO{Name: "api", SuccessTarget: 0.99})
+	assert.Nil(t, err)
+
+	var rm metricdata.ResourceMetrics
+	assert.Nil(t, reader.Collect(context.TODO(), &rm))
+	for _, m := range rm.ScopeMetrics[0].Metrics {
+		points := m.Data.(metricdata.Gauge[float64]).DataPoints
+		assert.Len(t, points, 1, m.Name)
+		kind, _ := points[0].Attributes.Value("slo.kind")
+		assert.Equal(t, "success", kind.AsString())
+	}
+}
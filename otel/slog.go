@@ -0,0 +1,54 @@
+package otel
+
+import (
+	"context"
+	"log/slog"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Log correlation attributes added by the slog handler.
+const (
+	LogTraceIDKey = "trace_id"
+	LogSpanIDKey  = "span_id"
+)
+
+type slogHandler struct {
+	slog.Handler
+}
+
+// NewSlogHandler wraps next to add the trace_id and span_id of the span found
+// in the context of every record, so logs written with the slog Context
+// methods correlate with the traces of the pipeline:
+//
+//	logger := slog.New(otel.NewSlogHandler(slog.NewJSONHandler(os.Stdout, nil)))
+//	logger.InfoContext(ctx, "order shipped")
+//
+// Records without a valid span are passed through as is. The attributes are
+// part of the current group when the logger was created with WithGroup.
+func NewSlogHandler(next slog.Handler) slog.Handler {
+	return &slogHandler{Handler: next}
+}
+
+// Handle implements the slog.Handler interface.
+func (h *slogHandler) Handle(ctx context.Context, r slog.Record) error {
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		r = r.Clone()
+		r.AddAttrs(
+			slog.String(LogTraceIDKey, sc.TraceID().String()),
+			slog.String(LogSpanIDKey, sc.SpanID().String()),
+		)
+	}
+
+	return h.Handler.Handle(ctx, r)
+}
+
+// WithAttrs implements the slog.Handler interface.
+func (h *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &slogHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+// WithGroup implements the slog.Handler interface.
+func (h *slogHandler) WithGroup(name string) slog.Handler {
+	return &slogHandler{Handler: h.Handler.WithGroup(name)}
+}
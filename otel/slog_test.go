@@ -0,0 +1,33 @@
+package otel
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/sdk/trace"
+)
+
+func TestSlogHandler_AddsTraceContext(t *testing.T) {
+	var out bytes.Buffer
+	logger := slog.New(NewSlogHandler(slog.NewJSONHandler(&out, nil))).With("service", "orders")
+
+	ctx, span := trace.NewTracerProvider().Tracer("sample").Start(context.TODO(), "sample span")
+	defer span.End()
+	logger.InfoContext(ctx, "order shipped")
+
+	var record map[string]interface{}
+	assert.Nil(t, json.Unmarshal(out.Bytes(), &record))
+	assert.Equal(t, "orders", record["service"])
+	assert.Equal(t, span.SpanContext().TraceID().String(), record[LogTraceIDKey])
+	assert.Equal(t, span.SpanContext().SpanID().String(), record[LogSpanIDKey])
+
+	out.Reset()
+	logger.InfoContext(context.TODO(), "order shipped")
+	record = nil
+	assert.Nil(t, json.Unmarshal(out.Bytes(), &record))
+	assert.NotContains(t, record, LogTraceIDKey)
+}
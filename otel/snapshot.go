@@ -0,0 +1,199 @@
+package otel
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	"go.opentelemetry.io/contrib/zpages"
+	"go.opentelemetry.io/otel/sdk/trace"
+)
+
+// PipelineSnapshot describes a pipeline built by NewExporter, it's a copy
+// left untouched by later changes to the pipeline.
+//
+// Processors lists the processors registered on the provider when built,
+// in their order, the ones registered later with RegisterSpanProcessor
+// aren't known. Processors and Resource are empty until the pipeline is
+// started.
+type PipelineSnapshot struct {
+	Output     string            `json:"output"`
+	Endpoint   string            `json:"endpoint,omitempty"`
+	Started    bool              `json:"started"`
+	Exporter   string            `json:"exporter"`
+	Processors []string          `json:"processors"`
+	Sampler    string            `json:"sampler"`
+	Resource   map[string]string `json:"resource"`
+	Counters   PipelineCounters  `json:"counters"`
+}
+
+// PipelineCounters are the spans processed by a pipeline since it started,
+// DryRun is only set for dry-run pipelines.
+type PipelineCounters struct {
+	SpansStarted int64          `json:"spans_started"`
+	SpansEnded   int64          `json:"spans_ended"`
+	DryRun       *DryRunSummary `json:"dry_run,omitempty"`
+}
+
+// Snapshot returns the description of the pipeline built by e,
+// ok is false when e wasn't created by NewExporter.
+func Snapshot(e Exporter) (snapshot PipelineSnapshot, ok bool) {
+	p, ok := pipelineOf(e)
+	if !ok {
+		return PipelineSnapshot{}, false
+	}
+
+	snapshot = PipelineSnapshot{
+		Exporter: exporterName(e, p),
+		Sampler:  p.sampler.Description(),
+		Resource: map[string]string{},
+		Counters: PipelineCounters{
+			SpansStarted: p.counter.started.Load(),
+			SpansEnded:   p.counter.ended.Load(),
+		},
+	}
+	switch e.(type) {
+	case *ioOutput:
+		snapshot.Output = "io"
+	case *prometheusOutput:
+		snapshot.Output = "prometheus"
+	case *testOutput:
+		snapshot.Output = "test"
+	case *grpcOutput:
+		snapshot.Output = "grpc"
+		snapshot.Endpoint = p.config.URL
+		if len(p.config.Endpoints) > 0 {
+			snapshot.Endpoint = strings.Join(p.config.Endpoints, ",")
+		}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	snapshot.Started = p.provider != nil
+	snapshot.Processors = p.processorNames()
+	p.resMu.Lock()
+	res := p.res
+	p.resMu.Unlock()
+	if res != nil {
+		for _, kv := range res.Attributes() {
+			snapshot.Resource[string(kv.Key)] = kv.Value.Emit()
+		}
+	}
+	if p.dryRun != nil {
+		summary := p.dryRun.Summary()
+		snapshot.Counters.DryRun = &summary
+	}
+	if p.config.Disabled {
+		snapshot.Processors = nil
+	}
+
+	return snapshot, true
+}
+
+// exporterName returns the name of the span exporter of the pipeline p of e.
+func exporterName(e Exporter, p *pipeline) string {
+	switch {
+	case p.config.Disabled:
+		return "none"
+	case p.config.DryRun:
+		return "dry-run"
+	}
+	if _, ok := e.(*grpcOutput); ok {
+		return "otlp-grpc"
+	}
+
+	return "stdout"
+}
+
+// processorNames returns the names of the processors registered on the
+// provider in their order, p.mu being held. The processors wrapping another
+// one are followed by the names of the processors they wrap.
+func (p *pipeline) processorNames() []string {
+	var names []string
+	for _, sp := range p.processors {
+		names = append(names, processorName(sp)...)
+	}
+
+	return names
+}
+
+// processorName returns the names of sp and of the processors it wraps, the
+// ones of Config.SpanProcessors being named after their type.
+func processorName(sp trace.SpanProcessor) []string {
+	switch sp := sp.(type) {
+	case orphanGuard:
+		return []string{"orphan_guard"}
+	case *MaxDurationProcessor:
+		return []string{"max_duration"}
+	case *BaggageProcessor:
+		return []string{"baggage"}
+	case *EnrichmentProcessor:
+		return []string{"enrichment"}
+	case legacyLinkProcessor:
+		return []string{"legacy_links"}
+	case *debugProcessor:
+		return []string{"debug"}
+	case *spanCounter:
+		return []string{"counter"}
+	case *REDProcessor:
+		return []string{"span_metrics"}
+	case *zpages.SpanProcessor:
+		return []string{"zpages"}
+	case *traceBuffer:
+		return []string{"trace_viewer"}
+	case enabledProcessor:
+		return processorName(sp.SpanProcessor)
+	case *healthProcessor:
+		return processorName(sp.SpanProcessor)
+	case *AttributeFilterProcessor:
+		return append([]string{"attribute_filter"}, processorName(sp.next)...)
+	case *RedactionProcessor:
+		return append([]string{"redaction"}, processorName(sp.next)...)
+	case *TruncationProcessor:
+		return append([]string{"truncation"}, processorName(sp.next)...)
+	case *errorOnlyProcessor:
+		return append([]string{"error_only"}, processorName(sp.next)...)
+	case *queueProcessor:
+		name := "blocking_queue"
+		if sp.priority {
+			name = "priority_queue"
+		}
+		return append([]string{name}, processorName(sp.next)...)
+	case *reloadableBatch:
+		return []string{"batch"}
+	case simpleProcessor:
+		return []string{"simple"}
+	case consoleProcessor:
+		return []string{"console"}
+	}
+
+	return []string{fmt.Sprintf("%T", sp)}
+}
+
+// spanCounter counts the spans started and ended by a pipeline.
+type spanCounter struct {
+	started atomic.Int64
+	ended   atomic.Int64
+}
+
+// OnStart implements the trace.SpanProcessor interface.
+func (c *spanCounter) OnStart(context.Context, trace.ReadWriteSpan) {
+	c.started.Add(1)
+}
+
+// OnEnd implements the trace.SpanProcessor interface.
+func (c *spanCounter) OnEnd(trace.ReadOnlySpan) {
+	c.ended.Add(1)
+}
+
+// Shutdown implements the trace.SpanProcessor interface.
+func (c *spanCounter) Shutdown(context.Context) error {
+	return nil
+}
+
+// ForceFlush implements the trace.SpanProcessor interface.
+func (c *spanCounter) ForceFlush(context.Context) error {
+	return nil
+}
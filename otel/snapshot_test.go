@@ -0,0 +1,90 @@
+package otel
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+func TestSnapshot_DescribesPipeline(t *testing.T) {
+	setEnv()
+	defer unsetEnv()
+
+	c := NewENVConfig()
+	c.DryRun = true
+	c.DevMode = true
+
+	exporter := NewExporter(GRPC, c, WithGlobalRegistration(false))
+	snapshot, ok := Snapshot(exporter)
+	assert.True(t, ok)
+	assert.False(t, snapshot.Started)
+	assert.Empty(t, snapshot.Processors)
+	assert.Empty(t, snapshot.Resource)
+
+	pipeline, err := exporter.ExportPipeline(context.TODO())
+	assert.Nil(t, err)
+	defer pipeline.Shutdown(context.TODO())
+
+	_, span := pipeline.Tracer("sample").Start(context.TODO(), "sample span", oteltrace.WithSpanKind(oteltrace.SpanKindServer))
+	span.End()
+	assert.Nil(t, pipeline.ForceFlush(context.TODO()))
+
+	snapshot, ok = Snapshot(exporter)
+	assert.True(t, ok)
+	assert.True(t, snapshot.Started)
+	assert.Equal(t, "grpc", snapshot.Output)
+	assert.Equal(t, "otlp.nr-data.net:4317", snapshot.Endpoint)
+	assert.Equal(t, "dry-run", snapshot.Exporter)
+	assert.Equal(t, []string{"orphan_guard", "debug", "counter", "batch"}, snapshot.Processors)
+	assert.Equal(t, "DynamicSampler{AlwaysOnSampler}", snapshot.Sampler)
+	assert.Equal(t, "sampleServiceName", snapshot.Resource["service.name"])
+	assert.Equal(t, int64(1), snapshot.Counters.SpansStarted)
+	assert.Equal(t, int64(1), snapshot.Counters.SpansEnded)
+	assert.Equal(t, int64(1), snapshot.Counters.DryRun.Spans)
+
+	_, ok = Snapshot(nil)
+	assert.False(t, ok)
+}
+
+func TestExporter_CustomSpanProcessors(t *testing.T) {
+	setEnv()
+	defer unsetEnv()
+
+	recorder := tracetest.NewSpanRecorder()
+	c := NewENVConfig()
+	c.Writer = io.Discard
+	c.SpanProcessors = []trace.SpanProcessor{recorder}
+	exporter := NewExporter(IO, c)
+
+	pipeline, err := exporter.ExportPipeline(context.TODO())
+	assert.Nil(t, err)
+	defer pipeline.Shutdown(context.TODO())
+
+	_, span := pipeline.Tracer("sample").Start(context.TODO(), "sample span")
+	span.End()
+	assert.Len(t, recorder.Ended(), 1)
+
+	snapshot, _ := Snapshot(exporter)
+	assert.Equal(t, []string{"*tracetest.SpanRecorder", "debug", "counter", "batch"}, snapshot.Processors)
+}
+
+func TestSnapshot_ListsBuiltProcessors(t *testing.T) {
+	exporter := NewExporter(IO, &Config{
+		Writer:             io.Discard,
+		ConsoleWriter:      io.Discard,
+		ErrorTracesOnly:    true,
+		MaxAttributeLength: 64,
+		DropRateThreshold:  0.1,
+	}, WithGlobalRegistration(false))
+	pipeline, err := exporter.ExportPipeline(context.TODO())
+	assert.Nil(t, err)
+	defer pipeline.Shutdown(context.TODO())
+
+	snapshot, _ := Snapshot(exporter)
+	assert.Equal(t, []string{"debug", "counter", "truncation", "error_only", "blocking_queue", "batch", "console"}, snapshot.Processors)
+}
@@ -0,0 +1,47 @@
+package otel
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Start starts a span with attrs from the tracer of the global provider
+// registered by ExportPipeline:
+//
+//	ctx, span := otel.Start(ctx, "charge", attribute.String("order.id", id))
+//	defer span.End()
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.GetTracerProvider().Tracer(instrumentationName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// Fail records err on span and sets its status to error, nil errors are ignored.
+// It returns err so failures can be recorded while being returned:
+//
+//	if err != nil {
+//		return otel.Fail(span, err)
+//	}
+func Fail(span trace.Span, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+
+	return err
+}
+
+// End records err like Fail and ends span, meant to be deferred with a
+// named error result:
+//
+//	func charge(ctx context.Context) (err error) {
+//		ctx, span := otel.Start(ctx, "charge")
+//		defer func() { otel.End(span, err) }()
+func End(span trace.Span, err error) {
+	Fail(span, err)
+	span.End()
+}
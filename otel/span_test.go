@@ -0,0 +1,41 @@
+package otel
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestStart_UsesGlobalProvider(t *testing.T) {
+	previous := otel.GetTracerProvider()
+	defer otel.SetTracerProvider(previous)
+
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(trace.NewTracerProvider(trace.WithSpanProcessor(recorder)))
+
+	failure := errors.New("card declined")
+	_, span := Start(context.Background(), "charge", attribute.String("order.id", "42"))
+	assert.Nil(t, Fail(span, nil))
+	assert.Equal(t, failure, Fail(span, failure))
+	span.End()
+
+	_, span = Start(context.Background(), "refund")
+	End(span, nil)
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 2)
+	assert.Equal(t, instrumentationName, spans[0].InstrumentationScope().Name)
+	assert.Contains(t, spans[0].Attributes(), attribute.String("order.id", "42"))
+	assert.Equal(t, codes.Error, spans[0].Status().Code)
+	assert.Equal(t, "card declined", spans[0].Status().Description)
+	assert.Len(t, spans[0].Events(), 1)
+	assert.Equal(t, codes.Unset, spans[1].Status().Code)
+	assert.Empty(t, spans[1].Events())
+}
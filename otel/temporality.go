@@ -0,0 +1,79 @@
+package otel
+
+import (
+	"fmt"
+
+	"go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+// temporalities are the supported Config.MetricTemporality values, they follow
+// OTEL_EXPORTER_OTLP_METRICS_TEMPORALITY_PREFERENCE.
+var temporalities = map[string]metric.TemporalitySelector{
+	"cumulative": metric.DefaultTemporalitySelector,
+	"delta": func(kind metric.InstrumentKind) metricdata.Temporality {
+		switch kind {
+		case metric.InstrumentKindCounter, metric.InstrumentKindHistogram, metric.InstrumentKindObservableCounter:
+			return metricdata.DeltaTemporality
+		}
+		return metricdata.CumulativeTemporality
+	},
+	"lowmemory": func(kind metric.InstrumentKind) metricdata.Temporality {
+		switch kind {
+		case metric.InstrumentKindCounter, metric.InstrumentKindHistogram:
+			return metricdata.DeltaTemporality
+		}
+		return metricdata.CumulativeTemporality
+	},
+}
+
+// histogramAggregations are the supported Config.MetricHistogramAggregation values,
+// they follow OTEL_EXPORTER_OTLP_METRICS_DEFAULT_HISTOGRAM_AGGREGATION.
+var histogramAggregations = map[string]metric.Aggregation{
+	"explicit_bucket_histogram": metric.DefaultAggregationSelector(metric.InstrumentKindHistogram),
+	"base2_exponential_bucket_histogram": metric.AggregationBase2ExponentialHistogram{
+		MaxSize:  160,
+		MaxScale: 20,
+	},
+}
+
+// temporalitySelector returns the temporality of the metric exporters,
+// MetricTemporalitySelector when set.
+func (c *Config) temporalitySelector() (metric.TemporalitySelector, error) {
+	if c.MetricTemporalitySelector != nil {
+		return c.MetricTemporalitySelector, nil
+	}
+	if c.MetricTemporality == "" {
+		return metric.DefaultTemporalitySelector, nil
+	}
+
+	selector, ok := temporalities[c.MetricTemporality]
+	if !ok {
+		return nil, fmt.Errorf("unsupported metric temporality %q", c.MetricTemporality)
+	}
+
+	return selector, nil
+}
+
+// aggregationSelector returns the aggregation of the metric exporters,
+// MetricAggregationSelector when set.
+func (c *Config) aggregationSelector() (metric.AggregationSelector, error) {
+	if c.MetricAggregationSelector != nil {
+		return c.MetricAggregationSelector, nil
+	}
+	if c.MetricHistogramAggregation == "" {
+		return metric.DefaultAggregationSelector, nil
+	}
+
+	histogram, ok := histogramAggregations[c.MetricHistogramAggregation]
+	if !ok {
+		return nil, fmt.Errorf("unsupported histogram aggregation %q", c.MetricHistogramAggregation)
+	}
+
+	return func(kind metric.InstrumentKind) metric.Aggregation {
+		if kind == metric.InstrumentKindHistogram {
+			return histogram
+		}
+		return metric.DefaultAggregationSelector(kind)
+	}, nil
+}
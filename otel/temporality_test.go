@@ -0,0 +1,43 @@
+package otel
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestConfig_MetricTemporality(t *testing.T) {
+	c := &Config{MetricTemporality: "delta"}
+	selector, err := c.temporalitySelector()
+	assert.Nil(t, err)
+	assert.Equal(t, metricdata.DeltaTemporality, selector(metric.InstrumentKindCounter))
+	assert.Equal(t, metricdata.CumulativeTemporality, selector(metric.InstrumentKindUpDownCounter))
+
+	c.MetricTemporality = "lowmemory"
+	selector, _ = c.temporalitySelector()
+	assert.Equal(t, metricdata.DeltaTemporality, selector(metric.InstrumentKindHistogram))
+	assert.Equal(t, metricdata.CumulativeTemporality, selector(metric.InstrumentKindObservableCounter))
+
+	c.MetricTemporalitySelector = func(metric.InstrumentKind) metricdata.Temporality { return metricdata.DeltaTemporality }
+	selector, _ = c.temporalitySelector()
+	assert.Equal(t, metricdata.DeltaTemporality, selector(metric.InstrumentKindObservableCounter))
+}
+
+func TestConfig_MetricHistogramAggregation(t *testing.T) {
+	c := &Config{MetricHistogramAggregation: "base2_exponential_bucket_histogram"}
+	selector, err := c.aggregationSelector()
+	assert.Nil(t, err)
+	assert.IsType(t, metric.AggregationBase2ExponentialHistogram{}, selector(metric.InstrumentKindHistogram))
+	assert.IsType(t, metric.AggregationSum{}, selector(metric.InstrumentKindCounter))
+}
+
+func TestExportMetricPipeline_UnsupportedTemporality(t *testing.T) {
+	exporter := NewExporter(IO, &Config{Writer: &bytes.Buffer{}, MetricTemporality: "sometimes"}, WithGlobalRegistration(false))
+
+	_, err := ExportMetricPipeline(context.TODO(), exporter)
+	assert.ErrorContains(t, err, `unsupported metric temporality "sometimes"`)
+}
@@ -0,0 +1,207 @@
+package otel
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+
+	"go.opentelemetry.io/otel/sdk/trace"
+)
+
+// defaultTenantCapacity is the number of tenant pipelines kept by default.
+const defaultTenantCapacity = 100
+
+// TenantExporterFactory builds and caches a pipeline per tenant, each from
+// a copy of the base Config changed by override, like the endpoint and API
+// key of the tenant:
+//
+//	factory := otel.NewTenantExporterFactory(otel.GRPC, otel.NewENVConfig(), func(tenant string, c *otel.Config) error {
+//		account, err := accounts.Get(tenant)
+//		if err != nil {
+//			return err
+//		}
+//		c.URL, c.APIKey = account.OTLPEndpoint, account.OTLPKey
+//		c.ResourceAttributes = append(c.ResourceAttributes, attribute.String("tenant.id", tenant))
+//		return nil
+//	}, 500)
+//	defer factory.Shutdown(context.Background())
+//
+//	provider, err := factory.TracerProvider(ctx, tenant)
+//
+// Once capacity pipelines are cached the least recently used one is shut
+// down to make room, spans still running on it are dropped when they end.
+// Its pipelines are never registered globally.
+type TenantExporterFactory struct {
+	outputType OutputType
+	base       *Config
+	override   func(tenant string, c *Config) error
+	capacity   int
+	opts       []Option
+
+	mu       sync.Mutex
+	lru      *list.List
+	tenants  map[string]*list.Element
+	building map[string]*tenantBuild
+}
+
+// tenantBuild is the pipeline of a tenant being built, the concurrent calls
+// for the tenant waiting for it to be done.
+type tenantBuild struct {
+	done     chan struct{}
+	pipeline *tenantPipeline
+	err      error
+}
+
+// tenantPipeline is an element of the LRU list of a TenantExporterFactory.
+type tenantPipeline struct {
+	tenant   string
+	exporter Exporter
+	provider *trace.TracerProvider
+}
+
+// NewTenantExporterFactory creates a factory building the pipelines of the
+// tenants of outputType, a capacity <= 0 keeps 100 of them. A nil override
+// uses base as is.
+func NewTenantExporterFactory(outputType OutputType, base *Config, override func(tenant string, c *Config) error, capacity int, opts ...Option) *TenantExporterFactory {
+	if capacity <= 0 {
+		capacity = defaultTenantCapacity
+	}
+
+	return &TenantExporterFactory{
+		outputType: outputType,
+		base:       base,
+		override:   override,
+		capacity:   capacity,
+		opts:       append(opts, WithGlobalRegistration(false)),
+		lru:        list.New(),
+		tenants:    make(map[string]*list.Element),
+		building:   make(map[string]*tenantBuild),
+	}
+}
+
+// TracerProvider returns the tracer provider of tenant, building its pipeline
+// on the first call.
+func (f *TenantExporterFactory) TracerProvider(ctx context.Context, tenant string) (*trace.TracerProvider, error) {
+	p, err := f.pipeline(ctx, tenant)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.provider, nil
+}
+
+// Exporter returns the exporter of tenant, building its pipeline on the
+// first call, for ExportMetricPipeline and ExportLogPipeline.
+func (f *TenantExporterFactory) Exporter(ctx context.Context, tenant string) (Exporter, error) {
+	p, err := f.pipeline(ctx, tenant)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.exporter, nil
+}
+
+// pipeline returns the cached pipeline of tenant, or builds it unlocked so
+// the detectors and connection of a tenant don't block the other ones.
+func (f *TenantExporterFactory) pipeline(ctx context.Context, tenant string) (*tenantPipeline, error) {
+	f.mu.Lock()
+	if e, ok := f.tenants[tenant]; ok {
+		f.lru.MoveToFront(e)
+		f.mu.Unlock()
+		return e.Value.(*tenantPipeline), nil
+	}
+	if b, ok := f.building[tenant]; ok {
+		f.mu.Unlock()
+		select {
+		case <-b.done:
+			return b.pipeline, b.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	b := &tenantBuild{done: make(chan struct{})}
+	f.building[tenant] = b
+	f.mu.Unlock()
+
+	b.pipeline, b.err = f.build(ctx, tenant)
+
+	f.mu.Lock()
+	delete(f.building, tenant)
+	if b.err == nil {
+		f.tenants[tenant] = f.lru.PushFront(b.pipeline)
+		if f.lru.Len() > f.capacity {
+			evicted := f.lru.Remove(f.lru.Back()).(*tenantPipeline)
+			delete(f.tenants, evicted.tenant)
+			go f.evict(evicted)
+		}
+	}
+	f.mu.Unlock()
+	close(b.done)
+
+	return b.pipeline, b.err
+}
+
+// build builds the pipeline of tenant from a copy of the base Config.
+func (f *TenantExporterFactory) build(ctx context.Context, tenant string) (*tenantPipeline, error) {
+	c := *f.base
+	// appending to the copied slices mustn't change the ones of base
+	c.ResourceAttributes = slices.Clip(c.ResourceAttributes)
+	c.SpanAttributes = slices.Clip(c.SpanAttributes)
+	if f.override != nil {
+		if err := f.override(tenant, &c); err != nil {
+			return nil, fmt.Errorf("could not configure tenant %q: %w", tenant, err)
+		}
+	}
+
+	exporter, provider, err := buildPipeline(ctx, f.outputType, &c, f.opts...)
+	if err != nil {
+		return nil, fmt.Errorf("could not build pipeline of tenant %q: %w", tenant, err)
+	}
+
+	return &tenantPipeline{tenant: tenant, exporter: exporter, provider: provider}, nil
+}
+
+// evict shuts down the pipeline of an evicted tenant, reporting failures
+// to the error handler.
+func (f *TenantExporterFactory) evict(p *tenantPipeline) {
+	if err := p.shutdown(context.Background()); err != nil {
+		handle(errorKindTenant, err)
+	}
+}
+
+func (p *tenantPipeline) shutdown(ctx context.Context) error {
+	state, _ := pipelineOf(p.exporter)
+	if err := state.shutdown(ctx); err != nil {
+		return fmt.Errorf("could not shut down pipeline of tenant %q: %w", p.tenant, err)
+	}
+
+	return nil
+}
+
+// Len returns the number of cached tenant pipelines.
+func (f *TenantExporterFactory) Len() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.lru.Len()
+}
+
+// Shutdown shuts down the pipelines of every tenant, a later call for a
+// tenant builds a new one.
+func (f *TenantExporterFactory) Shutdown(ctx context.Context) error {
+	f.mu.Lock()
+	pipelines := f.lru
+	f.lru = list.New()
+	f.tenants = make(map[string]*list.Element)
+	f.mu.Unlock()
+
+	var errs []error
+	for e := pipelines.Front(); e != nil; e = e.Next() {
+		errs = append(errs, e.Value.(*tenantPipeline).shutdown(ctx))
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,97 @@
+package otel
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/trace"
+)
+
+func TestTenantExporterFactory_CachesPipelinesPerTenant(t *testing.T) {
+	base := &Config{ServiceName: "orders", ResourceAttributes: make([]attribute.KeyValue, 0, 4)}
+	factory := NewTenantExporterFactory(Test, base, func(tenant string, c *Config) error {
+		if tenant == "" {
+			return errors.New("missing tenant")
+		}
+		c.ResourceAttributes = append(c.ResourceAttributes, attribute.String("tenant.id", tenant))
+		return nil
+	}, 2)
+
+	acme, err := factory.TracerProvider(context.TODO(), "acme")
+	assert.Nil(t, err)
+	again, _ := factory.TracerProvider(context.TODO(), "acme")
+	assert.Same(t, acme, again)
+	_, err = factory.TracerProvider(context.TODO(), "globex")
+	assert.Nil(t, err)
+	_, err = factory.TracerProvider(context.TODO(), "")
+	assert.EqualError(t, err, `could not configure tenant "": missing tenant`)
+	assert.Empty(t, base.ResourceAttributes)
+
+	_, span := acme.Tracer("sample").Start(context.TODO(), "sample span")
+	span.End()
+	exporter, _ := factory.Exporter(context.TODO(), "acme")
+	recorder, _ := Recorder(exporter)
+	snapshot, _ := Snapshot(exporter)
+	assert.Equal(t, "acme", snapshot.Resource["tenant.id"])
+	assert.Len(t, recorder.Spans(), 1)
+
+	// acme was used last, globex is evicted
+	_, err = factory.TracerProvider(context.TODO(), "initech")
+	assert.Nil(t, err)
+	assert.Equal(t, 2, factory.Len())
+	refreshed, _ := factory.TracerProvider(context.TODO(), "acme")
+	assert.Same(t, acme, refreshed)
+
+	assert.Nil(t, factory.Shutdown(context.TODO()))
+	assert.Equal(t, 0, factory.Len())
+	_, span = acme.Tracer("sample").Start(context.TODO(), "sample span")
+	span.End()
+	assert.Len(t, recorder.Spans(), 1)
+}
+
+func TestTenantExporterFactory_ShutsDownEvictedPipelines(t *testing.T) {
+	factory := NewTenantExporterFactory(Test, &Config{}, nil, 1)
+	acme, err := factory.TracerProvider(context.TODO(), "acme")
+	assert.Nil(t, err)
+
+	_, err = factory.TracerProvider(context.TODO(), "globex")
+	assert.Nil(t, err)
+
+	assert.Eventually(t, func() bool {
+		_, span := acme.Tracer("sample").Start(context.TODO(), "sample span")
+		defer span.End()
+		return !span.IsRecording()
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestTenantExporterFactory_BuildsTenantsConcurrently(t *testing.T) {
+	release := make(chan struct{})
+	factory := NewTenantExporterFactory(Test, &Config{}, func(tenant string, c *Config) error {
+		if tenant == "slow" {
+			<-release
+		}
+		return nil
+	}, 0)
+	defer factory.Shutdown(context.TODO())
+
+	slow := make(chan *trace.TracerProvider, 2)
+	for range 2 {
+		go func() {
+			provider, _ := factory.TracerProvider(context.TODO(), "slow")
+			slow <- provider
+		}()
+	}
+
+	ctx, cancel := context.WithTimeout(context.TODO(), time.Second)
+	defer cancel()
+	_, err := factory.TracerProvider(ctx, "fast")
+	assert.Nil(t, err, "not blocked by the slow tenant")
+
+	close(release)
+	assert.Same(t, <-slow, <-slow, "built once")
+	assert.Equal(t, 2, factory.Len())
+}
@@ -0,0 +1,100 @@
+package otel
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/sdk/trace"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// maxThrottleDelay caps the pause requested by the endpoint, the max elapsed
+// time of the retries of the OTLP exporters.
+const maxThrottleDelay = time.Minute
+
+// throttleExporter pauses the exports once the endpoint asked to retry later,
+// with a RetryInfo detail on a ResourceExhausted or Unavailable status, the
+// gRPC counterpart of a 429 or 503 Retry-After. The spans keep queuing in
+// the batch processor meanwhile so the batches exported after the pause are
+// bigger and less frequent.
+type throttleExporter struct {
+	trace.SpanExporter
+	done chan struct{}
+
+	mu    sync.Mutex
+	until time.Time
+	once  sync.Once
+}
+
+func newThrottleExporter(exp trace.SpanExporter) *throttleExporter {
+	return &throttleExporter{SpanExporter: exp, done: make(chan struct{})}
+}
+
+// ExportSpans implements the trace.SpanExporter interface.
+func (e *throttleExporter) ExportSpans(ctx context.Context, spans []trace.ReadOnlySpan) error {
+	if e.wait() {
+		// The export timeout of the batch processor may have elapsed during the pause.
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(context.WithoutCancel(ctx), workerExportTimeout)
+		defer cancel()
+	}
+
+	err := e.SpanExporter.ExportSpans(ctx, spans)
+	if delay, ok := retryDelay(err); ok {
+		e.mu.Lock()
+		e.until = time.Now().Add(min(delay, maxThrottleDelay))
+		e.mu.Unlock()
+	}
+
+	return err
+}
+
+// wait waits for the end of the pause, it reports whether there was one.
+func (e *throttleExporter) wait() bool {
+	e.mu.Lock()
+	delay := time.Until(e.until)
+	e.mu.Unlock()
+	if delay <= 0 {
+		return false
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-e.done:
+	}
+
+	return true
+}
+
+// Shutdown implements the trace.SpanExporter interface, it ends the pause so
+// the remaining spans are exported right away.
+func (e *throttleExporter) Shutdown(ctx context.Context) error {
+	e.once.Do(func() { close(e.done) })
+	return e.SpanExporter.Shutdown(ctx)
+}
+
+// retryDelay returns the delay the endpoint asked to wait for before the
+// next export.
+func retryDelay(err error) (time.Duration, bool) {
+	var grpcErr interface{ GRPCStatus() *status.Status }
+	if !errors.As(err, &grpcErr) {
+		return 0, false
+	}
+	s := grpcErr.GRPCStatus()
+	if s.Code() != codes.ResourceExhausted && s.Code() != codes.Unavailable {
+		return 0, false
+	}
+	for _, detail := range s.Details() {
+		if info, ok := detail.(*errdetails.RetryInfo); ok && info.GetRetryDelay() != nil {
+			return info.GetRetryDelay().AsDuration(), true
+		}
+	}
+
+	return 0, false
+}
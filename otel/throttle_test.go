@@ -0,0 +1,66 @@
+package otel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// throttledExporter asks to retry later after its first export.
+type throttledExporter struct {
+	trace.SpanExporter
+	delay   time.Duration
+	exports []time.Time
+}
+
+func (e *throttledExporter) ExportSpans(context.Context, []trace.ReadOnlySpan) error {
+	e.exports = append(e.exports, time.Now())
+	if len(e.exports) > 1 {
+		return nil
+	}
+	s, _ := status.New(codes.ResourceExhausted, "rate limited").
+		WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(e.delay)})
+	return s.Err()
+}
+
+func TestThrottleExporter_PausesOnRetryInfo(t *testing.T) {
+	exp := &throttledExporter{delay: 50 * time.Millisecond}
+	throttle := newThrottleExporter(exp)
+	batch := []trace.ReadOnlySpan{sampledSpan("sample span", otelcodes.Unset)}
+
+	assert.ErrorContains(t, throttle.ExportSpans(context.TODO(), batch), "rate limited")
+	assert.Nil(t, throttle.ExportSpans(context.TODO(), batch))
+	assert.GreaterOrEqual(t, exp.exports[1].Sub(exp.exports[0]), exp.delay)
+
+	start := time.Now()
+	assert.Nil(t, throttle.ExportSpans(context.TODO(), batch))
+	assert.Less(t, time.Since(start), exp.delay, "the pause is over")
+}
+
+func TestRetryDelay_RequiresRetryInfo(t *testing.T) {
+	_, ok := retryDelay(status.Error(codes.ResourceExhausted, "rate limited"))
+	assert.False(t, ok)
+	_, ok = retryDelay(errors.New("rate limited"))
+	assert.False(t, ok)
+
+	s, _ := status.New(codes.InvalidArgument, "invalid").
+		WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(time.Second)})
+	_, ok = retryDelay(s.Err())
+	assert.False(t, ok)
+
+	s, _ = status.New(codes.Unavailable, "unavailable").
+		WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(time.Second)})
+	delay, ok := retryDelay(fmt.Errorf("traces export: %w", s.Err()))
+	assert.True(t, ok)
+	assert.Equal(t, time.Second, delay)
+}
@@ -0,0 +1,226 @@
+package otel
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/credentials"
+)
+
+// tlsConfig returns the TLS configuration of the GRPC output: the system
+// roots or the ones of CAFile, with the client certificate of ClientCertFile
+// and ClientKeyFile when set, restricted by the TLS settings of the Config.
+func (c *Config) tlsConfig() (*tls.Config, error) {
+	config, _, err := c.loadTLSConfig()
+	return config, err
+}
+
+func (c *Config) loadTLSConfig() (*tls.Config, *tlsFiles, error) {
+	if (c.ClientCertFile == "") != (c.ClientKeyFile == "") {
+		return nil, nil, errors.New("client certificate and key files must be set together")
+	}
+	config, err := c.tlsPolicy()
+	if err != nil {
+		return nil, nil, err
+	}
+	files := &tlsFiles{config: c}
+	if err := files.load(); err != nil {
+		return nil, nil, err
+	}
+
+	config.RootCAs = files.roots
+	if c.ClientCertFile != "" {
+		config.GetClientCertificate = files.clientCertificate
+	}
+
+	return config, files, nil
+}
+
+// tlsCredentials returns the transport credentials of the GRPC output with
+// the tlsConfig. The files are loaded again once they change, like
+// certificates rotated by a mesh, the next handshakes using them without
+// restarting the pipeline.
+func (c *Config) tlsCredentials() (credentials.TransportCredentials, error) {
+	config, files, err := c.loadTLSConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	return &reloadingCredentials{TransportCredentials: credentials.NewTLS(config), config: config, files: files}, nil
+}
+
+// reloadingCredentials verifies the endpoint against the current roots of
+// its files on every handshake, with the default verification of the
+// endpoint host.
+type reloadingCredentials struct {
+	credentials.TransportCredentials
+	config *tls.Config
+	files  *tlsFiles
+}
+
+// ClientHandshake implements the credentials.TransportCredentials interface.
+func (c *reloadingCredentials) ClientHandshake(ctx context.Context, authority string, conn net.Conn) (net.Conn, credentials.AuthInfo, error) {
+	config := c.config.Clone()
+	config.RootCAs = c.files.currentRoots()
+
+	return credentials.NewTLS(config).ClientHandshake(ctx, authority, conn)
+}
+
+// Clone implements the credentials.TransportCredentials interface.
+func (c *reloadingCredentials) Clone() credentials.TransportCredentials {
+	return &reloadingCredentials{TransportCredentials: c.TransportCredentials.Clone(), config: c.config.Clone(), files: c.files}
+}
+
+// tlsVersions are the minimum TLS versions supported by Config.TLSMinVersion.
+var tlsVersions = map[string]uint16{
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// fipsCipherSuites are the FIPS 140-3 approved TLS 1.2 cipher suites.
+var fipsCipherSuites = []uint16{
+	tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+	tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+}
+
+// fipsTLS13CipherSuites are the FIPS 140-3 approved TLS 1.3 cipher suites,
+// Go negotiating TLS_CHACHA20_POLY1305_SHA256 too unless GODEBUG=fips140=only.
+var fipsTLS13CipherSuites = []uint16{
+	tls.TLS_AES_128_GCM_SHA256,
+	tls.TLS_AES_256_GCM_SHA384,
+}
+
+// verifyFIPSCipherSuite fails the handshakes which negotiated a cipher suite
+// not FIPS approved, TLS 1.3 ones not being configurable.
+func verifyFIPSCipherSuite(state tls.ConnectionState) error {
+	if !slices.Contains(fipsCipherSuites, state.CipherSuite) && !slices.Contains(fipsTLS13CipherSuites, state.CipherSuite) {
+		return fmt.Errorf("TLS cipher suite %s isn't FIPS approved", tls.CipherSuiteName(state.CipherSuite))
+	}
+
+	return nil
+}
+
+// tlsPolicy returns a TLS configuration with the versions, cipher suites and
+// curves allowed by TLSMinVersion, TLSCipherSuites and TLSFIPS.
+func (c *Config) tlsPolicy() (*tls.Config, error) {
+	config := &tls.Config{}
+	if c.TLSMinVersion != "" {
+		version, ok := tlsVersions[c.TLSMinVersion]
+		if !ok {
+			return nil, fmt.Errorf("unsupported TLS min version %q, use 1.2 or 1.3", c.TLSMinVersion)
+		}
+		config.MinVersion = version
+	}
+
+	for _, name := range c.TLSCipherSuites {
+		i := slices.IndexFunc(tls.CipherSuites(), func(s *tls.CipherSuite) bool { return s.Name == name })
+		if i < 0 {
+			return nil, fmt.Errorf("unknown or insecure TLS cipher suite %q", name)
+		}
+		config.CipherSuites = append(config.CipherSuites, tls.CipherSuites()[i].ID)
+	}
+
+	if c.TLSFIPS {
+		config.MinVersion = max(config.MinVersion, tls.VersionTLS12)
+		config.CurvePreferences = []tls.CurveID{tls.CurveP256, tls.CurveP384}
+		config.VerifyConnection = verifyFIPSCipherSuite
+		if config.CipherSuites == nil {
+			config.CipherSuites = fipsCipherSuites
+		}
+		for _, suite := range config.CipherSuites {
+			if !slices.Contains(fipsCipherSuites, suite) {
+				return nil, fmt.Errorf("TLS cipher suite %s isn't FIPS approved", tls.CipherSuiteName(suite))
+			}
+		}
+	}
+
+	return config, nil
+}
+
+// tlsFiles holds the roots and the client certificate loaded from the files
+// of a Config, loaded again when their modification time changes.
+type tlsFiles struct {
+	config *Config
+
+	mu       sync.Mutex
+	modTimes []time.Time
+	roots    *x509.CertPool
+	cert     *tls.Certificate
+}
+
+// load loads the files when they changed since the last load.
+func (f *tlsFiles) load() error {
+	c := f.config
+	var modTimes []time.Time
+	for _, path := range []string{c.CAFile, c.ClientCertFile, c.ClientKeyFile} {
+		var modTime time.Time
+		if info, err := os.Stat(path); err == nil {
+			modTime = info.ModTime()
+		}
+		modTimes = append(modTimes, modTime)
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if slices.Equal(modTimes, f.modTimes) {
+		return nil
+	}
+
+	if c.CAFile != "" {
+		pem, err := os.ReadFile(c.CAFile)
+		if err != nil {
+			return fmt.Errorf("could not read CA file: %w", err)
+		}
+		roots := x509.NewCertPool()
+		if !roots.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("no certificate found in CA file %s", c.CAFile)
+		}
+		f.roots = roots
+	}
+	if c.ClientCertFile != "" {
+		cert, err := tls.LoadX509KeyPair(c.ClientCertFile, c.ClientKeyFile)
+		if err != nil {
+			return fmt.Errorf("could not load client certificate: %w", err)
+		}
+		f.cert = &cert
+	}
+	f.modTimes = modTimes
+
+	return nil
+}
+
+// reload loads the changed files, keeping the previous certificates when
+// they can't be loaded, like while they're being written.
+func (f *tlsFiles) reload() {
+	if err := f.load(); err != nil {
+		handle(errorKindTLSReload, fmt.Errorf("could not reload TLS files, keeping the previous ones: %w", err))
+	}
+}
+
+// currentRoots returns the current roots, nil for the system ones.
+func (f *tlsFiles) currentRoots() *x509.CertPool {
+	f.reload()
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.roots
+}
+
+// clientCertificate returns the current client certificate.
+func (f *tlsFiles) clientCertificate(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+	f.reload()
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.cert, nil
+}
@@ -0,0 +1,153 @@
+package otel
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/credentials"
+)
+
+func TestConfig_TLSConfig(t *testing.T) {
+	server := httptest.NewUnstartedServer(nil)
+	server.EnableHTTP2 = true
+	server.StartTLS()
+	defer server.Close()
+	dir := t.TempDir()
+	caFile := filepath.Join(dir, "ca.pem")
+	assert.Nil(t, os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), 0o600))
+
+	config, err := (&Config{CAFile: caFile}).tlsConfig()
+	assert.Nil(t, err)
+	assert.Nil(t, handshake(server, credentials.NewTLS(config)))
+
+	config, err = (&Config{}).tlsConfig()
+	assert.Nil(t, err)
+	assert.Nil(t, config.RootCAs)
+
+	_, err = (&Config{CAFile: filepath.Join(dir, "missing.pem")}).tlsConfig()
+	assert.ErrorContains(t, err, "could not read CA file")
+
+	_, err = (&Config{ClientCertFile: caFile}).tlsConfig()
+	assert.EqualError(t, err, "client certificate and key files must be set together")
+}
+
+// handshake connects to server with creds, like the GRPC connection.
+func handshake(server *httptest.Server, creds credentials.TransportCredentials) error {
+	addr := server.Listener.Addr().String()
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	_, _, err = creds.ClientHandshake(context.TODO(), addr, conn)
+
+	return err
+}
+
+// selfSignedServer starts a TLS server with its own self-signed certificate,
+// unlike the certificate shared by the httptest servers.
+func selfSignedServer(t *testing.T, ip net.IP) *httptest.Server {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		IPAddresses:  []net.IP{ip},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.Nil(t, err)
+
+	server := httptest.NewUnstartedServer(nil)
+	server.EnableHTTP2 = true
+	server.TLS = &tls.Config{Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}}}
+	server.StartTLS()
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestConfig_TLSConfigReloadsRotatedCA(t *testing.T) {
+	rotated := selfSignedServer(t, net.IPv4(127, 0, 0, 1))
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	writeCA := func(server *httptest.Server, modTime time.Time) {
+		assert.Nil(t, os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), 0o600))
+		assert.Nil(t, os.Chtimes(caFile, modTime, modTime))
+	}
+	writeCA(selfSignedServer(t, net.IPv4(127, 0, 0, 1)), time.Now().Add(-time.Hour))
+
+	creds, err := (&Config{CAFile: caFile}).tlsCredentials()
+	assert.Nil(t, err)
+	assert.ErrorContains(t, handshake(rotated, creds), "certificate signed by unknown authority")
+
+	writeCA(rotated, time.Now())
+	assert.Nil(t, handshake(rotated, creds))
+	assert.Nil(t, handshake(rotated, creds.Clone()))
+}
+
+func TestConfig_TLSCredentialsVerifiesEndpointIP(t *testing.T) {
+	server := selfSignedServer(t, net.IPv4(10, 9, 9, 9))
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	assert.Nil(t, os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), 0o600))
+
+	creds, err := (&Config{CAFile: caFile}).tlsCredentials()
+	assert.Nil(t, err)
+	assert.ErrorContains(t, handshake(server, creds), "not 127.0.0.1")
+}
+
+func TestConfig_TLSPolicy(t *testing.T) {
+	config, err := (&Config{
+		TLSMinVersion:   "1.2",
+		TLSCipherSuites: []string{"TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256"},
+	}).tlsConfig()
+	assert.Nil(t, err)
+	assert.Equal(t, uint16(tls.VersionTLS12), config.MinVersion)
+	assert.Equal(t, []uint16{tls.TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256}, config.CipherSuites)
+
+	config, err = (&Config{TLSFIPS: true}).tlsConfig()
+	assert.Nil(t, err)
+	assert.Equal(t, uint16(tls.VersionTLS12), config.MinVersion)
+	assert.Equal(t, fipsCipherSuites, config.CipherSuites)
+	assert.Equal(t, []tls.CurveID{tls.CurveP256, tls.CurveP384}, config.CurvePreferences)
+	assert.Nil(t, config.VerifyConnection(tls.ConnectionState{Version: tls.VersionTLS13, CipherSuite: tls.TLS_AES_128_GCM_SHA256}))
+	assert.EqualError(t, config.VerifyConnection(tls.ConnectionState{Version: tls.VersionTLS13, CipherSuite: tls.TLS_CHACHA20_POLY1305_SHA256}),
+		"TLS cipher suite TLS_CHACHA20_POLY1305_SHA256 isn't FIPS approved")
+
+	_, err = (&Config{TLSMinVersion: "1.0"}).tlsConfig()
+	assert.EqualError(t, err, `unsupported TLS min version "1.0", use 1.2 or 1.3`)
+	_, err = (&Config{TLSCipherSuites: []string{"TLS_RSA_WITH_RC4_128_SHA"}}).tlsConfig()
+	assert.EqualError(t, err, `unknown or insecure TLS cipher suite "TLS_RSA_WITH_RC4_128_SHA"`)
+	_, err = (&Config{TLSFIPS: true, TLSCipherSuites: []string{"TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256"}}).tlsConfig()
+	assert.EqualError(t, err, "TLS cipher suite TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256 isn't FIPS approved")
+}
+
+func TestConfig_TLSMinVersionEnforced(t *testing.T) {
+	server := httptest.NewUnstartedServer(nil)
+	server.EnableHTTP2 = true
+	server.TLS = &tls.Config{MaxVersion: tls.VersionTLS12}
+	server.StartTLS()
+	defer server.Close()
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	assert.Nil(t, os.WriteFile(caFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), 0o600))
+
+	config, err := (&Config{CAFile: caFile, TLSMinVersion: "1.3"}).tlsConfig()
+	assert.Nil(t, err)
+	assert.ErrorContains(t, handshake(server, credentials.NewTLS(config)), "protocol version")
+
+	config, err = (&Config{CAFile: caFile, TLSFIPS: true}).tlsConfig()
+	assert.Nil(t, err)
+	assert.Nil(t, handshake(server, credentials.NewTLS(config)))
+}
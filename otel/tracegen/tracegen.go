@@ -0,0 +1,161 @@
+// Package tracegen synthesizes multi service traces through the pipelines
+// of the otel package, to load test collectors and validate dashboards
+// before production traffic arrives.
+package tracegen
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"github.com/rezazadehramin/opentelemetry-go/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+// Config describes the shape of the generated traces.
+//
+// Every trace starts on the first service, each level of the span tree
+// calls the next service so a trace of depth N crosses N services (cycling
+// through Services).
+// FanOut is the number of downstream calls per span, so a trace holds
+// FanOut^Depth leaf calls. ErrorRate is the probability for a call to fail.
+// Seed makes the generated traces reproducible.
+type Config struct {
+	Services     []string
+	Traces       int
+	Depth        int
+	FanOut       int
+	ErrorRate    float64
+	SpanDuration time.Duration
+	Seed         uint64
+}
+
+// Stats summarizes what Generate sent through the pipelines.
+type Stats struct {
+	Traces int
+	Spans  int
+	Errors int
+}
+
+type generator struct {
+	Config
+	rand    *rand.Rand
+	tracers []oteltrace.Tracer
+	stats   Stats
+}
+
+// Generate sends synthetic traces through one pipeline per service, built
+// from base with the service name replaced, a nil base being the zero
+// Config. The pipelines are flushed and shut down before returning.
+func Generate(ctx context.Context, outputType otel.OutputType, base *otel.Config, cfg Config) (Stats, error) {
+	if len(cfg.Services) == 0 {
+		return Stats{}, errors.New("at least one service is required")
+	}
+	if cfg.Depth < 1 {
+		cfg.Depth = 1
+	}
+	if cfg.FanOut < 1 {
+		cfg.FanOut = 1
+	}
+	if cfg.SpanDuration <= 0 {
+		cfg.SpanDuration = 10 * time.Millisecond
+	}
+	if base == nil {
+		base = &otel.Config{}
+	}
+
+	g := &generator{
+		Config: cfg,
+		rand:   rand.New(rand.NewPCG(cfg.Seed, cfg.Seed)),
+	}
+
+	var providers []*trace.TracerProvider
+	defer func() {
+		for _, provider := range providers {
+			_ = provider.Shutdown(ctx)
+		}
+	}()
+
+	for _, service := range cfg.Services {
+		c := *base
+		c.ServiceName = service
+
+		provider, err := otel.NewExporter(outputType, &c, otel.WithGlobalRegistration(false)).ExportPipeline(ctx)
+		if err != nil {
+			return Stats{}, fmt.Errorf("could not create pipeline for %s: %w", service, err)
+		}
+		providers = append(providers, provider)
+		g.tracers = append(g.tracers, provider.Tracer("github.com/rezazadehramin/opentelemetry-go/otel/tracegen"))
+	}
+
+	start := time.Now()
+	for i := 0; i < cfg.Traces; i++ {
+		g.span(ctx, 0, "GET /"+cfg.Services[0], oteltrace.SpanKindServer, start)
+		g.stats.Traces++
+	}
+
+	for _, provider := range providers {
+		if err := provider.ForceFlush(ctx); err != nil {
+			return g.stats, fmt.Errorf("could not flush pipeline: %w", err)
+		}
+	}
+
+	return g.stats, nil
+}
+
+// span generates the span of a service call at the given level of the tree
+// and its downstream calls, returning when the span ended.
+func (g *generator) span(ctx context.Context, level int, name string, kind oteltrace.SpanKind, start time.Time) time.Time {
+	tracer := g.tracers[level%len(g.tracers)]
+	ctx, span := tracer.Start(ctx, name,
+		oteltrace.WithSpanKind(kind),
+		oteltrace.WithTimestamp(start),
+		oteltrace.WithAttributes(attribute.Int("tracegen.level", level)),
+	)
+	g.stats.Spans++
+
+	end := start.Add(g.jitter())
+	if level+1 < g.Depth {
+		for i := 0; i < g.FanOut; i++ {
+			end = g.call(ctx, level+1, end)
+		}
+	}
+	end = end.Add(g.jitter())
+
+	if g.rand.Float64() < g.ErrorRate {
+		span.SetStatus(codes.Error, "synthetic error")
+		g.stats.Errors++
+	}
+	span.End(oteltrace.WithTimestamp(end))
+
+	return end
+}
+
+// call generates a client span on the caller side wrapping the server
+// span of the downstream service.
+func (g *generator) call(ctx context.Context, level int, start time.Time) time.Time {
+	service := g.Services[level%len(g.Services)]
+	caller := g.tracers[(level-1)%len(g.tracers)]
+
+	ctx, span := caller.Start(ctx, "call "+service,
+		oteltrace.WithSpanKind(oteltrace.SpanKindClient),
+		oteltrace.WithTimestamp(start),
+	)
+	g.stats.Spans++
+
+	end := g.span(ctx, level, "GET /"+service, oteltrace.SpanKindServer, start.Add(time.Millisecond))
+	end = end.Add(time.Millisecond)
+	span.End(oteltrace.WithTimestamp(end))
+
+	return end
+}
+
+// jitter returns a random duration around the configured span duration.
+func (g *generator) jitter() time.Duration {
+	return g.SpanDuration/2 + time.Duration(g.rand.Int64N(int64(g.SpanDuration)))
+}
@@ -0,0 +1,59 @@
+package tracegen
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/rezazadehramin/opentelemetry-go/otel"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerate_MultiServiceTraces(t *testing.T) {
+	var out bytes.Buffer
+	stats, err := Generate(context.TODO(), otel.IO, &otel.Config{Writer: &out}, Config{
+		Services: []string{"frontend", "checkout", "payments"},
+		Traces:   2,
+		Depth:    3,
+		FanOut:   2,
+		Seed:     42,
+	})
+	assert.Nil(t, err)
+
+	// root + 2 + 4 calls per trace, each call being a client and a server span
+	assert.Equal(t, Stats{Traces: 2, Spans: 26, Errors: 0}, stats)
+
+	decoder := json.NewDecoder(&out)
+	spans := 0
+	for {
+		var span map[string]interface{}
+		if err := decoder.Decode(&span); err == io.EOF {
+			break
+		}
+		spans++
+	}
+	assert.Equal(t, 26, spans)
+}
+
+func TestGenerate_ErrorRate(t *testing.T) {
+	stats, err := Generate(context.TODO(), otel.IO, &otel.Config{Writer: io.Discard}, Config{
+		Services:  []string{"frontend"},
+		Traces:    10,
+		ErrorRate: 1,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 10, stats.Errors)
+}
+
+func TestGenerate_RequiresServices(t *testing.T) {
+	_, err := Generate(context.TODO(), otel.IO, &otel.Config{}, Config{Traces: 1})
+	assert.NotNil(t, err)
+}
+
+func TestGenerate_NilBase(t *testing.T) {
+	stats, err := Generate(context.TODO(), otel.Test, nil, Config{Services: []string{"frontend"}, Traces: 1})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, stats.Traces)
+}
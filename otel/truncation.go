@@ -0,0 +1,159 @@
+package otel
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/trace"
+)
+
+// TruncatedKey is set on the spans of which an attribute value was truncated.
+const TruncatedKey = attribute.Key("otel.truncated")
+
+// TruncationProcessor is a span processor truncating the string attribute
+// values of ended spans, their events and links longer than a number of
+// bytes before handing them to next, usually the batch processor of the
+// exporter, so giant SQL statements or payload dumps don't break exports.
+// Spans with a truncated value get the otel.truncated=true attribute.
+// Set Config.MaxAttributeLength and Config.AttributeLengthLimits, or wrap a
+// processor and register it on the provider returned by ExportPipeline with
+// RegisterSpanProcessor.
+//
+// Values are cut on a UTF-8 character boundary, the elements of string
+// slices are truncated one by one.
+type TruncationProcessor struct {
+	next   trace.SpanProcessor
+	limit  int
+	limits map[string]int
+}
+
+var _ trace.SpanProcessor = (*TruncationProcessor)(nil)
+
+// NewTruncationProcessor creates a processor truncating the string attribute
+// values to limit bytes before handing them to next. limits overrides it per
+// key, keys ending with ".*" matching every key of that prefix like "db.*",
+// a limit <= 0 leaving the values of that key as is.
+func NewTruncationProcessor(next trace.SpanProcessor, limit int, limits map[string]int) *TruncationProcessor {
+	return &TruncationProcessor{next: next, limit: limit, limits: limits}
+}
+
+// limitOf returns the limit of the values of key, exact keys taking
+// precedence over the longest matching prefix.
+func (p *TruncationProcessor) limitOf(key attribute.Key) int {
+	if limit, ok := p.limits[string(key)]; ok {
+		return limit
+	}
+	limit, matched := p.limit, ""
+	for k, l := range p.limits {
+		if len(k) > len(matched) && matchKey([]string{k}, key) {
+			limit, matched = l, k
+		}
+	}
+
+	return limit
+}
+
+func (p *TruncationProcessor) truncate(attrs []attribute.KeyValue) ([]attribute.KeyValue, bool) {
+	var truncated []attribute.KeyValue
+	for i, kv := range attrs {
+		limit := p.limitOf(kv.Key)
+		if limit <= 0 {
+			continue
+		}
+
+		var value attribute.Value
+		switch kv.Value.Type() {
+		case attribute.STRING:
+			s, cut := truncateString(kv.Value.AsString(), limit)
+			if !cut {
+				continue
+			}
+			value = attribute.StringValue(s)
+		case attribute.STRINGSLICE:
+			values := kv.Value.AsStringSlice()
+			var changed bool
+			for j, v := range values {
+				var cut bool
+				values[j], cut = truncateString(v, limit)
+				changed = changed || cut
+			}
+			if !changed {
+				continue
+			}
+			value = attribute.StringSliceValue(values)
+		default:
+			continue
+		}
+
+		if truncated == nil {
+			truncated = append(attrs[:0:0], attrs...)
+		}
+		truncated[i] = attribute.KeyValue{Key: kv.Key, Value: value}
+	}
+	if truncated == nil {
+		return attrs, false
+	}
+
+	return truncated, true
+}
+
+// truncateString cuts s to at most limit bytes on a character boundary.
+func truncateString(s string, limit int) (string, bool) {
+	if len(s) <= limit {
+		return s, false
+	}
+	for limit > 0 && !utf8.RuneStart(s[limit]) {
+		limit--
+	}
+
+	return s[:limit], true
+}
+
+// OnStart implements the trace.SpanProcessor interface.
+func (p *TruncationProcessor) OnStart(ctx context.Context, s trace.ReadWriteSpan) {
+	p.next.OnStart(ctx, s)
+}
+
+// OnEnd implements the trace.SpanProcessor interface.
+func (p *TruncationProcessor) OnEnd(s trace.ReadOnlySpan) {
+	span, truncated := mapAttributes(s, p.truncate)
+	if !truncated {
+		p.next.OnEnd(s)
+		return
+	}
+	span.attributes = append(span.attributes, TruncatedKey.Bool(true))
+	p.next.OnEnd(span)
+}
+
+// Shutdown implements the trace.SpanProcessor interface.
+func (p *TruncationProcessor) Shutdown(ctx context.Context) error {
+	return p.next.Shutdown(ctx)
+}
+
+// ForceFlush implements the trace.SpanProcessor interface.
+func (p *TruncationProcessor) ForceFlush(ctx context.Context) error {
+	return p.next.ForceFlush(ctx)
+}
+
+// lengthLimitsEnv reads key=limit pairs like "db.statement=4096" from the
+// environment, invalid limits are reported and ignored.
+func lengthLimitsEnv(key string) map[string]int {
+	var limits map[string]int
+	for _, entry := range listEnv(key) {
+		k, v, _ := strings.Cut(entry, "=")
+		limit, err := strconv.Atoi(strings.TrimSpace(v))
+		if k = strings.TrimSpace(k); k == "" || err != nil {
+			invalidEnv(key, entry)
+			continue
+		}
+		if limits == nil {
+			limits = make(map[string]int)
+		}
+		limits[k] = limit
+	}
+
+	return limits
+}
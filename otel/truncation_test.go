@@ -0,0 +1,63 @@
+package otel
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+func TestTruncationProcessor_TruncatesLongValues(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := trace.NewTracerProvider(trace.WithSpanProcessor(NewTruncationProcessor(recorder, 4, map[string]int{
+		"db.statement":        8,
+		"http.request.body.*": 0,
+	})))
+	defer tp.Shutdown(context.TODO())
+
+	_, span := tp.Tracer("sample").Start(context.TODO(), "query", oteltrace.WithAttributes(
+		attribute.String("user.name", "aéé"),
+		attribute.String("db.statement", "SELECT * FROM users"),
+		attribute.String("http.request.body.content", "a huge payload"),
+		attribute.StringSlice("tags", []string{"ab", "abcdef"}),
+		attribute.Int("retries", 123456),
+	))
+	span.AddEvent("sent", oteltrace.WithAttributes(attribute.String("payload", "abcdef")))
+	span.End()
+
+	ended := recorder.Ended()[0]
+	assert.Equal(t, []attribute.KeyValue{
+		attribute.String("user.name", "aé"),
+		attribute.String("db.statement", "SELECT *"),
+		attribute.String("http.request.body.content", "a huge payload"),
+		attribute.StringSlice("tags", []string{"ab", "abcd"}),
+		attribute.Int("retries", 123456),
+		TruncatedKey.Bool(true),
+	}, ended.Attributes())
+	assert.Equal(t, "abcd", ended.Events()[0].Attributes[0].Value.AsString())
+}
+
+func TestTruncationProcessor_KeepsShortValues(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	tp := trace.NewTracerProvider(trace.WithSpanProcessor(NewTruncationProcessor(recorder, 16, nil)))
+	defer tp.Shutdown(context.TODO())
+
+	_, span := tp.Tracer("sample").Start(context.TODO(), "query",
+		oteltrace.WithAttributes(attribute.String("db.statement", "SELECT 1")))
+	span.End()
+
+	assert.Equal(t, []attribute.KeyValue{attribute.String("db.statement", "SELECT 1")}, recorder.Ended()[0].Attributes())
+}
+
+func TestLengthLimitsEnv_ParsesPairs(t *testing.T) {
+	os.Setenv("OTEL_ATTRIBUTE_LENGTH_LIMITS", "db.statement=16384, http.*=0,invalid,sample=x")
+	defer os.Unsetenv("OTEL_ATTRIBUTE_LENGTH_LIMITS")
+
+	assert.Equal(t, map[string]int{"db.statement": 16384, "http.*": 0}, lengthLimitsEnv("OTEL_ATTRIBUTE_LENGTH_LIMITS"))
+	assert.Nil(t, lengthLimitsEnv("OTEL_UNSET_LENGTH_LIMITS"))
+}
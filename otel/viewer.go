@@ -0,0 +1,239 @@
+package otel
+
+import (
+	"context"
+	"errors"
+	"html/template"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+// Bounds of the traces kept by the trace viewer.
+const (
+	viewerMaxTraces = 100
+	viewerMaxSpans  = 1000
+)
+
+// traceBuffer keeps the spans of the latest traces ended on a pipeline.
+type traceBuffer struct {
+	mu     sync.Mutex
+	order  []oteltrace.TraceID
+	traces map[oteltrace.TraceID][]trace.ReadOnlySpan
+}
+
+var _ trace.SpanProcessor = (*traceBuffer)(nil)
+
+func newTraceBuffer() *traceBuffer {
+	return &traceBuffer{traces: make(map[oteltrace.TraceID][]trace.ReadOnlySpan)}
+}
+
+// OnStart implements the trace.SpanProcessor interface.
+func (b *traceBuffer) OnStart(context.Context, trace.ReadWriteSpan) {}
+
+// OnEnd implements the trace.SpanProcessor interface.
+func (b *traceBuffer) OnEnd(s trace.ReadOnlySpan) {
+	id := s.SpanContext().TraceID()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	spans, ok := b.traces[id]
+	if !ok {
+		if len(b.order) == viewerMaxTraces {
+			delete(b.traces, b.order[0])
+			b.order = b.order[1:]
+		}
+		b.order = append(b.order, id)
+	}
+	if len(spans) < viewerMaxSpans {
+		b.traces[id] = append(spans, s)
+	}
+}
+
+// Shutdown implements the trace.SpanProcessor interface.
+func (b *traceBuffer) Shutdown(context.Context) error {
+	return nil
+}
+
+// ForceFlush implements the trace.SpanProcessor interface.
+func (b *traceBuffer) ForceFlush(context.Context) error {
+	return nil
+}
+
+// waterfall is a trace as rendered by the viewer.
+type waterfall struct {
+	ID       string
+	Root     string
+	Start    time.Time
+	Duration string
+	Error    bool
+	Rows     []waterfallRow
+}
+
+// waterfallRow is a span of a waterfall, Offset and Width are percentages
+// of the trace duration.
+type waterfallRow struct {
+	Name       string
+	Depth      int
+	Kind       string
+	Duration   string
+	Error      bool
+	Status     string
+	Attributes string
+	Offset     float64
+	Width      float64
+}
+
+// waterfalls returns the buffered traces, the latest first.
+func (b *traceBuffer) waterfalls() []waterfall {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	waterfalls := make([]waterfall, 0, len(b.order))
+	for i := len(b.order) - 1; i >= 0; i-- {
+		waterfalls = append(waterfalls, newWaterfall(b.order[i], b.traces[b.order[i]]))
+	}
+
+	return waterfalls
+}
+
+func newWaterfall(id oteltrace.TraceID, spans []trace.ReadOnlySpan) waterfall {
+	roots, children := spanTree(spans)
+	start, end := spans[0].StartTime(), spans[0].EndTime()
+	for _, s := range spans {
+		if s.StartTime().Before(start) {
+			start = s.StartTime()
+		}
+		if s.EndTime().After(end) {
+			end = s.EndTime()
+		}
+	}
+	total := float64(end.Sub(start))
+	if total <= 0 {
+		total = 1
+	}
+
+	w := waterfall{ID: id.String(), Root: roots[0].Name(), Start: start, Duration: formatDuration(end.Sub(start))}
+	var walk func(s trace.ReadOnlySpan, depth int)
+	walk = func(s trace.ReadOnlySpan, depth int) {
+		row := waterfallRow{
+			Name:     s.Name(),
+			Depth:    depth,
+			Kind:     s.SpanKind().String(),
+			Duration: formatDuration(s.EndTime().Sub(s.StartTime())),
+			Error:    s.Status().Code == codes.Error,
+			Status:   s.Status().Description,
+			Offset:   100 * float64(s.StartTime().Sub(start)) / total,
+			Width:    max(100*float64(s.EndTime().Sub(s.StartTime()))/total, 0.5),
+		}
+		for i, kv := range s.Attributes() {
+			if i > 0 {
+				row.Attributes += " "
+			}
+			row.Attributes += string(kv.Key) + "=" + kv.Value.Emit()
+		}
+		w.Error = w.Error || row.Error
+		w.Rows = append(w.Rows, row)
+		for _, child := range children[s.SpanContext().SpanID()] {
+			walk(child, depth+1)
+		}
+	}
+	for _, s := range roots {
+		walk(s, 0)
+	}
+
+	return w
+}
+
+// spanTree sorts the spans of a trace by start time, returning the ones
+// whose parent isn't among them and the children of every span.
+func spanTree(spans []trace.ReadOnlySpan) ([]trace.ReadOnlySpan, map[oteltrace.SpanID][]trace.ReadOnlySpan) {
+	spans = append([]trace.ReadOnlySpan(nil), spans...)
+	sort.SliceStable(spans, func(i, j int) bool { return spans[i].StartTime().Before(spans[j].StartTime()) })
+
+	ids := make(map[oteltrace.SpanID]bool, len(spans))
+	for _, s := range spans {
+		ids[s.SpanContext().SpanID()] = true
+	}
+	var roots []trace.ReadOnlySpan
+	children := make(map[oteltrace.SpanID][]trace.ReadOnlySpan)
+	for _, s := range spans {
+		if parent := s.Parent().SpanID(); ids[parent] {
+			children[parent] = append(children[parent], s)
+		} else {
+			roots = append(roots, s)
+		}
+	}
+
+	return roots, children
+}
+
+var viewerTemplate = template.Must(template.New("viewer").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>traces</title>
+<style>
+body { font: 13px monospace; margin: 1em; }
+details { margin-bottom: .5em; }
+summary { cursor: pointer; }
+table { width: 100%; border-collapse: collapse; }
+td { padding: 1px 4px; white-space: nowrap; }
+td.bar { width: 60%; }
+.bar div { height: 12px; background: #4c8bf5; }
+.error { color: #d93025; }
+.error .bar div { background: #d93025; }
+.dim { color: #888; }
+</style>
+</head>
+<body>
+<h3>{{len .}} recent traces</h3>
+{{range .}}
+<details>
+<summary{{if .Error}} class="error"{{end}}>{{.Start.Format "15:04:05.000"}} {{.Root}} <span class="dim">{{.Duration}} {{len .Rows}} spans {{.ID}}</span></summary>
+<table>
+{{range .Rows}}
+<tr{{if .Error}} class="error"{{end}} title="{{.Attributes}}">
+<td style="padding-left: {{.Depth}}em">{{.Name}}</td>
+<td class="dim">{{.Kind}}</td>
+<td>{{.Duration}}</td>
+<td class="bar"><div style="margin-left: {{printf "%.2f" .Offset}}%; width: {{printf "%.2f" .Width}}%"></div></td>
+<td class="dim">{{.Status}}</td>
+</tr>
+{{end}}
+</table>
+</details>
+{{end}}
+</body>
+</html>
+`))
+
+// TraceViewerHandler returns a page rendering the latest traces of the
+// pipeline built by e with WithTraceViewer as waterfalls, children indented
+// under their parent, for development:
+//
+//	viewer, err := otel.TraceViewerHandler(exporter)
+//	...
+//	go http.ListenAndServe("localhost:8081", viewer)
+//
+// The last 100 traces are kept in memory, with up to 1000 spans each.
+func TraceViewerHandler(e Exporter) (http.Handler, error) {
+	p, ok := pipelineOf(e)
+	if !ok {
+		return nil, errors.New("unsupported exporter")
+	}
+	if !p.traceViewer {
+		return nil, errors.New("trace viewer not enabled, see WithTraceViewer")
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_ = viewerTemplate.Execute(w, p.viewer.waterfalls())
+	}), nil
+}
@@ -0,0 +1,62 @@
+package otel
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	oteltrace "go.opentelemetry.io/otel/trace"
+)
+
+func TestTraceViewerHandler_RendersWaterfalls(t *testing.T) {
+	exporter := NewExporter(Test, &Config{}, WithGlobalRegistration(false), WithTraceViewer())
+	pipeline, err := exporter.ExportPipeline(context.TODO())
+	assert.Nil(t, err)
+	defer pipeline.Shutdown(context.TODO())
+
+	tracer := pipeline.Tracer("sample")
+	ctx, root := tracer.Start(context.TODO(), "GET /orders", oteltrace.WithSpanKind(oteltrace.SpanKindServer))
+	_, child := tracer.Start(ctx, "SELECT orders", oteltrace.WithAttributes(attribute.String("db.system", "postgresql")))
+	child.SetStatus(codes.Error, "timeout")
+	child.End()
+	root.End()
+	_, other := tracer.Start(context.TODO(), "consume orders")
+	other.End()
+
+	handler, err := TraceViewerHandler(exporter)
+	assert.Nil(t, err)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	body := rec.Body.String()
+
+	assert.Contains(t, body, "2 recent traces")
+	assert.Contains(t, body, `<td style="padding-left: 1em">SELECT orders</td>`)
+	assert.Contains(t, body, `title="db.system=postgresql"`)
+	assert.Contains(t, body, "timeout")
+	assert.Less(t, strings.Index(body, "consume orders"), strings.Index(body, "GET /orders"))
+}
+
+func TestTraceBuffer_KeepsLatestTraces(t *testing.T) {
+	exporter := NewExporter(Test, &Config{}, WithGlobalRegistration(false), WithTraceViewer())
+	pipeline, err := exporter.ExportPipeline(context.TODO())
+	assert.Nil(t, err)
+	defer pipeline.Shutdown(context.TODO())
+
+	for i := 0; i < viewerMaxTraces+5; i++ {
+		_, span := pipeline.Tracer("sample").Start(context.TODO(), "sample span")
+		span.End()
+	}
+
+	p, _ := pipelineOf(exporter)
+	assert.Len(t, p.viewer.waterfalls(), viewerMaxTraces)
+}
+
+func TestTraceViewerHandler_NotEnabled(t *testing.T) {
+	_, err := TraceViewerHandler(NewExporter(IO, &Config{}, WithGlobalRegistration(false)))
+	assert.EqualError(t, err, "trace viewer not enabled, see WithTraceViewer")
+}
@@ -0,0 +1,67 @@
+package otel
+
+import (
+	"context"
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TraceIDHeader is the header carrying the trace ID of outgoing webhooks and
+// notifications, a plain value customers can quote to support.
+const TraceIDHeader = "X-Trace-Id"
+
+// TraceReference identifies the span a webhook or notification was sent from,
+// embed it in payloads so receipts can be matched with our traces:
+//
+//	type OrderShipped struct {
+//		OrderID string `json:"order_id"`
+//		otel.TraceReference
+//	}
+type TraceReference struct {
+	TraceID string `json:"trace_id,omitempty"`
+	SpanID  string `json:"span_id,omitempty"`
+}
+
+// NewTraceReference returns the reference of the span of ctx,
+// an empty one when ctx holds no valid span.
+func NewTraceReference(ctx context.Context) TraceReference {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return TraceReference{}
+	}
+
+	return TraceReference{
+		TraceID: sc.TraceID().String(),
+		SpanID:  sc.SpanID().String(),
+	}
+}
+
+// InjectTraceHeaders sets TraceIDHeader along with the headers of the
+// propagators registered by the pipeline, e.g. traceparent, on the headers of
+// an outgoing webhook. Mail headers can be passed as http.Header(mimeHeader).
+//
+// Nothing is set when ctx holds no valid span.
+func InjectTraceHeaders(ctx context.Context, header http.Header) {
+	ref := NewTraceReference(ctx)
+	if ref.TraceID == "" {
+		return
+	}
+
+	header.Set(TraceIDHeader, ref.TraceID)
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(header))
+}
+
+// InjectTracePayload adds the "trace_id" and "span_id" fields of the span of
+// ctx to a map payload, for payloads not declared as structs.
+func InjectTracePayload(ctx context.Context, payload map[string]interface{}) {
+	ref := NewTraceReference(ctx)
+	if ref.TraceID == "" {
+		return
+	}
+
+	payload["trace_id"] = ref.TraceID
+	payload["span_id"] = ref.SpanID
+}
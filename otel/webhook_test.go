@@ -0,0 +1,54 @@
+package otel
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/trace"
+)
+
+func TestInjectTraceHeaders_SetsTraceID(t *testing.T) {
+	previous := otel.GetTextMapPropagator()
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	defer otel.SetTextMapPropagator(previous)
+
+	ctx, span := trace.NewTracerProvider().Tracer("test").Start(context.Background(), "notify")
+	defer span.End()
+
+	header := http.Header{}
+	InjectTraceHeaders(ctx, header)
+
+	traceID := span.SpanContext().TraceID().String()
+	assert.Equal(t, traceID, header.Get(TraceIDHeader))
+	assert.Contains(t, header.Get("traceparent"), traceID)
+
+	empty := http.Header{}
+	InjectTraceHeaders(context.Background(), empty)
+	assert.Empty(t, empty)
+}
+
+func TestTraceReference_Payload(t *testing.T) {
+	ctx, span := trace.NewTracerProvider().Tracer("test").Start(context.Background(), "notify")
+	defer span.End()
+
+	payload := struct {
+		OrderID string `json:"order_id"`
+		TraceReference
+	}{OrderID: "42", TraceReference: NewTraceReference(ctx)}
+	body, err := json.Marshal(payload)
+	assert.Nil(t, err)
+
+	var fields map[string]interface{}
+	assert.Nil(t, json.Unmarshal(body, &fields))
+	assert.Equal(t, span.SpanContext().TraceID().String(), fields["trace_id"])
+	assert.Equal(t, span.SpanContext().SpanID().String(), fields["span_id"])
+
+	m := map[string]interface{}{"order_id": "42"}
+	InjectTracePayload(ctx, m)
+	assert.Equal(t, fields, m)
+}
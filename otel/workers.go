@@ -0,0 +1,108 @@
+package otel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/sdk/trace"
+)
+
+// workerExportTimeout bounds every export of the workers, the timeout of
+// the OTLP exporters.
+const workerExportTimeout = 30 * time.Second
+
+// workerExporter exports the batches of the batch processor with concurrent
+// workers, ExportSpans returning once a worker took the batch. Failed exports
+// are reported to the error handler as the batch processor would.
+type workerExporter struct {
+	trace.SpanExporter
+	batches chan []trace.ReadOnlySpan
+
+	mu      sync.RWMutex
+	closed  bool
+	pending sync.WaitGroup
+	workers sync.WaitGroup
+}
+
+// workerExporter wraps exp with the Config.ExportWorkers workers,
+// exp is returned as is for a single worker.
+func (c *Config) workerExporter(exp trace.SpanExporter) trace.SpanExporter {
+	if c.ExportWorkers <= 1 {
+		return exp
+	}
+
+	e := &workerExporter{SpanExporter: exp, batches: make(chan []trace.ReadOnlySpan)}
+	for range c.ExportWorkers {
+		e.workers.Go(e.run)
+	}
+
+	return e
+}
+
+func (e *workerExporter) run() {
+	for batch := range e.batches {
+		ctx, cancel := context.WithTimeout(context.Background(), workerExportTimeout)
+		if err := e.SpanExporter.ExportSpans(ctx, batch); err != nil {
+			handle(errorKindExport, err)
+		}
+		cancel()
+		e.pending.Done()
+	}
+}
+
+// ExportSpans implements the trace.SpanExporter interface, it waits for an
+// idle worker.
+func (e *workerExporter) ExportSpans(ctx context.Context, spans []trace.ReadOnlySpan) error {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if e.closed {
+		return errors.New("exporter shut down")
+	}
+
+	e.pending.Add(1)
+	select {
+	case e.batches <- slices.Clone(spans):
+		return nil
+	case <-ctx.Done():
+		e.pending.Done()
+		return ctx.Err()
+	}
+}
+
+// flush waits for the batches taken by the workers to be exported.
+func (e *workerExporter) flush(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		e.pending.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("could not wait for the export workers: %w", ctx.Err())
+	}
+}
+
+// Shutdown implements the trace.SpanExporter interface, the batches taken
+// by the workers are exported first.
+func (e *workerExporter) Shutdown(ctx context.Context) error {
+	e.mu.Lock()
+	if !e.closed {
+		e.closed = true
+		close(e.batches)
+	}
+	e.mu.Unlock()
+
+	if err := e.flush(ctx); err != nil {
+		return errors.Join(err, e.SpanExporter.Shutdown(ctx))
+	}
+	e.workers.Wait()
+
+	return e.SpanExporter.Shutdown(ctx)
+}
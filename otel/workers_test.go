@@ -0,0 +1,80 @@
+package otel
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// slowExporter takes delay to export and records the most concurrent exports.
+type slowExporter struct {
+	tracetest.InMemoryExporter
+	delay time.Duration
+
+	running    atomic.Int64
+	concurrent atomic.Int64
+	shutdown   atomic.Bool
+	mu         sync.Mutex
+}
+
+func (e *slowExporter) ExportSpans(ctx context.Context, spans []trace.ReadOnlySpan) error {
+	running := e.running.Add(1)
+	defer e.running.Add(-1)
+	e.mu.Lock()
+	e.concurrent.Store(max(e.concurrent.Load(), running))
+	e.mu.Unlock()
+
+	time.Sleep(e.delay)
+	return e.InMemoryExporter.ExportSpans(ctx, spans)
+}
+
+func (e *slowExporter) Shutdown(context.Context) error {
+	e.shutdown.Store(true)
+	return nil
+}
+
+func TestWorkerExporter_ExportsConcurrently(t *testing.T) {
+	exp := &slowExporter{delay: 20 * time.Millisecond}
+	workers := (&Config{ExportWorkers: 4}).workerExporter(exp).(*workerExporter)
+
+	batch := []trace.ReadOnlySpan{sampledSpan("sample span", codes.Unset)}
+	for range 8 {
+		assert.Nil(t, workers.ExportSpans(context.TODO(), batch))
+	}
+	assert.Nil(t, workers.flush(context.TODO()))
+	assert.Len(t, exp.GetSpans(), 8)
+	assert.Equal(t, int64(4), exp.concurrent.Load())
+
+	assert.Nil(t, workers.ExportSpans(context.TODO(), batch))
+	assert.Nil(t, workers.Shutdown(context.TODO()))
+	assert.True(t, exp.shutdown.Load())
+	assert.Len(t, exp.GetSpans(), 9)
+	assert.EqualError(t, workers.ExportSpans(context.TODO(), batch), "exporter shut down")
+}
+
+func TestWorkerExporter_SingleWorkerExportsInline(t *testing.T) {
+	exp := tracetest.NewInMemoryExporter()
+	assert.Equal(t, trace.SpanExporter(exp), (&Config{}).workerExporter(exp))
+}
+
+func TestExportWorkers_FlushWaitsForExports(t *testing.T) {
+	exp := &slowExporter{delay: 20 * time.Millisecond}
+	p := newPipeline(&Config{ExportWorkers: 2}, nil)
+	provider := trace.NewTracerProvider(p.exportOptions(exp)...)
+
+	for range 3 {
+		_, span := provider.Tracer("sample").Start(context.TODO(), "sample span")
+		span.End()
+		assert.Nil(t, provider.ForceFlush(context.TODO()))
+	}
+	assert.Len(t, exp.GetSpans(), 3)
+	assert.Nil(t, provider.Shutdown(context.TODO()))
+	assert.True(t, exp.shutdown.Load())
+}
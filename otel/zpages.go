@@ -0,0 +1,32 @@
+package otel
+
+import (
+	"errors"
+	"net/http"
+
+	"go.opentelemetry.io/contrib/zpages"
+)
+
+// ZPagesHandler returns the tracez page of the pipeline built by e with
+// WithZPages, listing per span name the running spans, the latency
+// distribution of the ended ones and the latest spans of each latency
+// bucket and in error, mount it on a debug mux:
+//
+//	tracez, err := otel.ZPagesHandler(exporter)
+//	...
+//	mux.Handle("/debug/tracez", tracez)
+//
+// Spans are collected in memory as they start and end, before sampling
+// drops them from export, so the page is current even when the backend
+// is slow to ingest.
+func ZPagesHandler(e Exporter) (http.Handler, error) {
+	p, ok := pipelineOf(e)
+	if !ok {
+		return nil, errors.New("unsupported exporter")
+	}
+	if !p.zpages {
+		return nil, errors.New("zpages not enabled, see WithZPages")
+	}
+
+	return zpages.NewTracezHandler(p.tracez), nil
+}
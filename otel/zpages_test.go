@@ -0,0 +1,39 @@
+package otel
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/codes"
+)
+
+func TestZPagesHandler_ListsSpans(t *testing.T) {
+	exporter := NewExporter(Test, &Config{}, WithGlobalRegistration(false), WithZPages())
+	pipeline, err := exporter.ExportPipeline(context.TODO())
+	assert.Nil(t, err)
+	defer pipeline.Shutdown(context.TODO())
+
+	_, running := pipeline.Tracer("sample").Start(context.TODO(), "consume orders")
+	defer running.End()
+	_, span := pipeline.Tracer("sample").Start(context.TODO(), "GET /orders")
+	span.SetStatus(codes.Error, "timeout")
+	span.End()
+
+	handler, err := ZPagesHandler(exporter)
+	assert.Nil(t, err)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/tracez", nil))
+	assert.Contains(t, rec.Body.String(), "consume orders")
+	assert.Contains(t, rec.Body.String(), "GET /orders")
+
+	snapshot, _ := Snapshot(exporter)
+	assert.Equal(t, []string{"debug", "counter", "zpages", "simple"}, snapshot.Processors)
+}
+
+func TestZPagesHandler_NotEnabled(t *testing.T) {
+	_, err := ZPagesHandler(NewExporter(IO, &Config{}, WithGlobalRegistration(false)))
+	assert.EqualError(t, err, "zpages not enabled, see WithZPages")
+}